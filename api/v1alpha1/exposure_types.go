@@ -0,0 +1,93 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// ExposureType is the kind of resource exposing a service outside the cluster.
+type ExposureType string
+
+const (
+	// ExposureTypeIngress routes requests for a host name and path prefix to the service with an Ingress.
+	ExposureTypeIngress ExposureType = "Ingress"
+	// ExposureTypeHTTPRoute routes requests for a host name and path prefix to the service with a Gateway API HTTPRoute.
+	ExposureTypeHTTPRoute ExposureType = "HTTPRoute"
+	// ExposureTypeLoadBalancer makes the service a LoadBalancer service.
+	ExposureTypeLoadBalancer ExposureType = "LoadBalancer"
+)
+
+// ExposureSpec defines how the service is exposed outside the cluster.
+type ExposureSpec struct {
+	// Type is the kind of resource exposing the service: an Ingress, a Gateway API HTTPRoute, or a LoadBalancer service.
+	// +kubebuilder:validation:Enum=Ingress;HTTPRoute;LoadBalancer
+	Type ExposureType `json:"type"`
+	// Hostname is the host name routed to the service. Required for Ingress and HTTPRoute.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// PathPrefix is the path prefix routed to the service, "/" by default. The HTTPRoute strips the prefix
+	// before forwarding the requests. Only supported for HTTPRoute, an Ingress routes all the paths of the host.
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// IngressClassName is the class of the Ingress, the default class of the cluster if not specified.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Gateway is the Gateway the HTTPRoute attaches to. Required for HTTPRoute.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+	// TLS configures the certificate of the Ingress. TLS of an HTTPRoute is terminated by the listener of the Gateway.
+	// +optional
+	TLS *ExposureTLSSpec `json:"tls,omitempty"`
+	// Internal only exposes the LoadBalancer service to the virtual network of the cluster, using the
+	// internal load balancer annotations configured for the controller.
+	// +optional
+	Internal bool `json:"internal,omitempty"`
+	// Annotations are added to the Ingress or HTTPRoute, e.g. to configure the ingress controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ServiceAnnotations are added to the service, e.g. to configure the load balancer of the cloud provider.
+	// They take precedence over the internal load balancer annotations.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+}
+
+// GatewayReference identifies the Gateway an HTTPRoute attaches to.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Gateway, the namespace of the HTTPRoute if not specified.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the listener of the Gateway to attach to, all listeners if not specified.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// ExposureTLSSpec configures the certificate of an Ingress.
+type ExposureTLSSpec struct {
+	// SecretName is the name of the Secret holding the certificate. When an issuer is set, cert-manager
+	// issues the certificate into this Secret, "<name>-tls" by default.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// Issuer is the cert-manager issuer of the certificate.
+	// +optional
+	Issuer *IssuerReference `json:"issuer,omitempty"`
+}
+
+// IssuerReference identifies a cert-manager issuer.
+type IssuerReference struct {
+	// Name is the name of the issuer.
+	Name string `json:"name"`
+	// Kind is the kind of the issuer, Issuer by default.
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
@@ -0,0 +1,90 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+// validate validates how the service is exposed. Only the fields of the exposure type may be set.
+func (e *ExposureSpec) validate() (errs *apis.FieldError) {
+	if e == nil {
+		return nil
+	}
+	unsupported := []struct {
+		field string
+		set   bool
+	}{
+		{"internal", e.Internal && e.Type != ExposureTypeLoadBalancer},
+		{"ingressClassName", e.IngressClassName != nil && e.Type != ExposureTypeIngress},
+		{"tls", e.TLS != nil && e.Type != ExposureTypeIngress},
+		{"gateway", e.Gateway != nil && e.Type != ExposureTypeHTTPRoute},
+		{"hostname", e.Hostname != "" && e.Type == ExposureTypeLoadBalancer},
+		// The prefix cannot be stripped by an Ingress without annotations specific to the ingress controller.
+		{"pathPrefix", e.PathPrefix != "" && e.Type != ExposureTypeHTTPRoute},
+		{"annotations", len(e.Annotations) > 0 && e.Type == ExposureTypeLoadBalancer},
+	}
+	for _, u := range unsupported {
+		if u.set {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("%s is not supported for type %s", u.field, e.Type), u.field))
+		}
+	}
+
+	switch e.Type {
+	case ExposureTypeIngress, ExposureTypeHTTPRoute:
+		if e.Hostname == "" {
+			errs = errs.Also(apis.ErrMissingField("hostname"))
+		} else if msgs := validation.IsDNS1123Subdomain(e.Hostname); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "hostname"))
+		}
+		if e.Type == ExposureTypeHTTPRoute && e.PathPrefix != "" && !strings.HasPrefix(e.PathPrefix, "/") {
+			errs = errs.Also(apis.ErrInvalidValue("pathPrefix must start with /", "pathPrefix"))
+		}
+	case ExposureTypeLoadBalancer:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(e.Type, "type"))
+	}
+
+	if e.Type == ExposureTypeHTTPRoute && (e.Gateway == nil || e.Gateway.Name == "") {
+		errs = errs.Also(apis.ErrMissingField("gateway.name"))
+	}
+	if e.TLS != nil && e.Type == ExposureTypeIngress {
+		errs = errs.Also(e.TLS.validate().ViaField("tls"))
+	}
+	return errs
+}
+
+func (t *ExposureTLSSpec) validate() (errs *apis.FieldError) {
+	if t.SecretName == "" && t.Issuer == nil {
+		errs = errs.Also(apis.ErrMissingOneOf("secretName", "issuer"))
+	}
+	if t.SecretName != "" {
+		if msgs := validation.IsDNS1123Subdomain(t.SecretName); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, ", "), "secretName"))
+		}
+	}
+	if t.Issuer != nil {
+		if t.Issuer.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("issuer.name"))
+		}
+		if t.Issuer.Kind != "" && t.Issuer.Kind != "Issuer" && t.Issuer.Kind != "ClusterIssuer" {
+			errs = errs.Also(apis.ErrInvalidValue(t.Issuer.Kind, "issuer.kind"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,30 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// NetworkPolicySpec restricts the traffic of the pods with a NetworkPolicy generated by KAITO.
+// The pods of the same resource can always reach each other.
+type NetworkPolicySpec struct {
+	// AllowedNamespaces are the names of the namespaces whose pods can reach the service, in addition to
+	// the namespace of the resource.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// AllowedNamespaceLabels selects further namespaces by label whose pods can reach the service.
+	// +optional
+	AllowedNamespaceLabels map[string]string `json:"allowedNamespaceLabels,omitempty"`
+	// AllowedPodLabels restricts the pods of the allowed namespaces that can reach the service to the pods
+	// with these labels. All pods of the allowed namespaces can reach the service if not specified.
+	// +optional
+	AllowedPodLabels map[string]string `json:"allowedPodLabels,omitempty"`
+}
@@ -0,0 +1,48 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+func (n *NetworkPolicySpec) validate() (errs *apis.FieldError) {
+	if n == nil {
+		return nil
+	}
+	for i, namespace := range n.AllowedNamespaces {
+		if msgs := validation.IsDNS1123Label(namespace); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidArrayValue(namespace, "allowedNamespaces", i))
+		}
+	}
+	errs = errs.Also(validateLabels(n.AllowedNamespaceLabels).ViaField("allowedNamespaceLabels"))
+	errs = errs.Also(validateLabels(n.AllowedPodLabels).ViaField("allowedPodLabels"))
+	return errs
+}
+
+func validateLabels(labels map[string]string) (errs *apis.FieldError) {
+	for k, v := range labels {
+		if msgs := validation.IsQualifiedName(k); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(k, apis.CurrentField, strings.Join(msgs, ", ")))
+		}
+		if msgs := validation.IsValidLabelValue(v); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s: %s", v, strings.Join(msgs, ", ")), k))
+		}
+	}
+	return errs
+}
@@ -46,6 +46,15 @@ type LocalEmbeddingSpec struct {
 	ModelAccessSecret string `json:"modelAccessSecret,omitempty"`
 }
 
+type WorkspaceEmbeddingSpec struct {
+	// Name is the name of a Workspace in the namespace of the RAG engine serving a text-embedding preset.
+	Name string `json:"name"`
+	// AccessSecret is the name of the secret that contains the API key of the RAG engine under the
+	// WORKSPACE_EMBEDDING_ACCESS_SECRET key, required if the workspace sets inference.auth.
+	// +optional
+	AccessSecret string `json:"accessSecret,omitempty"`
+}
+
 type EmbeddingSpec struct {
 	// Remote specifies how to generate embeddings for index data using a remote service.
 	// Note that either Remote or Local needs to be specified, not both.
@@ -54,6 +63,10 @@ type EmbeddingSpec struct {
 	// Local specifies how to generate embeddings for index data using a model run locally.
 	// +optional
 	Local *LocalEmbeddingSpec `json:"local,omitempty"`
+	// Workspace specifies the Kaito Workspace whose inference service generates embeddings for index data.
+	// Note that Workspace cannot be specified together with Remote or Local.
+	// +optional
+	Workspace *WorkspaceEmbeddingSpec `json:"workspace,omitempty"`
 }
 
 type InferenceServiceSpec struct {
@@ -85,6 +98,15 @@ type RAGEngineSpec struct {
 	// to generate embeddings. If not specified, a default service name will be created by the RAG engine.
 	// +optional
 	IndexServiceName string `json:"indexServiceName,omitempty"`
+	// Exposure exposes the service of the RAG engine outside the cluster with an Ingress, a Gateway API
+	// HTTPRoute or a LoadBalancer service. It takes precedence over the kaito.sh/enablelb annotation.
+	// +optional
+	Exposure *ExposureSpec `json:"exposure,omitempty"`
+	// NetworkPolicy restricts the traffic of the RAG engine pods with a NetworkPolicy: the service is only
+	// reachable from the allowed namespaces and pods, and the pods can only reach the inference service,
+	// the embedding service and DNS.
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
 }
 
 // RAGEngineStatus defines the observed state of RAGEngine
@@ -23,6 +23,7 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 
@@ -62,12 +63,15 @@ func (w *RAGEngine) validateCreate() (errs *apis.FieldError) {
 		errs = errs.Also(apis.ErrGeneric("Embedding must be specified", ""))
 		return errs
 	}
-	if w.Spec.Embedding.Local == nil && w.Spec.Embedding.Remote == nil {
+	if w.Spec.Embedding.Local == nil && w.Spec.Embedding.Remote == nil && w.Spec.Embedding.Workspace == nil {
 		errs = errs.Also(apis.ErrGeneric("Either remote embedding or local embedding must be specified, not neither", ""))
 	}
 	if w.Spec.Embedding.Local != nil && w.Spec.Embedding.Remote != nil {
 		errs = errs.Also(apis.ErrGeneric("Either remote embedding or local embedding must be specified, but not both", ""))
 	}
+	if w.Spec.Embedding.Workspace != nil && (w.Spec.Embedding.Local != nil || w.Spec.Embedding.Remote != nil) {
+		errs = errs.Also(apis.ErrGeneric("Workspace embedding cannot be specified together with remote or local embedding", ""))
+	}
 	errs = errs.Also(w.Spec.Compute.validateRAGCreate())
 	if w.Spec.Embedding.Local != nil {
 		w.Spec.Embedding.Local.validateCreate().ViaField("embedding")
@@ -75,6 +79,10 @@ func (w *RAGEngine) validateCreate() (errs *apis.FieldError) {
 	if w.Spec.Embedding.Remote != nil {
 		w.Spec.Embedding.Remote.validateCreate().ViaField("embedding")
 	}
+	if w.Spec.Embedding.Workspace != nil {
+		errs = errs.Also(w.Spec.Embedding.Workspace.validateCreate().ViaField("embedding"))
+	}
+	errs = errs.Also(w.Spec.Exposure.validate().ViaField("exposure"), w.Spec.NetworkPolicy.validate().ViaField("networkPolicy"))
 
 	return errs
 }
@@ -134,6 +142,18 @@ func (e *RemoteEmbeddingSpec) validateCreate() (errs *apis.FieldError) {
 	return errs
 }
 
+func (e *WorkspaceEmbeddingSpec) validateCreate() (errs *apis.FieldError) {
+	if msgs := validation.IsDNS1123Subdomain(e.Name); len(msgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, "; "), "workspace.name"))
+	}
+	if e.AccessSecret != "" {
+		if msgs := validation.IsDNS1123Subdomain(e.AccessSecret); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(msgs, "; "), "workspace.accessSecret"))
+		}
+	}
+	return errs
+}
+
 func (e *InferenceServiceSpec) validateCreate() (errs *apis.FieldError) {
 	_, err := url.ParseRequestURI(e.URL)
 	if err != nil {
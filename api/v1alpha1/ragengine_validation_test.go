@@ -105,6 +105,125 @@ func TestRAGEngineValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Only Workspace Embedding specified",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Workspace: &WorkspaceEmbeddingSpec{Name: "workspace-bge-small-en"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Workspace and Remote Embedding specified",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Remote:    &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"},
+						Workspace: &WorkspaceEmbeddingSpec{Name: "workspace-bge-small-en"},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "Workspace embedding cannot be specified together with remote or local embedding",
+		},
+		{
+			name: "Invalid Workspace name",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Workspace: &WorkspaceEmbeddingSpec{Name: "Workspace_BGE"},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "embedding.workspace.name",
+		},
+		{
+			name: "Invalid Workspace access secret",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Workspace: &WorkspaceEmbeddingSpec{Name: "workspace-bge-small-en", AccessSecret: "Embedding_Key"},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "embedding.workspace.accessSecret",
+		},
+		{
+			name: "Valid HTTPRoute exposure",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Remote: &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"},
+					},
+					Exposure: &ExposureSpec{
+						Type:       ExposureTypeHTTPRoute,
+						Hostname:   "rag.example.com",
+						PathPrefix: "/rag",
+						Gateway:    &GatewayReference{Name: "public", Namespace: "gateways"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid exposure hostname",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Remote: &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"},
+					},
+					Exposure: &ExposureSpec{Type: ExposureTypeIngress, Hostname: "RAG_Host"},
+				},
+			},
+			wantErr:  true,
+			errField: "exposure.hostname",
+		},
+		{
+			name: "Invalid network policy namespace label",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Remote: &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"},
+					},
+					NetworkPolicy: &NetworkPolicySpec{AllowedNamespaceLabels: map[string]string{"-team": "ml"}},
+				},
+			},
+			wantErr:  true,
+			errField: "networkPolicy.allowedNamespaceLabels",
+		},
 	}
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 	for _, tt := range tests {
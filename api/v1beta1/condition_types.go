@@ -23,6 +23,14 @@ const (
 	// ConditionTypeResourceStatus is the state when Resource has been created.
 	ConditionTypeResourceStatus = ConditionType("ResourceReady")
 
+	// ConditionTypeWaitingForNodes is the state when node auto provisioning is disabled and
+	// fewer qualified nodes exist than the workspace requires.
+	ConditionTypeWaitingForNodes = ConditionType("WaitingForNodes")
+
+	// ConditionTypeNodeReplaced is the state when an unhealthy or lost worker node has been removed
+	// from the workspace and its nodeClaim deleted so that a replacement node is provisioned.
+	ConditionTypeNodeReplaced = ConditionType("NodeReplaced")
+
 	// WorkspaceConditionTypeInferenceStatus is the state when Inference service has been ready.
 	WorkspaceConditionTypeInferenceStatus = ConditionType("InferenceReady")
 
@@ -31,14 +31,15 @@ import (
 
 // InferenceConfig represents the structure of the inference configuration
 type InferenceConfig struct {
-	VLLM map[string]string `yaml:"vllm"`
+	VLLM   map[string]string `yaml:"vllm"`
+	SGLang map[string]string `yaml:"sglang"`
 	// Other fields can be added as needed
 }
 
 func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.FieldError) {
-	// currently, this check only applies to vllm runtime
+	// currently, this check only applies to vllm and sglang runtimes
 	runtime := GetWorkspaceRuntimeName(w)
-	if runtime != model.RuntimeNameVLLM {
+	if runtime != model.RuntimeNameVLLM && runtime != model.RuntimeNameSGLang {
 		return nil
 	}
 
@@ -101,16 +102,23 @@ func (w *Workspace) validateInferenceConfig(ctx context.Context) (errs *apis.Fie
 			modelLenRequired = true
 		}
 	}
-	if w.Resource.Count != nil && *w.Resource.Count > 1 {
+	// vLLM distributes the model across multiple instances, SGLang runs one replica per instance.
+	if w.Resource.Count != nil && *w.Resource.Count > 1 && runtime == model.RuntimeNameVLLM {
 		modelLenRequired = true
 	}
 
-	if modelLenRequired {
+	if modelLenRequired && runtime == model.RuntimeNameVLLM {
 		maxModelLen, exists := inferenceConfig.VLLM["max-model-len"]
 		if !exists || maxModelLen == "" {
 			return apis.ErrMissingField("max-model-len is required in the vllm section of inference_config.yaml when using multi-GPU instances with <20GB of memory per GPU or distributed inference")
 		}
 	}
+	if modelLenRequired && runtime == model.RuntimeNameSGLang {
+		contextLen, exists := inferenceConfig.SGLang["context-length"]
+		if !exists || contextLen == "" {
+			return apis.ErrMissingField("context-length is required in the sglang section of inference_config.yaml when using multi-GPU instances with <20GB of memory per GPU")
+		}
+	}
 
 	return errs
 }
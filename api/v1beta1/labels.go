@@ -43,6 +43,31 @@ const (
 
 	// AnnotationBypassResourceChecks allows bypassing resource requirement checks like GPU memory.
 	AnnotationBypassResourceChecks = KAITOPrefix + "bypass-resource-checks"
+
+	// AnnotationNodeProvisioningMode selects how the GPU nodes of a workspace are obtained.
+	AnnotationNodeProvisioningMode = KAITOPrefix + "node-provisioning-mode"
+
+	// NodeProvisioningModeBYO makes the controller only use existing nodes that match the
+	// resource spec. No NodeClaims are created for the workspace.
+	NodeProvisioningModeBYO = "byo"
+
+	// LabelSharedNode marks the nodeClaims and nodes that are shared by workspaces with the "Shared" placement policy.
+	LabelSharedNode = KAITOPrefix + "shared-node"
+
+	// AnnotationSharedWorkspaces lists the workspaces placed on a shared nodeClaim as comma separated
+	// namespace/name keys. The nodeClaim is deleted when the list becomes empty.
+	AnnotationSharedWorkspaces = KAITOPrefix + "shared-workspaces"
+
+	// AnnotationEngineCacheRepository is the OCI repository where the engines compiled for the
+	// TensorRT-LLM runtime are cached, e.g. "myregistry.azurecr.io/kaito/engines".
+	AnnotationEngineCacheRepository = KAITOPrefix + "engine-cache-repository"
+
+	// AnnotationEngineCacheSecret is the docker config secret used to push engines to and pull
+	// engines from the engine cache repository.
+	AnnotationEngineCacheSecret = KAITOPrefix + "engine-cache-secret"
+
+	// AnnotationTensorRTLLMEngine records the engine reference on the engine build job of a workspace.
+	AnnotationTensorRTLLMEngine = KAITOPrefix + "tensorrt-llm-engine"
 )
 
 // GetWorkspaceRuntimeName returns the runtime name of the workspace.
@@ -51,6 +76,16 @@ func GetWorkspaceRuntimeName(ws *Workspace) model.RuntimeName {
 		panic("workspace is nil")
 	}
 
+	// SGLang, TensorRT-LLM and llama.cpp are opt-in and do not depend on the vLLM feature gate.
+	switch name := ws.Annotations[AnnotationWorkspaceRuntime]; name {
+	case string(model.RuntimeNameSGLang):
+		return model.RuntimeNameSGLang
+	case string(model.RuntimeNameLlamaCpp):
+		return model.RuntimeNameLlamaCpp
+	case string(model.RuntimeNameTensorRTLLM):
+		return model.RuntimeNameTensorRTLLM
+	}
+
 	if !featuregates.FeatureGates[consts.FeatureFlagVLLM] {
 		return model.RuntimeNameHuggingfaceTransformers
 	}
@@ -66,3 +101,17 @@ func GetWorkspaceRuntimeName(ws *Workspace) model.RuntimeName {
 
 	return runtime
 }
+
+// IsCPUInference returns true if the workspace serves its model on CPU nodes without GPUs.
+func IsCPUInference(ws *Workspace) bool {
+	return ws != nil && ws.Inference != nil && GetWorkspaceRuntimeName(ws) == model.RuntimeNameLlamaCpp
+}
+
+// IsNodeAutoProvisioningDisabled returns true if the controller must not create NodeClaims for the workspace,
+// either because auto provisioning is disabled for the whole controller or the workspace opts out of it.
+func IsNodeAutoProvisioningDisabled(ws *Workspace) bool {
+	if featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+		return true
+	}
+	return ws != nil && ws.Annotations[AnnotationNodeProvisioningMode] == NodeProvisioningModeBYO
+}
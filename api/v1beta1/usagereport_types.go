@@ -0,0 +1,82 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UsageReportSpec defines the period covered by a UsageReport.
+type UsageReportSpec struct {
+	// Start is the beginning of the period covered by the report.
+	Start metav1.Time `json:"start"`
+	// End is the end of the period covered by the report.
+	End metav1.Time `json:"end"`
+}
+
+// TokenUsage is the number of tokens processed for a client of a model served by a workspace.
+type TokenUsage struct {
+	// Workspace is the name of the workspace serving the model.
+	Workspace string `json:"workspace"`
+	// Model is the served name of the model.
+	Model string `json:"model"`
+	// Adapter is the name of the adapter of the model used by the requests, empty for the base model.
+	// +optional
+	Adapter string `json:"adapter,omitempty"`
+	// Client is the name of the API key of the requests, or "anonymous" if the workspace does not require API keys.
+	Client string `json:"client"`
+	// PromptTokens is the number of prompt tokens processed.
+	PromptTokens int64 `json:"promptTokens"`
+	// CompletionTokens is the number of completion tokens generated.
+	CompletionTokens int64 `json:"completionTokens"`
+}
+
+// UsageReportStatus defines the token usage recorded in the period of a UsageReport.
+type UsageReportStatus struct {
+	// Usage is the token usage of each workspace, model, adapter and client in the namespace.
+	// +optional
+	Usage []TokenUsage `json:"usage,omitempty"`
+	// LastUpdateTime is the last time the usage was updated.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// UsageReport is the token usage of the workspaces of a namespace over a period, rolled up by the workspace
+// controller from the metrics of the inference proxy. It is a lower bound of the usage, since the tokens served
+// by a pod since it was last collected are lost when the pod is deleted or the controller restarts.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=usagereports,scope=Namespaced,categories=workspace
+// +kubebuilder:printcolumn:name="Start",type="string",JSONPath=".spec.start",description=""
+// +kubebuilder:printcolumn:name="End",type="string",JSONPath=".spec.end",description=""
+// +kubebuilder:printcolumn:name="LastUpdate",type="date",JSONPath=".status.lastUpdateTime",description=""
+type UsageReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UsageReportSpec   `json:"spec,omitempty"`
+	Status UsageReportStatus `json:"status,omitempty"`
+}
+
+// UsageReportList contains a list of UsageReport
+// +kubebuilder:object:root=true
+type UsageReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UsageReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UsageReport{}, &UsageReportList{})
+}
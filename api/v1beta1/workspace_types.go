@@ -15,6 +15,7 @@ package v1beta1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,49 @@ type ResourceSpec struct {
 	// +kubebuilder:default:="Standard_NC24ads_A100_v4"
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// InstanceTypes is an optional list of fallback GPU node SKUs. If nodes of InstanceType cannot be
+	// provisioned because the capacity is unavailable, the controller retries with the next instance type
+	// in this list that satisfies the preset GPU count and memory requirements.
+	// +optional
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+
+	// InstanceTypePolicy determines the order in which the fallback InstanceTypes are tried.
+	// This field defaults to "Ordered" if not specified.
+	// +optional
+	InstanceTypePolicy InstanceTypePolicy `json:"instanceTypePolicy,omitempty"`
+
+	// CapacityType specifies the capacity type of the GPU nodes provisioned for the workload.
+	// Spot nodes are considerably cheaper but can be evicted by the cloud provider at any time.
+	// This field defaults to "on-demand" if not specified.
+	// +optional
+	CapacityType CapacityType `json:"capacityType,omitempty"`
+
+	// MinOnDemandCount is the number of nodes that are always provisioned with the on-demand
+	// capacity type when CapacityType is "spot". It keeps a floor of nodes that cannot be evicted.
+	// +optional
+	MinOnDemandCount *int `json:"minOnDemandCount,omitempty"`
+
+	// PlacementPolicy determines whether the workload gets dedicated GPU nodes or shares GPU nodes
+	// with other workspaces. With "Shared", the inference pod only requests the GPUs required by the
+	// preset and multiple workspaces are bin-packed onto shared nodes. A shared node is released
+	// when the last workspace using it is deleted.
+	// This field defaults to "Dedicated" if not specified.
+	// +optional
+	PlacementPolicy PlacementPolicy `json:"placementPolicy,omitempty"`
+
+	// GPUPartition requests a fraction of a GPU for the inference pod instead of whole GPUs, either
+	// a MIG instance or a time-sliced share of a GPU. The GPUs of the nodes must be partitioned
+	// accordingly by the nvidia device plugin, e.g. via the MIG manager or time-slicing config of
+	// the NVIDIA GPU operator.
+	// +optional
+	GPUPartition *GPUPartition `json:"gpuPartition,omitempty"`
+
+	// DynamicResourceAllocation requests the GPUs of the workload through a Dynamic Resource Allocation
+	// (DRA) ResourceClaimTemplate instead of device plugin resources such as nvidia.com/gpu. It requires
+	// a cluster with the resource.k8s.io/v1beta1 API and a DRA driver for the GPUs.
+	// +optional
+	DynamicResourceAllocation *DynamicResourceAllocation `json:"dynamicResourceAllocation,omitempty"`
+
 	// LabelSelector specifies the required labels for the GPU nodes.
 	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
 
@@ -45,6 +89,80 @@ type ResourceSpec struct {
 	PreferredNodes []string `json:"preferredNodes,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Ordered;FewestGPUs
+type InstanceTypePolicy string
+
+const (
+	// InstanceTypePolicyOrdered tries the fallback instance types in the order they are listed.
+	InstanceTypePolicyOrdered InstanceTypePolicy = "Ordered"
+	// InstanceTypePolicyFewestGPUs tries the fallback instance types with fewer GPUs per node first.
+	InstanceTypePolicyFewestGPUs InstanceTypePolicy = "FewestGPUs"
+)
+
+// +kubebuilder:validation:Enum=on-demand;spot
+type CapacityType string
+
+const (
+	// The values match the Karpenter `karpenter.sh/capacity-type` label values.
+	CapacityTypeOnDemand CapacityType = "on-demand"
+	CapacityTypeSpot     CapacityType = "spot"
+)
+
+// +kubebuilder:validation:Enum=Dedicated;Shared
+type PlacementPolicy string
+
+const (
+	// PlacementPolicyDedicated provisions GPU nodes for the exclusive use of the workspace.
+	PlacementPolicyDedicated PlacementPolicy = "Dedicated"
+	// PlacementPolicyShared places the workspace on GPU nodes shared with other workspaces.
+	PlacementPolicyShared PlacementPolicy = "Shared"
+)
+
+// GPUPartition describes the fraction of a GPU requested by a workload. Exactly one of MIGProfile
+// and TimeSlicingReplicas must be set.
+type GPUPartition struct {
+	// MIGProfile is the MIG profile requested by the inference pod, e.g. "1g.10gb". The pod requests
+	// one nvidia.com/mig-<profile> resource, which is advertised by the device plugin with the mixed
+	// MIG strategy.
+	// +optional
+	MIGProfile string `json:"migProfile,omitempty"`
+
+	// TimeSlicingReplicas is the number of replicas each GPU is time-sliced into by the device plugin.
+	// The pod requests one nvidia.com/gpu replica and the runtime is limited to the replica's share
+	// of the GPU memory, since time-slicing does not isolate GPU memory.
+	// +optional
+	TimeSlicingReplicas int `json:"timeSlicingReplicas,omitempty"`
+}
+
+// DynamicResourceAllocation describes the devices allocated to the workload by a DRA driver.
+type DynamicResourceAllocation struct {
+	// DeviceClassName is the DeviceClass of the requested GPUs.
+	// This field defaults to "gpu.nvidia.com", the DeviceClass of the NVIDIA DRA driver, if not specified.
+	// +kubebuilder:default:="gpu.nvidia.com"
+	// +optional
+	DeviceClassName string `json:"deviceClassName,omitempty"`
+
+	// ProductName selects devices whose "productName" attribute published by the DRA driver equals
+	// this value, e.g. "NVIDIA A100-SXM4-80GB".
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+
+	// MinMemory selects devices whose "memory" capacity published by the DRA driver is at least this value.
+	// +optional
+	MinMemory *resource.Quantity `json:"minMemory,omitempty"`
+}
+
+// DefaultDRADeviceClassName is the DeviceClass published by the NVIDIA DRA driver.
+const DefaultDRADeviceClassName = "gpu.nvidia.com"
+
+// GetDeviceClassName returns the DeviceClass of the requested GPUs, defaulting to DefaultDRADeviceClassName.
+func (d *DynamicResourceAllocation) GetDeviceClassName() string {
+	if d.DeviceClassName == "" {
+		return DefaultDRADeviceClassName
+	}
+	return d.DeviceClassName
+}
+
 type ModelName string
 
 // +kubebuilder:validation:Enum=public;private
@@ -102,6 +220,54 @@ type InferenceSpec struct {
 	// Users can specify multiple adapters for the model and the respective weight of using each of them.
 	// +optional
 	Adapters []AdapterSpec `json:"adapters,omitempty"`
+	// Auth requires clients of the inference service to present an API key. KAITO injects an
+	// authenticating proxy into the inference pods and the service targets the proxy.
+	// +optional
+	Auth *AuthSpec `json:"auth,omitempty"`
+	// RateLimit limits the requests of each client of the inference service, enforced by the proxy KAITO
+	// injects into the inference pods.
+	// +optional
+	RateLimit *RateLimitSpec `json:"rateLimit,omitempty"`
+	// Exposure exposes the inference service outside the cluster with an Ingress, a Gateway API HTTPRoute
+	// or a LoadBalancer service. It takes precedence over the kaito.sh/enablelb annotation.
+	// +optional
+	Exposure *ExposureSpec `json:"exposure,omitempty"`
+	// NetworkPolicy restricts the traffic to the inference pods with a NetworkPolicy: the inference service
+	// is only reachable from the allowed namespaces and pods, and the other ports, e.g. the Ray ports of
+	// multi-node inference, only from the pods of the workspace.
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// AdapterPlacement determines which replicas load each adapter. By default, every replica loads every
+	// adapter.
+	// +optional
+	AdapterPlacement *AdapterPlacementSpec `json:"adapterPlacement,omitempty"`
+}
+
+// AuthSpec configures the API keys accepted by the inference service.
+type AuthSpec struct {
+	// SecretName is the name of a Secret in the namespace of the workspace holding the API keys. Each key of
+	// the Secret names a client and its value is the API key of the client, which is sent as a bearer token
+	// in the Authorization header. Changes to the Secret are picked up without restarting the pods.
+	SecretName string `json:"secretName"`
+}
+
+// RateLimitSpec configures the limits of each client of the inference service. Clients are identified by their
+// API key when auth is enabled and by their IP address otherwise, in which case the clients behind a source NAT,
+// an ingress or the router share the limits of its IP address. Requests over a limit are rejected with a 429.
+// With the vLLM runtime, the requests served at the same time by a pod are also limited to the max-num-seqs of
+// the vllm section of the inference config, if set. The limits are enforced by each inference pod on its own,
+// they are not shared across the replicas of the workspace.
+type RateLimitSpec struct {
+	// RequestsPerMinute is the number of requests a client can send to an inference pod per minute. Each
+	// pod enforces the limit on its own, so a client can send up to this number times the number of pods.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestsPerMinute *int32 `json:"requestsPerMinute,omitempty"`
+	// MaxConcurrentRequests is the number of requests of a client an inference pod serves at the same time.
+	// Each pod enforces the limit on its own, so a client can send up to this number times the number of pods.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
 }
 
 type AdapterSpec struct {
@@ -114,6 +280,45 @@ type AdapterSpec struct {
 	Strength *string `json:"strength,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=All;Spread
+type AdapterPlacementPolicy string
+
+const (
+	// AdapterPlacementPolicyAll loads every adapter on every replica.
+	AdapterPlacementPolicyAll AdapterPlacementPolicy = "All"
+	// AdapterPlacementPolicySpread spreads the adapters across the replicas, so that the replicas together
+	// serve more adapters than a single replica can load.
+	AdapterPlacementPolicySpread AdapterPlacementPolicy = "Spread"
+)
+
+// AdapterPlacementSpec configures the placement of the adapters on the replicas of the inference workload.
+// With the Spread policy, the replicas are deployed as a StatefulSet so that each replica keeps its adapters,
+// and requests for an adapter must be sent through the KAITO router, which routes them to the replicas that
+// loaded the adapter. The placement is reported in the status of the workspace.
+type AdapterPlacementSpec struct {
+	// Policy is the placement policy of the adapters, All by default.
+	// +kubebuilder:default:="All"
+	// +optional
+	Policy AdapterPlacementPolicy `json:"policy,omitempty"`
+	// ReplicasPerAdapter is the number of replicas each adapter is loaded on with the Spread policy, 1 by default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicasPerAdapter *int32 `json:"replicasPerAdapter,omitempty"`
+}
+
+// IsSpread returns true if the adapters are spread across the replicas.
+func (a *AdapterPlacementSpec) IsSpread() bool {
+	return a != nil && a.Policy == AdapterPlacementPolicySpread
+}
+
+// GetReplicasPerAdapter returns the number of replicas each adapter is loaded on with the Spread policy.
+func (a *AdapterPlacementSpec) GetReplicasPerAdapter() int {
+	if a == nil || a.ReplicasPerAdapter == nil {
+		return 1
+	}
+	return int(*a.ReplicasPerAdapter)
+}
+
 type DataSource struct {
 	// The name of the dataset. The same name will be used as a container name.
 	// It must be a valid DNS subdomain value,
@@ -181,11 +386,35 @@ type WorkspaceStatus struct {
 	// +optional
 	WorkerNodes []string `json:"workerNodes,omitempty"`
 
+	// InstanceType is the fallback GPU node SKU used to provision nodes after the capacity of
+	// the requested instance type turned out to be unavailable.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// EmbeddingDimension is the dimension of the embedding vectors returned by the inference
+	// service of a text-embedding preset.
+	// +optional
+	EmbeddingDimension int32 `json:"embeddingDimension,omitempty"`
+
+	// AdapterPlacement lists the adapters loaded by each ready replica of the inference workload when the
+	// adapters are spread across the replicas.
+	// +optional
+	AdapterPlacement []ReplicaAdapters `json:"adapterPlacement,omitempty"`
+
 	// Conditions report the current conditions of the workspace.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ReplicaAdapters are the adapters loaded on a replica of the inference workload.
+type ReplicaAdapters struct {
+	// Pod is the name of the pod of the replica.
+	Pod string `json:"pod"`
+	// Adapters are the names of the adapters loaded on the replica.
+	// +optional
+	Adapters []string `json:"adapters,omitempty"`
+}
+
 // Workspace is the Schema for the workspaces API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
@@ -215,6 +444,30 @@ type WorkspaceList struct {
 	Items           []Workspace `json:"items"`
 }
 
+// ActiveInstanceType returns the instance type used to provision nodes and size the workload.
+// It is the fallback instance type recorded in the status if any, otherwise the requested one.
+func (w *Workspace) ActiveInstanceType() string {
+	if w.Status.InstanceType != "" {
+		return w.Status.InstanceType
+	}
+	return w.Resource.InstanceType
+}
+
+// IsSpot returns true if the workspace requests spot GPU nodes.
+func (w *Workspace) IsSpot() bool {
+	return w.Resource.CapacityType == CapacityTypeSpot
+}
+
+// UsesDynamicResourceAllocation returns true if the GPUs of the workspace are requested through a DRA ResourceClaimTemplate.
+func (w *Workspace) UsesDynamicResourceAllocation() bool {
+	return w.Resource.DynamicResourceAllocation != nil
+}
+
+// IsSharedPlacement returns true if the workspace shares GPU nodes with other workspaces.
+func (w *Workspace) IsSharedPlacement() bool {
+	return w.Resource.PlacementPolicy == PlacementPolicyShared
+}
+
 func init() {
 	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{})
 }
@@ -30,6 +30,7 @@ import (
 	"knative.dev/pkg/apis"
 
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
@@ -60,7 +61,14 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 	base := apis.GetBaseline(ctx)
 	if base == nil {
 		klog.InfoS("Validate creation", "workspace", fmt.Sprintf("%s/%s", w.Namespace, w.Name))
-		errs = errs.Also(w.validateCreate().ViaField("spec"))
+		errs = errs.Also(
+			w.validateCreate().ViaField("spec"),
+			w.Resource.validateInstanceTypes().ViaField("resource"),
+			w.Resource.validateCapacityType(w.Tuning).ViaField("resource"),
+			w.Resource.validatePlacementPolicy(w.Inference).ViaField("resource"),
+			w.Resource.validateGPUPartition(w.Inference, GetWorkspaceRuntimeName(w)).ViaField("resource"),
+			w.Resource.validateDynamicResourceAllocation().ViaField("resource"),
+		)
 		if w.Inference != nil {
 			// Check if the bypass resource checks annotation is set
 			bypassResourceChecks := false
@@ -74,6 +82,9 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 			// TODO: Add Adapter Spec Validation - Including DataSource Validation for Adapter
 			errs = errs.Also(
 				w.Resource.validateCreateWithInference(w.Inference, bypassResourceChecks, runtime).ViaField("resource"),
+				w.Resource.validateCPUInference(runtime).ViaField("resource"),
+				w.validateTensorRTLLM(runtime),
+				w.validateAdapterPlacement(runtime),
 				w.Inference.validateCreate(ctx, runtime).ViaField("inference"),
 				w.validateInferenceConfig(ctx),
 			)
@@ -91,7 +102,8 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 			w.Resource.validateUpdate(&old.Resource).ViaField("resource"),
 		)
 		if w.Inference != nil {
-			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"))
+			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"),
+				w.validateAdapterPlacement(GetWorkspaceRuntimeName(w)))
 		}
 		if w.Tuning != nil {
 			errs = errs.Also(w.Tuning.validateUpdate(old.Tuning).ViaField("tuning"))
@@ -316,9 +328,14 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec, byp
 		return errs
 	}
 
+	// llama.cpp runs on CPU nodes, any instance type is accepted and the GPU checks do not apply.
+	cpuInference := runtime == model.RuntimeNameLlamaCpp
+
 	// Check if instancetype exists in our SKUs map for the particular cloud provider
-	if skuConfig := skuHandler.GetGPUConfigBySKU(instanceType); skuConfig != nil {
-		if presetName != "" {
+	if skuConfig := skuHandler.GetGPUConfigBySKU(instanceType); skuConfig != nil && !cpuInference {
+		if presetName != "" && r.GPUPartition != nil {
+			errs = errs.Also(r.validateGPUPartitionFit(presetName, skuConfig, bypassResourceChecks))
+		} else if presetName != "" {
 			modelPreset := plugin.KaitoModelRegister.MustGet(presetName) // InferenceSpec has been validated so the name is valid.
 			params := modelPreset.GetInferenceParameters()
 
@@ -394,8 +411,20 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec, byp
 			if modelPreset.SupportDistributedInference() && distributedInferenceRequired && runtime == model.RuntimeNameHuggingfaceTransformers {
 				errs = errs.Also(apis.ErrGeneric("Multi-node distributed inference is not supported with Huggingface Transformers runtime"))
 			}
+			if distributedInferenceRequired && runtime == model.RuntimeNameSGLang {
+				errs = errs.Also(apis.ErrGeneric("Multi-node distributed inference is not supported with SGLang runtime"))
+			}
+			if runtime == model.RuntimeNameSGLang && skuConfig.Vendor() != sku.GPUVendorNvidia {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("SGLang runtime is only supported on nvidia GPUs, instance type %s has %s GPUs", instanceType, skuConfig.Vendor()), "instanceType"))
+			}
+			if distributedInferenceRequired && runtime == model.RuntimeNameTensorRTLLM {
+				errs = errs.Also(apis.ErrGeneric("Multi-node distributed inference is not supported with TensorRT-LLM runtime"))
+			}
+			if runtime == model.RuntimeNameTensorRTLLM && skuConfig.Vendor() != sku.GPUVendorNvidia {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("TensorRT-LLM runtime is only supported on nvidia GPUs, instance type %s has %s GPUs", instanceType, skuConfig.Vendor()), "instanceType"))
+			}
 		}
-	} else {
+	} else if !cpuInference {
 		provider := os.Getenv("CLOUD_PROVIDER")
 		// Check for other instance types pattern matches if cloud provider is Azure
 		if provider != consts.AzureCloudName || (!strings.HasPrefix(instanceType, N_SERIES_PREFIX) && !strings.HasPrefix(instanceType, D_SERIES_PREFIX)) {
@@ -403,6 +432,15 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec, byp
 		}
 	}
 
+	// Fallback instance types must satisfy the same preset requirements as the primary instance type.
+	for i, fallback := range r.InstanceTypes {
+		fallbackSpec := r.DeepCopy()
+		fallbackSpec.InstanceType = fallback
+		fallbackSpec.InstanceTypes = nil
+		fallbackSpec.LabelSelector = nil
+		errs = errs.Also(fallbackSpec.validateCreateWithInference(inference, bypassResourceChecks, runtime).ViaFieldIndex("instanceTypes", i))
+	}
+
 	// Validate labelSelector
 	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
 		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
@@ -411,6 +449,234 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec, byp
 	return errs
 }
 
+func (r *ResourceSpec) validateInstanceTypes() (errs *apis.FieldError) {
+	switch r.InstanceTypePolicy {
+	case "", InstanceTypePolicyOrdered, InstanceTypePolicyFewestGPUs:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type policy %s", r.InstanceTypePolicy), "instanceTypePolicy"))
+	}
+	seen := map[string]bool{r.InstanceType: true}
+	for i, instanceType := range r.InstanceTypes {
+		if seen[instanceType] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Duplicate instance type %s", instanceType), fmt.Sprintf("instanceTypes[%d]", i)))
+		}
+		seen[instanceType] = true
+	}
+	return errs
+}
+
+func (r *ResourceSpec) validateCapacityType(tuning *TuningSpec) (errs *apis.FieldError) {
+	switch r.CapacityType {
+	case "", CapacityTypeOnDemand, CapacityTypeSpot:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported capacity type %s", r.CapacityType), "capacityType"))
+	}
+	// A tuning job restarted after a spot eviction resumes from the checkpoints in its output, which are
+	// lost with the evicted node unless the output is a volume outliving the node.
+	if r.CapacityType == CapacityTypeSpot && tuning != nil {
+		if output := tuning.Output; output == nil || output.Volume == nil || output.Volume.EmptyDir != nil || output.Volume.HostPath != nil {
+			errs = errs.Also(apis.ErrGeneric("spot capacity for tuning requires the tuning output to be a volume outliving the node, e.g. a persistent volume claim", "capacityType"))
+		}
+	}
+	if r.MinOnDemandCount == nil {
+		return errs
+	}
+	if r.CapacityType != CapacityTypeSpot {
+		errs = errs.Also(apis.ErrGeneric("minOnDemandCount can only be set when capacityType is spot", "minOnDemandCount"))
+	}
+	if tuning != nil {
+		errs = errs.Also(apis.ErrGeneric("minOnDemandCount is not supported for tuning", "minOnDemandCount"))
+	}
+	if *r.MinOnDemandCount < 0 || (r.Count != nil && *r.MinOnDemandCount > *r.Count) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("minOnDemandCount %d must be between 0 and the node count", *r.MinOnDemandCount), "minOnDemandCount"))
+	}
+	return errs
+}
+
+func (r *ResourceSpec) validatePlacementPolicy(inference *InferenceSpec) (errs *apis.FieldError) {
+	switch r.PlacementPolicy {
+	case "", PlacementPolicyDedicated:
+		return errs
+	case PlacementPolicyShared:
+	default:
+		return errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported placement policy %s", r.PlacementPolicy), "placementPolicy"))
+	}
+	// The GPUs of a shared node are divided by the GPU count requirement of the presets.
+	if inference == nil || inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("shared placement is only supported for inference with a preset", "placementPolicy"))
+	}
+	if r.Count != nil && *r.Count != 1 {
+		errs = errs.Also(apis.ErrGeneric("shared placement requires a node count of 1", "count"))
+	}
+	if len(r.InstanceTypes) > 0 {
+		errs = errs.Also(apis.ErrGeneric("instanceTypes cannot be used with shared placement", "instanceTypes"))
+	}
+	if len(r.PreferredNodes) > 0 {
+		errs = errs.Also(apis.ErrGeneric("preferredNodes cannot be used with shared placement", "preferredNodes"))
+	}
+	if r.CapacityType == CapacityTypeSpot {
+		errs = errs.Also(apis.ErrGeneric("spot capacity cannot be used with shared placement", "capacityType"))
+	}
+	return errs
+}
+
+func (r *ResourceSpec) validateGPUPartition(inference *InferenceSpec, runtime model.RuntimeName) (errs *apis.FieldError) {
+	partition := r.GPUPartition
+	if partition == nil {
+		return errs
+	}
+	switch {
+	case partition.MIGProfile != "" && partition.TimeSlicingReplicas != 0:
+		errs = errs.Also(apis.ErrMultipleOneOf("gpuPartition.migProfile", "gpuPartition.timeSlicingReplicas"))
+	case partition.MIGProfile != "":
+		if _, err := sku.ParseMIGProfile(partition.MIGProfile); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "gpuPartition.migProfile"))
+		}
+	case partition.TimeSlicingReplicas == 0:
+		errs = errs.Also(apis.ErrMissingOneOf("gpuPartition.migProfile", "gpuPartition.timeSlicingReplicas"))
+	case partition.TimeSlicingReplicas < 2:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("timeSlicingReplicas %d must be at least 2", partition.TimeSlicingReplicas), "gpuPartition.timeSlicingReplicas"))
+	default:
+		// Time-slicing does not isolate GPU memory, only vLLM can be limited to the share of the replica.
+		if runtime != model.RuntimeNameVLLM {
+			errs = errs.Also(apis.ErrGeneric("time-slicing requires the vLLM runtime", "gpuPartition.timeSlicingReplicas"))
+		}
+	}
+
+	// The memory requirement of the preset is checked against the memory of the partition.
+	if inference == nil || inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("GPU partitions are only supported for inference with a preset", "gpuPartition"))
+	}
+	if r.Count != nil && *r.Count != 1 {
+		errs = errs.Also(apis.ErrGeneric("GPU partitions require a node count of 1", "count"))
+	}
+	if r.PlacementPolicy == PlacementPolicyShared {
+		errs = errs.Also(apis.ErrGeneric("GPU partitions cannot be used with shared placement", "placementPolicy"))
+	}
+	if gpuConfig, _ := utils.GetGPUConfigBySKU(r.InstanceType); gpuConfig != nil {
+		if gpuConfig.Vendor() != sku.GPUVendorNvidia {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("GPU partitions are only supported on nvidia GPUs, instance type %s has %s GPUs", r.InstanceType, gpuConfig.Vendor()), "gpuPartition"))
+		} else if memGB, err := sku.ParseMIGProfile(partition.MIGProfile); err == nil && memGB > gpuConfig.PerGPUMemGB() {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("MIG profile %s exceeds the %dGB memory of a GPU of instance type %s", partition.MIGProfile, gpuConfig.PerGPUMemGB(), r.InstanceType), "gpuPartition.migProfile"))
+		}
+	}
+	return errs
+}
+
+// validateCPUInference rejects the GPU specific resource settings for workspaces served on CPU nodes.
+func (r *ResourceSpec) validateCPUInference(runtime model.RuntimeName) (errs *apis.FieldError) {
+	if runtime != model.RuntimeNameLlamaCpp {
+		return errs
+	}
+	if r.GPUPartition != nil {
+		errs = errs.Also(apis.ErrGeneric("gpuPartition cannot be used with the llama.cpp runtime", "gpuPartition"))
+	}
+	if r.DynamicResourceAllocation != nil {
+		errs = errs.Also(apis.ErrGeneric("dynamicResourceAllocation cannot be used with the llama.cpp runtime", "dynamicResourceAllocation"))
+	}
+	if r.PlacementPolicy == PlacementPolicyShared {
+		errs = errs.Also(apis.ErrGeneric("shared placement is only supported on GPU nodes, not with the llama.cpp runtime", "placementPolicy"))
+	}
+	return errs
+}
+
+// validateTensorRTLLM checks the engine cache of the TensorRT-LLM runtime. Engines are compiled for a
+// GPU model, so the instance type must be known and the GPUs cannot be partitioned.
+func (w *Workspace) validateTensorRTLLM(runtime model.RuntimeName) (errs *apis.FieldError) {
+	if runtime != model.RuntimeNameTensorRTLLM {
+		return errs
+	}
+	repository, ok := w.Annotations[AnnotationEngineCacheRepository]
+	if !ok || repository == "" {
+		errs = errs.Also(apis.ErrMissingField(AnnotationEngineCacheRepository).ViaField("metadata", "annotations"))
+	} else if named, err := reference.ParseNormalizedNamed(repository); err != nil || !reference.IsNameOnly(named) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must be a repository without tag or digest", repository), AnnotationEngineCacheRepository).ViaField("metadata", "annotations"))
+	}
+	if w.Inference.Template != nil {
+		errs = errs.Also(apis.ErrGeneric("the TensorRT-LLM runtime requires a preset", "inference"))
+	}
+	if gpuConfig, _ := utils.GetGPUConfigBySKU(w.Resource.InstanceType); gpuConfig == nil {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("TensorRT-LLM engines are compiled for the GPU model of the instance type, the GPU model of instance type %s is unknown", w.Resource.InstanceType), "instanceType").ViaField("resource"))
+	}
+	if w.Resource.GPUPartition != nil {
+		errs = errs.Also(apis.ErrGeneric("gpuPartition cannot be used with the TensorRT-LLM runtime", "gpuPartition").ViaField("resource"))
+	}
+	if w.Resource.DynamicResourceAllocation != nil {
+		errs = errs.Also(apis.ErrGeneric("dynamicResourceAllocation cannot be used with the TensorRT-LLM runtime", "dynamicResourceAllocation").ViaField("resource"))
+	}
+	return errs
+}
+
+// validateAdapterPlacement validates the placement of the adapters on the replicas, which are the nodes of the
+// workspace. The adapters are spread by the vLLM runtime, each replica loading up to MaxAdaptersNumber adapters.
+func (w *Workspace) validateAdapterPlacement(runtime model.RuntimeName) (errs *apis.FieldError) {
+	placement := w.Inference.AdapterPlacement
+	if !placement.IsSpread() {
+		return errs
+	}
+	if w.Inference.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("the Spread adapter placement requires a preset", "policy").ViaField("inference", "adapterPlacement"))
+	}
+	if runtime != model.RuntimeNameVLLM {
+		errs = errs.Also(apis.ErrGeneric("the Spread adapter placement requires the vLLM runtime", "policy").ViaField("inference", "adapterPlacement"))
+	}
+	replicas := 1
+	if w.Resource.Count != nil {
+		replicas = *w.Resource.Count
+	}
+	replicasPerAdapter := placement.GetReplicasPerAdapter()
+	if replicasPerAdapter < 1 || replicasPerAdapter > replicas {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("replicasPerAdapter must be between 1 and the node count %d", replicas),
+			"replicasPerAdapter").ViaField("inference", "adapterPlacement"))
+	} else if perReplica := (len(w.Inference.Adapters)*replicasPerAdapter + replicas - 1) / replicas; perReplica > MaxAdaptersNumber {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Number of Adapters exceeds the maximum limit, maximum of %d per replica allowed, got %d",
+			MaxAdaptersNumber, perReplica), "adapters").ViaField("inference"))
+	}
+	return errs
+}
+
+func (r *ResourceSpec) validateDynamicResourceAllocation() (errs *apis.FieldError) {
+	dra := r.DynamicResourceAllocation
+	if dra == nil {
+		return errs
+	}
+	if dra.MinMemory != nil && dra.MinMemory.Sign() <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("minMemory %s must be positive", dra.MinMemory.String()), "dynamicResourceAllocation.minMemory"))
+	}
+	// GPU partitions are requested as device plugin resources, DRA drivers publish partitions as devices instead.
+	if r.GPUPartition != nil {
+		errs = errs.Also(apis.ErrGeneric("gpuPartition cannot be used with dynamicResourceAllocation", "gpuPartition"))
+	}
+	return errs
+}
+
+// validateGPUPartitionFit checks that the preset fits on a single GPU partition of the instance type.
+func (r *ResourceSpec) validateGPUPartitionFit(presetName string, skuConfig *sku.GPUConfig, bypassResourceChecks bool) (errs *apis.FieldError) {
+	params := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters()
+	partitionConfig := skuConfig.WithPartition(r.GPUPartition.MIGProfile, r.GPUPartition.TimeSlicingReplicas)
+	partitionMemory := resource.NewQuantity(int64(partitionConfig.PerGPUMemGB())*consts.GiBToBytes, resource.BinarySI)
+
+	modelGPUCount := resource.MustParse(params.GPUCountRequirement)
+	modelTotalGPUMemory := resource.MustParse(params.TotalGPUMemoryRequirement)
+
+	var messages []string
+	if modelGPUCount.Value() > 1 {
+		messages = append(messages, fmt.Sprintf("Insufficient number of GPUs: a GPU partition provides 1 GPU, but preset %s requires at least %d", presetName, modelGPUCount.Value()))
+	}
+	if partitionMemory.Cmp(modelTotalGPUMemory) < 0 {
+		messages = append(messages, fmt.Sprintf("Insufficient GPU partition memory: the GPU partition of instance type %s provides %s, but preset %s requires at least %s",
+			r.InstanceType, partitionMemory.String(), presetName, modelTotalGPUMemory.String()))
+	}
+	for _, message := range messages {
+		if bypassResourceChecks {
+			klog.Warningf("Bypassing resource check: %s", message)
+			continue
+		}
+		errs = errs.Also(apis.ErrInvalidValue(message, "gpuPartition"))
+	}
+	return errs
+}
+
 func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError) {
 	// We disable changing node count for now.
 	if r.Count != nil && old.Count != nil && *r.Count != *old.Count {
@@ -419,6 +685,24 @@ func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError)
 	if r.InstanceType != old.InstanceType {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "instanceType"))
 	}
+	if !reflect.DeepEqual(r.InstanceTypes, old.InstanceTypes) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "instanceTypes"))
+	}
+	if r.InstanceTypePolicy != old.InstanceTypePolicy {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "instanceTypePolicy"))
+	}
+	if r.CapacityType != old.CapacityType {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "capacityType"))
+	}
+	if r.PlacementPolicy != old.PlacementPolicy {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "placementPolicy"))
+	}
+	if !reflect.DeepEqual(r.GPUPartition, old.GPUPartition) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "gpuPartition"))
+	}
+	if !reflect.DeepEqual(r.DynamicResourceAllocation, old.DynamicResourceAllocation) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "dynamicResourceAllocation"))
+	}
 	newLabels, err0 := metav1.LabelSelectorAsMap(r.LabelSelector)
 	oldLabels, err1 := metav1.LabelSelectorAsMap(old.LabelSelector)
 	if err0 != nil || err1 != nil {
@@ -476,7 +760,8 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, runtime model.Runtim
 			errs = errs.Also(apis.ErrGeneric("This preset does not require a modelAccessSecret with HF_TOKEN key under presetOptions"))
 		}
 	}
-	if len(i.Adapters) > MaxAdaptersNumber {
+	// Spread adapters are limited per replica by validateAdapterPlacement.
+	if !i.AdapterPlacement.IsSpread() && len(i.Adapters) > MaxAdaptersNumber {
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Number of Adapters exceeds the maximum limit, maximum of %s allowed", strconv.Itoa(MaxAdaptersNumber))))
 	}
 
@@ -486,6 +771,8 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, runtime model.Runtim
 		errs = errs.Also(validateDuplicateName(i.Adapters, nameMap))
 	}
 
+	errs = errs.Also(i.validateAuth(), i.validateRateLimit(), i.Exposure.validate().ViaField("exposure"),
+		i.NetworkPolicy.validate().ViaField("networkPolicy"))
 	return errs
 }
 
@@ -493,6 +780,10 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 	if !reflect.DeepEqual(i.Preset, old.Preset) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "preset"))
 	}
+	// Switching the adapter placement policy changes the kind of the inference workload.
+	if i.AdapterPlacement.IsSpread() != old.AdapterPlacement.IsSpread() {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "policy").ViaField("adapterPlacement"))
+	}
 	// inference.template can be changed, but cannot be set/unset.
 	if (i.Template != nil && old.Template == nil) || (i.Template == nil && old.Template != nil) {
 		errs = errs.Also(apis.ErrGeneric("field cannot be unset/set if it was set/unset", "template"))
@@ -509,6 +800,43 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 		nameMap := make(map[string]bool)
 		errs = errs.Also(validateDuplicateName(i.Adapters, nameMap))
 	}
+
+	errs = errs.Also(i.validateAuth(), i.validateRateLimit(), i.Exposure.validate().ViaField("exposure"),
+		i.NetworkPolicy.validate().ViaField("networkPolicy"))
+	return errs
+}
+
+// validateAuth validates the API key authentication of the inference service. The authenticating proxy is
+// injected into the preset inference pods, so auth cannot be used with a custom template.
+func (i *InferenceSpec) validateAuth() (errs *apis.FieldError) {
+	if i.Auth == nil {
+		return nil
+	}
+	if i.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("auth is only supported with a preset", "auth"))
+	}
+	if i.Auth.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField("secretName").ViaField("auth"))
+	} else if errmsgs := validation.IsDNS1123Subdomain(i.Auth.SecretName); len(errmsgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "secretName").ViaField("auth"))
+	}
+	return errs
+}
+
+// validateRateLimit validates the limits enforced by the inference proxy, which like auth requires a preset.
+func (i *InferenceSpec) validateRateLimit() (errs *apis.FieldError) {
+	if i.RateLimit == nil {
+		return nil
+	}
+	if i.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("rateLimit is only supported with a preset", "rateLimit"))
+	}
+	if i.RateLimit.RequestsPerMinute != nil && *i.RateLimit.RequestsPerMinute < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*i.RateLimit.RequestsPerMinute, "requestsPerMinute").ViaField("rateLimit"))
+	}
+	if i.RateLimit.MaxConcurrentRequests != nil && *i.RateLimit.MaxConcurrentRequests < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*i.RateLimit.MaxConcurrentRequests, "maxConcurrentRequests").ViaField("rateLimit"))
+	}
 	return errs
 }
 
@@ -20,8 +20,10 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kaito-project/kaito/pkg/k8sclient"
@@ -339,6 +341,38 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 			validateTuning:      false,
 		},
 
+		{
+			name: "Valid Fallback Instance Types",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_ND96asr_v4",
+				InstanceTypes: []string{"Standard_ND96amsr_A100_v4"},
+				Count:         pointerToInt(1),
+			},
+			modelGPUCount:       "8",
+			modelPerGPUMemory:   "19Gi",
+			modelTotalGPUMemory: "152Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Insufficient Fallback Instance Type",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_ND96asr_v4",
+				InstanceTypes: []string{"Standard_NC6s_v3"},
+				Count:         pointerToInt(1),
+			},
+			modelGPUCount:       "8",
+			modelPerGPUMemory:   "19Gi",
+			modelTotalGPUMemory: "152Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			errContent:          "Insufficient number of GPUs: Instance type Standard_NC6s_v3",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
 		{
 			name: "Invalid SKU",
 			resourceSpec: &ResourceSpec{
@@ -442,6 +476,91 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 			expectErrs:         true,
 			errContent:         "Multi-node distributed inference is not supported with Huggingface Transformers runtime",
 		},
+		{
+			name: "SGLang + Distributed Inference",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC6s_v3",
+				Count:        pointerToInt(4),
+			},
+			preset:             true,
+			presetNameOverride: "test-validation-download",
+			runtime:            model.RuntimeNameSGLang,
+			expectErrs:         true,
+			errContent:         "Multi-node distributed inference is not supported with SGLang runtime",
+		},
+		{
+			name: "SGLang on AMD GPUs",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NG32ads_V620_v1",
+				Count:        pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameSGLang,
+			expectErrs:          true,
+			errContent:          "SGLang runtime is only supported on nvidia GPUs",
+		},
+		{
+			name: "GPU partition fits the model",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC24ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{MIGProfile: "2g.20gb"},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			expectErrs:          false,
+		},
+		{
+			name: "Model does not fit the MIG profile",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC24ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			expectErrs:          true,
+			errContent:          "Insufficient GPU partition memory",
+		},
+		{
+			name: "Model does not fit the time-sliced share",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC24ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{TimeSlicingReplicas: 8},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			expectErrs:          true,
+			errContent:          "Insufficient GPU partition memory",
+		},
+		{
+			name: "Multi-GPU model does not fit a GPU partition",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC96ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{MIGProfile: "7g.80gb"},
+			},
+			modelGPUCount:       "2",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "32Gi",
+			preset:              true,
+			runtime:             model.RuntimeNameVLLM,
+			expectErrs:          true,
+			errContent:          "a GPU partition provides 1 GPU",
+		},
 	}
 
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
@@ -494,91 +613,791 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 					t.Errorf("validateCreate() errors = %v, expectErrs %v", errs, tc.expectErrs)
 				}
 
-				// If there is an error and errContent is not empty, check that the error contains the expected content.
-				if hasErrs && tc.errContent != "" {
-					errMsg := errs.Error()
-					if !strings.Contains(errMsg, tc.errContent) {
-						t.Errorf("validateCreate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
-					}
+				// If there is an error and errContent is not empty, check that the error contains the expected content.
+				if hasErrs && tc.errContent != "" {
+					errMsg := errs.Error()
+					if !strings.Contains(errMsg, tc.errContent) {
+						t.Errorf("validateCreate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateUpdate(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		newResource *ResourceSpec
+		oldResource *ResourceSpec
+		errContent  string // Content expected error to include, if any
+		expectErrs  bool
+	}{
+		{
+			name: "Immutable GPUPartition",
+			newResource: &ResourceSpec{
+				GPUPartition: &GPUPartition{MIGProfile: "2g.20gb"},
+			},
+			oldResource: &ResourceSpec{
+				GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"},
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Immutable Count",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(10),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Immutable InstanceType",
+			newResource: &ResourceSpec{
+				InstanceType: "new_type",
+			},
+			oldResource: &ResourceSpec{
+				InstanceType: "old_type",
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Immutable InstanceTypes",
+			newResource: &ResourceSpec{
+				InstanceTypes: []string{"new_type"},
+			},
+			oldResource: &ResourceSpec{
+				InstanceTypes: []string{"old_type"},
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Immutable LabelSelector",
+			newResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key1": "value1"}},
+			},
+			oldResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key2": "value2"}},
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Update",
+			newResource: &ResourceSpec{
+				Count:         pointerToInt(5),
+				InstanceType:  "same_type",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
+			},
+			oldResource: &ResourceSpec{
+				Count:         pointerToInt(5),
+				InstanceType:  "same_type",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+	}
+
+	// Run the tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.newResource.validateUpdate(tc.oldResource)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			// If there is an error and errContent is not empty, check that the error contains the expected content.
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateUpdate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateInstanceTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceSpec *ResourceSpec
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
+	}{
+		{
+			name: "Valid fallback instance types",
+			resourceSpec: &ResourceSpec{
+				InstanceType:       "Standard_NC12s_v3",
+				InstanceTypes:      []string{"Standard_NC24s_v3", "Standard_NC6s_v3"},
+				InstanceTypePolicy: InstanceTypePolicyFewestGPUs,
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Unsupported instance type policy",
+			resourceSpec: &ResourceSpec{
+				InstanceType:       "Standard_NC12s_v3",
+				InstanceTypePolicy: "Cheapest",
+			},
+			errContent: "Unsupported instance type policy",
+			expectErrs: true,
+		},
+		{
+			name: "Fallback duplicates primary instance type",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC12s_v3",
+				InstanceTypes: []string{"Standard_NC12s_v3"},
+			},
+			errContent: "Duplicate instance type Standard_NC12s_v3",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.resourceSpec.validateInstanceTypes()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateInstanceTypes() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateInstanceTypes() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateCapacityType(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceSpec *ResourceSpec
+		tuning       *TuningSpec
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
+	}{
+		{
+			name: "Spot with on-demand floor",
+			resourceSpec: &ResourceSpec{
+				Count:            pointerToInt(3),
+				CapacityType:     CapacityTypeSpot,
+				MinOnDemandCount: pointerToInt(1),
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Spot tuning",
+			resourceSpec: &ResourceSpec{
+				Count:        pointerToInt(1),
+				CapacityType: CapacityTypeSpot,
+			},
+			tuning:     &TuningSpec{Output: &DataDestination{Volume: &v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"}}}},
+			expectErrs: false,
+		},
+		{
+			name: "Spot tuning pushing the output to an image",
+			resourceSpec: &ResourceSpec{
+				Count:        pointerToInt(1),
+				CapacityType: CapacityTypeSpot,
+			},
+			tuning:     &TuningSpec{Output: &DataDestination{Image: "registry.example.com/adapter:0.0.1", ImagePushSecret: "push-secret"}},
+			errContent: "spot capacity for tuning requires the tuning output to be a volume",
+			expectErrs: true,
+		},
+		{
+			name: "Spot tuning with the output in an empty dir",
+			resourceSpec: &ResourceSpec{
+				Count:        pointerToInt(1),
+				CapacityType: CapacityTypeSpot,
+			},
+			tuning:     &TuningSpec{Output: &DataDestination{Volume: &v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}},
+			errContent: "spot capacity for tuning requires the tuning output to be a volume",
+			expectErrs: true,
+		},
+		{
+			name: "Unsupported capacity type",
+			resourceSpec: &ResourceSpec{
+				Count:        pointerToInt(1),
+				CapacityType: "reserved",
+			},
+			errContent: "Unsupported capacity type",
+			expectErrs: true,
+		},
+		{
+			name: "On-demand floor without spot",
+			resourceSpec: &ResourceSpec{
+				Count:            pointerToInt(2),
+				MinOnDemandCount: pointerToInt(1),
+			},
+			errContent: "minOnDemandCount can only be set when capacityType is spot",
+			expectErrs: true,
+		},
+		{
+			name: "On-demand floor larger than node count",
+			resourceSpec: &ResourceSpec{
+				Count:            pointerToInt(2),
+				CapacityType:     CapacityTypeSpot,
+				MinOnDemandCount: pointerToInt(3),
+			},
+			errContent: "must be between 0 and the node count",
+			expectErrs: true,
+		},
+		{
+			name: "On-demand floor with tuning",
+			resourceSpec: &ResourceSpec{
+				Count:            pointerToInt(1),
+				CapacityType:     CapacityTypeSpot,
+				MinOnDemandCount: pointerToInt(1),
+			},
+			tuning:     &TuningSpec{Output: &DataDestination{Volume: &v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "checkpoints"}}}},
+			errContent: "minOnDemandCount is not supported for tuning",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.resourceSpec.validateCapacityType(tc.tuning)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateCapacityType() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateCapacityType() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidatePlacementPolicy(t *testing.T) {
+	presetInference := &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}}}
+	tests := []struct {
+		name         string
+		resourceSpec *ResourceSpec
+		inference    *InferenceSpec
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
+	}{
+		{
+			name:         "Dedicated placement",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(2), PlacementPolicy: PlacementPolicyDedicated},
+			expectErrs:   false,
+		},
+		{
+			name:         "Shared placement for preset inference",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), PlacementPolicy: PlacementPolicyShared},
+			inference:    presetInference,
+			expectErrs:   false,
+		},
+		{
+			name:         "Unsupported placement policy",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), PlacementPolicy: "Spread"},
+			errContent:   "Unsupported placement policy",
+			expectErrs:   true,
+		},
+		{
+			name:         "Shared placement without preset",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), PlacementPolicy: PlacementPolicyShared},
+			errContent:   "shared placement is only supported for inference with a preset",
+			expectErrs:   true,
+		},
+		{
+			name:         "Shared placement with multiple nodes",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(2), PlacementPolicy: PlacementPolicyShared},
+			inference:    presetInference,
+			errContent:   "shared placement requires a node count of 1",
+			expectErrs:   true,
+		},
+		{
+			name: "Shared placement with fallback instance types",
+			resourceSpec: &ResourceSpec{
+				Count:           pointerToInt(1),
+				PlacementPolicy: PlacementPolicyShared,
+				InstanceTypes:   []string{"Standard_NC6s_v3"},
+			},
+			inference:  presetInference,
+			errContent: "instanceTypes cannot be used with shared placement",
+			expectErrs: true,
+		},
+		{
+			name: "Shared placement with spot capacity",
+			resourceSpec: &ResourceSpec{
+				Count:           pointerToInt(1),
+				PlacementPolicy: PlacementPolicyShared,
+				CapacityType:    CapacityTypeSpot,
+			},
+			inference:  presetInference,
+			errContent: "spot capacity cannot be used with shared placement",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.resourceSpec.validatePlacementPolicy(tc.inference)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validatePlacementPolicy() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validatePlacementPolicy() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateGPUPartition(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	presetInference := &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}}}
+	tests := []struct {
+		name         string
+		resourceSpec *ResourceSpec
+		inference    *InferenceSpec
+		runtime      model.RuntimeName
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
+	}{
+		{
+			name:         "No GPU partition",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(2)},
+			expectErrs:   false,
+		},
+		{
+			name: "MIG profile",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC24ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"},
+			},
+			inference:  presetInference,
+			runtime:    model.RuntimeNameHuggingfaceTransformers,
+			expectErrs: false,
+		},
+		{
+			name: "Time-slicing",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC24ads_A100_v4",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{TimeSlicingReplicas: 4},
+			},
+			inference:  presetInference,
+			runtime:    model.RuntimeNameVLLM,
+			expectErrs: false,
+		},
+		{
+			name:         "Empty GPU partition",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "expected exactly one, got neither",
+			expectErrs:   true,
+		},
+		{
+			name:         "MIG profile and time-slicing",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{MIGProfile: "1g.10gb", TimeSlicingReplicas: 2}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "expected exactly one, got both",
+			expectErrs:   true,
+		},
+		{
+			name:         "Invalid MIG profile",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{MIGProfile: "mig-1g"}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "invalid MIG profile",
+			expectErrs:   true,
+		},
+		{
+			name:         "Single time-slicing replica",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{TimeSlicingReplicas: 1}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "must be at least 2",
+			expectErrs:   true,
+		},
+		{
+			name:         "Time-slicing with transformers runtime",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{TimeSlicingReplicas: 2}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameHuggingfaceTransformers,
+			errContent:   "time-slicing requires the vLLM runtime",
+			expectErrs:   true,
+		},
+		{
+			name:         "GPU partition without preset",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1), GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"}},
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "GPU partitions are only supported for inference with a preset",
+			expectErrs:   true,
+		},
+		{
+			name:         "GPU partition with multiple nodes",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(2), GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"}},
+			inference:    presetInference,
+			runtime:      model.RuntimeNameVLLM,
+			errContent:   "GPU partitions require a node count of 1",
+			expectErrs:   true,
+		},
+		{
+			name: "GPU partition with shared placement",
+			resourceSpec: &ResourceSpec{
+				Count:           pointerToInt(1),
+				PlacementPolicy: PlacementPolicyShared,
+				GPUPartition:    &GPUPartition{MIGProfile: "1g.10gb"},
+			},
+			inference:  presetInference,
+			runtime:    model.RuntimeNameVLLM,
+			errContent: "GPU partitions cannot be used with shared placement",
+			expectErrs: true,
+		},
+		{
+			name: "GPU partition on AMD GPUs",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NG32ads_V620_v1",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{TimeSlicingReplicas: 2},
+			},
+			inference:  presetInference,
+			runtime:    model.RuntimeNameVLLM,
+			errContent: "GPU partitions are only supported on nvidia GPUs",
+			expectErrs: true,
+		},
+		{
+			name: "MIG profile larger than the GPU",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC6s_v3",
+				Count:        pointerToInt(1),
+				GPUPartition: &GPUPartition{MIGProfile: "3g.40gb"},
+			},
+			inference:  presetInference,
+			runtime:    model.RuntimeNameVLLM,
+			errContent: "exceeds the 16GB memory of a GPU",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.resourceSpec.validateGPUPartition(tc.inference, tc.runtime)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateGPUPartition() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateGPUPartition() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateDynamicResourceAllocation(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceSpec *ResourceSpec
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
+	}{
+		{
+			name:         "No dynamic resource allocation",
+			resourceSpec: &ResourceSpec{Count: pointerToInt(1)},
+			expectErrs:   false,
+		},
+		{
+			name: "Dynamic resource allocation with selectors",
+			resourceSpec: &ResourceSpec{
+				Count: pointerToInt(2),
+				DynamicResourceAllocation: &DynamicResourceAllocation{
+					ProductName: "NVIDIA A100-SXM4-80GB",
+					MinMemory:   ptr.To(resource.MustParse("40Gi")),
+				},
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Non-positive min memory",
+			resourceSpec: &ResourceSpec{
+				Count:                     pointerToInt(1),
+				DynamicResourceAllocation: &DynamicResourceAllocation{MinMemory: ptr.To(resource.MustParse("0"))},
+			},
+			errContent: "minMemory 0 must be positive",
+			expectErrs: true,
+		},
+		{
+			name: "Dynamic resource allocation with GPU partition",
+			resourceSpec: &ResourceSpec{
+				Count:                     pointerToInt(1),
+				GPUPartition:              &GPUPartition{MIGProfile: "1g.10gb"},
+				DynamicResourceAllocation: &DynamicResourceAllocation{},
+			},
+			errContent: "gpuPartition cannot be used with dynamicResourceAllocation",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.resourceSpec.validateDynamicResourceAllocation()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateDynamicResourceAllocation() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateDynamicResourceAllocation() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateTensorRTLLM(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	newWorkspace := func(annotations map[string]string, resourceSpec ResourceSpec) *Workspace {
+		return &Workspace{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Resource:   resourceSpec,
+			Inference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation"}},
+			},
+		}
+	}
+	cacheAnnotations := map[string]string{AnnotationEngineCacheRepository: "myregistry.azurecr.io/kaito/engines"}
+
+	tests := []struct {
+		name       string
+		workspace  *Workspace
+		runtime    model.RuntimeName
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name:       "Other runtimes are not checked",
+			workspace:  newWorkspace(nil, ResourceSpec{InstanceType: "unknown"}),
+			runtime:    model.RuntimeNameVLLM,
+			expectErrs: false,
+		},
+		{
+			name:       "Valid engine cache",
+			workspace:  newWorkspace(cacheAnnotations, ResourceSpec{InstanceType: "Standard_NC12s_v3"}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			expectErrs: false,
+		},
+		{
+			name:       "Missing engine cache repository",
+			workspace:  newWorkspace(nil, ResourceSpec{InstanceType: "Standard_NC12s_v3"}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			errContent: "missing field(s): metadata.annotations.kaito.sh/engine-cache-repository",
+			expectErrs: true,
+		},
+		{
+			name:       "Engine cache repository with tag",
+			workspace:  newWorkspace(map[string]string{AnnotationEngineCacheRepository: "myregistry.azurecr.io/kaito/engines:latest"}, ResourceSpec{InstanceType: "Standard_NC12s_v3"}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			errContent: "must be a repository without tag or digest",
+			expectErrs: true,
+		},
+		{
+			name:       "Unknown instance type",
+			workspace:  newWorkspace(cacheAnnotations, ResourceSpec{InstanceType: "Standard_NC99_unknown"}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			errContent: "the GPU model of instance type Standard_NC99_unknown is unknown",
+			expectErrs: true,
+		},
+		{
+			name:       "GPU partition",
+			workspace:  newWorkspace(cacheAnnotations, ResourceSpec{InstanceType: "Standard_NC12s_v3", GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"}}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			errContent: "gpuPartition cannot be used with the TensorRT-LLM runtime",
+			expectErrs: true,
+		},
+		{
+			name:       "Dynamic resource allocation",
+			workspace:  newWorkspace(cacheAnnotations, ResourceSpec{InstanceType: "Standard_NC12s_v3", DynamicResourceAllocation: &DynamicResourceAllocation{}}),
+			runtime:    model.RuntimeNameTensorRTLLM,
+			errContent: "dynamicResourceAllocation cannot be used with the TensorRT-LLM runtime",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.workspace.validateTensorRTLLM(tc.runtime)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateTensorRTLLM() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateTensorRTLLM() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateAdapterPlacement(t *testing.T) {
+	newWorkspace := func(count int, placement *AdapterPlacementSpec, adapters int) *Workspace {
+		w := &Workspace{
+			Resource: ResourceSpec{Count: &count},
+			Inference: &InferenceSpec{
+				Preset:           &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation"}},
+				AdapterPlacement: placement,
+			},
+		}
+		for i := range adapters {
+			w.Inference.Adapters = append(w.Inference.Adapters, AdapterSpec{Source: &DataSource{Name: fmt.Sprintf("adapter-%d", i)}})
+		}
+		return w
+	}
+	spread := func(replicasPerAdapter int32) *AdapterPlacementSpec {
+		return &AdapterPlacementSpec{Policy: AdapterPlacementPolicySpread, ReplicasPerAdapter: &replicasPerAdapter}
+	}
+
+	tests := []struct {
+		name       string
+		workspace  *Workspace
+		runtime    model.RuntimeName
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name:       "All policy is not checked",
+			workspace:  newWorkspace(1, &AdapterPlacementSpec{Policy: AdapterPlacementPolicyAll}, 20),
+			runtime:    model.RuntimeNameHuggingfaceTransformers,
+			expectErrs: false,
+		},
+		{
+			name:       "Spread adapters over the replica limit of a single replica",
+			workspace:  newWorkspace(3, spread(1), 30),
+			runtime:    model.RuntimeNameVLLM,
+			expectErrs: false,
+		},
+		{
+			name:       "Too many adapters per replica",
+			workspace:  newWorkspace(3, spread(2), 20),
+			runtime:    model.RuntimeNameVLLM,
+			errContent: "maximum of 10 per replica allowed, got 14",
+			expectErrs: true,
+		},
+		{
+			name:       "More replicas per adapter than nodes",
+			workspace:  newWorkspace(2, spread(3), 2),
+			runtime:    model.RuntimeNameVLLM,
+			errContent: "replicasPerAdapter must be between 1 and the node count 2",
+			expectErrs: true,
+		},
+		{
+			name:       "Transformers runtime",
+			workspace:  newWorkspace(2, spread(1), 2),
+			runtime:    model.RuntimeNameHuggingfaceTransformers,
+			errContent: "the Spread adapter placement requires the vLLM runtime",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.workspace.validateAdapterPlacement(tc.runtime)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateAdapterPlacement() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateAdapterPlacement() error message = %v, expected to contain = %v", errMsg, tc.errContent)
 				}
 			}
 		})
 	}
 }
 
-func TestResourceSpecValidateUpdate(t *testing.T) {
-
+func TestResourceSpecValidateCPUInference(t *testing.T) {
 	tests := []struct {
-		name        string
-		newResource *ResourceSpec
-		oldResource *ResourceSpec
-		errContent  string // Content expected error to include, if any
-		expectErrs  bool
+		name         string
+		resourceSpec *ResourceSpec
+		runtime      model.RuntimeName
+		errContent   string // Content expected error to include, if any
+		expectErrs   bool
 	}{
 		{
-			name: "Immutable Count",
-			newResource: &ResourceSpec{
-				Count: pointerToInt(10),
-			},
-			oldResource: &ResourceSpec{
-				Count: pointerToInt(5),
-			},
-			errContent: "field is immutable",
-			expectErrs: true,
+			name:         "GPU runtime is not checked",
+			resourceSpec: &ResourceSpec{GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"}},
+			runtime:      model.RuntimeNameVLLM,
+			expectErrs:   false,
 		},
 		{
-			name: "Immutable InstanceType",
-			newResource: &ResourceSpec{
-				InstanceType: "new_type",
-			},
-			oldResource: &ResourceSpec{
-				InstanceType: "old_type",
-			},
-			errContent: "field is immutable",
-			expectErrs: true,
+			name:         "CPU instance type",
+			resourceSpec: &ResourceSpec{InstanceType: "Standard_D8s_v5", Count: pointerToInt(2)},
+			runtime:      model.RuntimeNameLlamaCpp,
+			expectErrs:   false,
 		},
 		{
-			name: "Immutable LabelSelector",
-			newResource: &ResourceSpec{
-				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key1": "value1"}},
-			},
-			oldResource: &ResourceSpec{
-				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key2": "value2"}},
-			},
-			errContent: "field is immutable",
-			expectErrs: true,
+			name:         "GPU partition",
+			resourceSpec: &ResourceSpec{GPUPartition: &GPUPartition{MIGProfile: "1g.10gb"}},
+			runtime:      model.RuntimeNameLlamaCpp,
+			errContent:   "gpuPartition cannot be used with the llama.cpp runtime",
+			expectErrs:   true,
 		},
 		{
-			name: "Valid Update",
-			newResource: &ResourceSpec{
-				Count:         pointerToInt(5),
-				InstanceType:  "same_type",
-				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
-			},
-			oldResource: &ResourceSpec{
-				Count:         pointerToInt(5),
-				InstanceType:  "same_type",
-				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
-			},
-			errContent: "",
-			expectErrs: false,
+			name:         "Dynamic resource allocation",
+			resourceSpec: &ResourceSpec{DynamicResourceAllocation: &DynamicResourceAllocation{}},
+			runtime:      model.RuntimeNameLlamaCpp,
+			errContent:   "dynamicResourceAllocation cannot be used with the llama.cpp runtime",
+			expectErrs:   true,
+		},
+		{
+			name:         "Shared placement",
+			resourceSpec: &ResourceSpec{PlacementPolicy: PlacementPolicyShared},
+			runtime:      model.RuntimeNameLlamaCpp,
+			errContent:   "shared placement is only supported on GPU nodes",
+			expectErrs:   true,
 		},
 	}
 
-	// Run the tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			errs := tc.newResource.validateUpdate(tc.oldResource)
+			errs := tc.resourceSpec.validateCPUInference(tc.runtime)
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
-				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+				t.Errorf("validateCPUInference() errors = %v, expectErrs %v", errs, tc.expectErrs)
 			}
 
-			// If there is an error and errContent is not empty, check that the error contains the expected content.
 			if hasErrs && tc.errContent != "" {
 				errMsg := errs.Error()
 				if !strings.Contains(errMsg, tc.errContent) {
-					t.Errorf("validateUpdate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+					t.Errorf("validateCPUInference() error message = %v, expected to contain = %v", errMsg, tc.errContent)
 				}
 			}
 		})
@@ -1008,6 +1827,15 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Adapter Placement Policy Immutable",
+			newInference: &InferenceSpec{
+				AdapterPlacement: &AdapterPlacementSpec{Policy: AdapterPlacementPolicySpread},
+			},
+			oldInference: &InferenceSpec{},
+			errContent:   "field is immutable: adapterPlacement.policy",
+			expectErrs:   true,
+		},
 		{
 			name: "Template Unset",
 			newInference: &InferenceSpec{
@@ -1066,6 +1894,197 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 			errContent: "",
 			expectErrs: false,
 		},
+		{
+			name: "Enable Auth",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Auth:   &AuthSpec{SecretName: "api-keys"},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Auth Without Secret Name",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Auth:   &AuthSpec{},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "missing field(s): auth.secretName",
+			expectErrs: true,
+		},
+		{
+			name: "Auth Invalid Secret Name",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Auth:   &AuthSpec{SecretName: "API_KEYS"},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "auth.secretName",
+			expectErrs: true,
+		},
+		{
+			name: "Auth With Template",
+			newInference: &InferenceSpec{
+				Template: &v1.PodTemplateSpec{},
+				Auth:     &AuthSpec{SecretName: "api-keys"},
+			},
+			oldInference: &InferenceSpec{
+				Template: &v1.PodTemplateSpec{},
+			},
+			errContent: "auth is only supported with a preset",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Rate Limit",
+			newInference: &InferenceSpec{
+				Preset:    &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				RateLimit: &RateLimitSpec{RequestsPerMinute: ptr.To[int32](60), MaxConcurrentRequests: ptr.To[int32](4)},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Invalid Rate Limit",
+			newInference: &InferenceSpec{
+				Preset:    &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				RateLimit: &RateLimitSpec{RequestsPerMinute: ptr.To[int32](0)},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "rateLimit.requestsPerMinute",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Ingress Exposure",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{
+					Type:     ExposureTypeIngress,
+					Hostname: "phi.example.com",
+					TLS:      &ExposureTLSSpec{Issuer: &IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"}},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Ingress Exposure Without Hostname",
+			newInference: &InferenceSpec{
+				Preset:   &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{Type: ExposureTypeIngress},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "exposure.hostname",
+			expectErrs: true,
+		},
+		{
+			name: "Ingress Exposure With Path Prefix",
+			newInference: &InferenceSpec{
+				Preset:   &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{Type: ExposureTypeIngress, Hostname: "phi.example.com", PathPrefix: "/v1"},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "pathPrefix is not supported for type Ingress",
+			expectErrs: true,
+		},
+		{
+			name: "HTTPRoute Exposure Without Gateway",
+			newInference: &InferenceSpec{
+				Preset:   &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{Type: ExposureTypeHTTPRoute, Hostname: "phi.example.com"},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "exposure.gateway.name",
+			expectErrs: true,
+		},
+		{
+			name: "Internal Load Balancer Exposure",
+			newInference: &InferenceSpec{
+				Preset:   &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{Type: ExposureTypeLoadBalancer, Internal: true},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Load Balancer Exposure With TLS",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Exposure: &ExposureSpec{
+					Type: ExposureTypeLoadBalancer,
+					TLS:  &ExposureTLSSpec{SecretName: "phi-tls"},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "tls is not supported for type LoadBalancer",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Network Policy",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				NetworkPolicy: &NetworkPolicySpec{
+					AllowedNamespaces:      []string{"apps"},
+					AllowedNamespaceLabels: map[string]string{"team": "ml"},
+					AllowedPodLabels:       map[string]string{"app.kubernetes.io/name": "chat"},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "Network Policy Invalid Namespace",
+			newInference: &InferenceSpec{
+				Preset:        &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				NetworkPolicy: &NetworkPolicySpec{AllowedNamespaces: []string{"Apps_NS"}},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "networkPolicy.allowedNamespaces[0]",
+			expectErrs: true,
+		},
+		{
+			name: "Network Policy Invalid Pod Label",
+			newInference: &InferenceSpec{
+				Preset:        &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				NetworkPolicy: &NetworkPolicySpec{AllowedPodLabels: map[string]string{"role": "not a value"}},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+			},
+			errContent: "networkPolicy.allowedPodLabels.role",
+			expectErrs: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -1840,6 +2859,19 @@ vllm:
 			Data: map[string]string{
 				"inference_config.yaml": `
 vllm: {}
+`,
+			},
+		},
+		// ConfigMap with context-length in the sglang section
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "valid-config-with-context-length",
+				Namespace: DefaultReleaseNamespace,
+			},
+			Data: map[string]string{
+				"inference_config.yaml": `
+sglang:
+  context-length: 2048
 `,
 			},
 		},
@@ -1952,6 +2984,52 @@ other_field: value
 			errContent: "max-model-len is required in the vllm section of inference_config.yaml when using multi-GPU instances with <20GB of memory per GPU",
 			expectErrs: true,
 		},
+		{
+			name: "SGLang, Multi-GPU with <20GB per GPU and context-length set",
+			workspace: &Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   DefaultReleaseNamespace,
+					Annotations: map[string]string{AnnotationWorkspaceRuntime: string(model.RuntimeNameSGLang)},
+				},
+				Inference: &InferenceSpec{
+					Preset: &PresetSpec{
+						PresetMeta: PresetMeta{
+							Name: ModelName("test-validation"),
+						},
+					},
+					Config: "valid-config-with-context-length",
+				},
+				Resource: ResourceSpec{
+					InstanceType: "Standard_NV12", // 2 GPUs with 8GB each (16GB total)
+					Count:        pointerToInt(1),
+				},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "SGLang, Multi-GPU with <20GB per GPU and context-length missing",
+			workspace: &Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   DefaultReleaseNamespace,
+					Annotations: map[string]string{AnnotationWorkspaceRuntime: string(model.RuntimeNameSGLang)},
+				},
+				Inference: &InferenceSpec{
+					Preset: &PresetSpec{
+						PresetMeta: PresetMeta{
+							Name: ModelName("test-validation"),
+						},
+					},
+					Config: "valid-config-with-max-model-len",
+				},
+				Resource: ResourceSpec{
+					InstanceType: "Standard_NV12", // 2 GPUs with 8GB each (16GB total)
+					Count:        pointerToInt(1),
+				},
+			},
+			errContent: "context-length is required in the sglang section of inference_config.yaml",
+			expectErrs: true,
+		},
 		{
 			name: "Single Instance, Single-GPU (no max-model-len required)",
 			workspace: &Workspace{
@@ -51,6 +51,14 @@ app.kubernetes.io/name: {{ include "kaito.name" . }}
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
 
+{{/*
+Router selector labels
+*/}}
+{{- define "kaito.routerSelectorLabels" -}}
+app.kubernetes.io/name: {{ include "kaito.name" . }}-router
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
+
 {{/*
 Utils function
 */}}
@@ -0,0 +1,122 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+
+	"github.com/kaito-project/kaito/pkg/inferenceproxy"
+)
+
+func main() {
+	var bindAddr string
+	var metricsAddr string
+	var upstream string
+	var apiKeysDir string
+	var limits inferenceproxy.Limits
+	var inferenceConfig string
+	var metering inferenceproxy.Metering
+	var adapterListDir string
+	var adapterDir string
+	var adapterSyncInterval time.Duration
+	flag.StringVar(&bindAddr, "bind-address", ":5001", "The address the proxy binds to.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":5002", "The address the metric endpoint and the loaded adapters bind to.")
+	flag.StringVar(&upstream, "upstream", "http://127.0.0.1:5000", "The URL of the inference server.")
+	flag.StringVar(&apiKeysDir, "api-keys-dir", "", "The directory of the mounted API keys Secret. Authentication is disabled if empty.")
+	flag.IntVar(&limits.RequestsPerMinute, "requests-per-minute", 0, "The maximum number of requests per minute of each client. Unlimited if 0.")
+	flag.IntVar(&limits.MaxConcurrentRequests, "max-concurrent-requests", 0, "The maximum number of in-flight requests of each client. Unlimited if 0.")
+	flag.StringVar(&inferenceConfig, "inference-config", "", "The inference config file, the max-num-seqs of vLLM in it caps the in-flight requests of all clients.")
+	flag.StringVar(&metering.Workspace, "workspace", "", "The name of the workspace, to label the token usage.")
+	flag.StringVar(&metering.Model, "model", "", "The served model name of the inference server, to label the token usage.")
+	flag.StringVar(&adapterListDir, "adapter-list-dir", "", "The directory of the mounted adapter ConfigMap. Adapters are not loaded if empty.")
+	flag.StringVar(&adapterDir, "adapter-dir", "/mnt/adapter", "The directory the adapters are pulled into.")
+	flag.DurationVar(&adapterSyncInterval, "adapter-sync-interval", 10*time.Second, "The interval at which the loaded adapters are synced with the adapter list.")
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	upstreamURL, err := url.Parse(upstream)
+	if err != nil {
+		klog.ErrorS(err, "invalid upstream URL", "upstream", upstream)
+		os.Exit(1)
+	}
+
+	if inferenceConfig != "" {
+		maxNumSeqs, err := inferenceproxy.MaxNumSeqsFromConfig(inferenceConfig)
+		if err != nil {
+			klog.ErrorS(err, "unable to read max-num-seqs, in-flight requests of all clients are not capped", "path", inferenceConfig)
+		}
+		limits.MaxConcurrentRequestsTotal = maxNumSeqs
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	var keys *inferenceproxy.APIKeyStore
+	if apiKeysDir != "" {
+		keys = inferenceproxy.NewAPIKeyStore(apiKeysDir)
+		if err := keys.Load(); err != nil {
+			klog.ErrorS(err, "unable to load API keys")
+			os.Exit(1)
+		}
+		go keys.Run(ctx, 30*time.Second)
+	}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.HandlerFor(inferenceproxy.Registry, promhttp.HandlerOpts{}))
+	if adapterListDir != "" {
+		// The replicas spreading the adapters get their index from the downward API.
+		loader := inferenceproxy.NewAdapterLoader(upstreamURL, adapterListDir, adapterDir, os.Getenv("POD_INDEX"))
+		go loader.Run(ctx, adapterSyncInterval)
+		metricsMux.Handle(inferenceproxy.AdaptersPath, loader)
+	}
+	servers := []*http.Server{
+		{Addr: bindAddr, Handler: inferenceproxy.NewProxy(upstreamURL, keys, limits, metering), ReadHeaderTimeout: 10 * time.Second},
+		{Addr: metricsAddr, Handler: metricsMux, ReadHeaderTimeout: 10 * time.Second},
+	}
+	errCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *http.Server) {
+			klog.InfoS("starting server", "address", server.Addr)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(server)
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		klog.ErrorS(err, "problem running server")
+		os.Exit(1)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.ErrorS(err, "failed to shut down server", "address", server.Addr)
+		}
+	}
+}
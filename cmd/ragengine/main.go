@@ -45,6 +45,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/ragengine/controllers"
 	"github.com/kaito-project/kaito/pkg/ragengine/webhooks"
 	kaitoutils "github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers/garbagecollect"
 )
 
 const (
@@ -78,6 +79,8 @@ func main() {
 	var enableWebhook bool
 	var probeAddr string
 	var featureGates string
+	var nodeClaimGCGracePeriod time.Duration
+	var nodeClaimGCDryRun bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -86,6 +89,10 @@ func main() {
 	flag.BoolVar(&enableWebhook, "webhook", true,
 		"Enable webhook for controller manager. Default is true.")
 	flag.StringVar(&featureGates, "feature-gates", "vLLM=true", "Enable Kaito feature gates. Default,	vLLM=true.")
+	flag.DurationVar(&nodeClaimGCGracePeriod, "nodeclaim-gc-grace-period", garbagecollect.DefaultNodeClaimGCGracePeriod,
+		"The time a nodeClaim can stay orphaned after its RAGEngine is gone before it is deleted.")
+	flag.BoolVar(&nodeClaimGCDryRun, "nodeclaim-gc-dry-run", false,
+		"Only report orphaned nodeClaims instead of deleting them.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -138,6 +145,18 @@ func main() {
 		klog.ErrorS(err, "unable to create controller", "controller", "RAG Eingine")
 		exitWithErrorFunc()
 	}
+
+	nodeClaimGCReconciler := garbagecollect.NewNodeClaimGCReconciler(
+		kClient,
+		mgr.GetEventRecorderFor("KAITO-NodeClaimGC-controller"),
+		nodeClaimGCGracePeriod,
+		garbagecollect.DefaultNodeClaimGCInterval,
+		nodeClaimGCDryRun,
+	)
+	if err = nodeClaimGCReconciler.SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "NodeClaimGC")
+		exitWithErrorFunc()
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
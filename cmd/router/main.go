@@ -0,0 +1,175 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimecache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/router"
+)
+
+var (
+	scheme = runtime.NewScheme()
+
+	exitWithErrorFunc = func() {
+		klog.Flush()
+		os.Exit(1)
+	}
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kaitov1beta1.AddToScheme(scheme))
+
+	klog.InitFlags(nil)
+}
+
+func main() {
+	var metricsAddr string
+	var probeAddr string
+	var routerAddr string
+	var featureGates string
+	var routingStrategy string
+	var prefixLength int
+	var imbalanceThreshold float64
+	var scrapeInterval time.Duration
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&routerAddr, "router-bind-address", ":5000", "The address the OpenAI compatible router binds to.")
+	flag.StringVar(&featureGates, "feature-gates", "vLLM=true", "Kaito feature gates, must match the workspace controller to resolve the runtime of workspaces.")
+	flag.StringVar(&routingStrategy, "routing-strategy", router.RoutingStrategyService,
+		"How requests are routed to the replicas of a workspace: service forwards them to the service of the workspace, prefix-aware forwards requests with the same session header or prompt prefix to the same replica.")
+	flag.IntVar(&prefixLength, "prefix-length", 1024, "The number of bytes of the prompt hashed to pick the replica of a request with the prefix-aware routing strategy.")
+	flag.Float64Var(&imbalanceThreshold, "load-imbalance-threshold", 4,
+		"The number of requests the replica of a request may have over the least loaded replica before the request is routed to the least loaded replica.")
+	flag.DurationVar(&scrapeInterval, "metrics-scrape-interval", 2*time.Second, "How often the vLLM metrics of the replicas are scraped with the prefix-aware routing strategy.")
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	if err := featuregates.ParseAndValidateFeatureGates(featureGates); err != nil {
+		klog.ErrorS(err, "unable to set `feature-gates` flag")
+		exitWithErrorFunc()
+	}
+	if routingStrategy != router.RoutingStrategyService && routingStrategy != router.RoutingStrategyPrefixAware {
+		klog.ErrorS(nil, "invalid `routing-strategy` flag", "routingStrategy", routingStrategy)
+		exitWithErrorFunc()
+	}
+	if prefixLength <= 0 || imbalanceThreshold <= 0 || scrapeInterval <= 0 {
+		klog.ErrorS(nil, "`prefix-length`, `load-imbalance-threshold` and `metrics-scrape-interval` must be positive")
+		exitWithErrorFunc()
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: metricsAddr,
+		},
+		HealthProbeBindAddress: probeAddr,
+		Cache: runtimecache.Options{
+			DefaultTransform: runtimecache.TransformStripManagedFields(),
+		},
+	})
+	if err != nil {
+		klog.ErrorS(err, "unable to start manager")
+		exitWithErrorFunc()
+	}
+
+	table := router.NewRouteTable()
+	if err = router.NewWorkspaceRouteReconciler(mgr.GetClient(), table).SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "Router")
+		exitWithErrorFunc()
+	}
+
+	// The replicas are tracked to route the requests for adapters spread across the replicas of a workspace.
+	replicas := router.NewReplicaTable()
+	if err = router.NewReplicaReconciler(mgr.GetClient(), replicas).SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "RouterReplicas")
+		exitWithErrorFunc()
+	}
+	balancer := &router.Balancer{
+		Replicas:           replicas,
+		PrefixAware:        routingStrategy == router.RoutingStrategyPrefixAware,
+		PrefixLength:       prefixLength,
+		ImbalanceThreshold: imbalanceThreshold,
+	}
+	if balancer.PrefixAware {
+		if err = mgr.Add(router.NewLoadScraper(replicas, scrapeInterval)); err != nil {
+			klog.ErrorS(err, "unable to add load scraper")
+			exitWithErrorFunc()
+		}
+	}
+
+	server := &http.Server{
+		Addr:              routerAddr,
+		Handler:           router.NewProxy(table, balancer),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				klog.ErrorS(err, "failed to shut down router")
+			}
+		}()
+		klog.InfoS("starting router", "address", routerAddr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})); err != nil {
+		klog.ErrorS(err, "unable to add router")
+		exitWithErrorFunc()
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		klog.ErrorS(err, "unable to set up health check")
+		exitWithErrorFunc()
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		klog.ErrorS(err, "unable to set up ready check")
+		exitWithErrorFunc()
+	}
+
+	klog.InfoS("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		klog.ErrorS(err, "problem running manager")
+		exitWithErrorFunc()
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	_ "github.com/kaito-project/kaito/presets/workspace/models"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/bge"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/deepseek"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/e5"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/falcon"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/llama3"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/mistral"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/phi2"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/phi3"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/phi4"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/qwen"
+)
@@ -44,8 +44,12 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
 	kaitoutils "github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/workspace/controllers"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers/adapterloader"
 	"github.com/kaito-project/kaito/pkg/workspace/controllers/garbagecollect"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers/nodehealth"
+	"github.com/kaito-project/kaito/pkg/workspace/controllers/usagereport"
 	"github.com/kaito-project/kaito/pkg/workspace/webhooks"
 )
 
@@ -80,6 +84,12 @@ func main() {
 	var enableWebhook bool
 	var probeAddr string
 	var featureGates string
+	var nodeHealthGracePeriod time.Duration
+	var nodeClaimGCGracePeriod time.Duration
+	var nodeClaimGCDryRun bool
+	var usageReportInterval time.Duration
+	var usageReportPeriod time.Duration
+	var adapterLoadInterval time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -88,6 +98,18 @@ func main() {
 	flag.BoolVar(&enableWebhook, "webhook", true,
 		"Enable webhook for controller manager. Default is true.")
 	flag.StringVar(&featureGates, "feature-gates", "vLLM=true", "Enable Kaito feature gates. Default,	vLLM=true.")
+	flag.DurationVar(&nodeHealthGracePeriod, "node-health-grace-period", nodehealth.DefaultGracePeriod,
+		"The time a workspace worker node can stay unhealthy before it is replaced.")
+	flag.DurationVar(&nodeClaimGCGracePeriod, "nodeclaim-gc-grace-period", garbagecollect.DefaultNodeClaimGCGracePeriod,
+		"The time a nodeClaim can stay orphaned after its workspace is gone before it is deleted.")
+	flag.BoolVar(&nodeClaimGCDryRun, "nodeclaim-gc-dry-run", false,
+		"Only report orphaned nodeClaims instead of deleting them.")
+	flag.DurationVar(&usageReportInterval, "usage-report-interval", usagereport.DefaultInterval,
+		"The interval at which the token usage metered by the inference proxies is collected.")
+	flag.DurationVar(&usageReportPeriod, "usage-report-period", usagereport.DefaultPeriod,
+		"The period covered by each UsageReport.")
+	flag.DurationVar(&adapterLoadInterval, "adapter-load-interval", adapterloader.DefaultInterval,
+		"The interval at which the adapters loaded by the running inference servers are checked.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -126,6 +148,13 @@ func main() {
 		exitWithErrorFunc()
 	}
 
+	// Feature gates must be parsed before the controllers are set up because
+	// they determine which resources the controllers watch.
+	if err := featuregates.ParseAndValidateFeatureGates(featureGates); err != nil {
+		klog.ErrorS(err, "unable to set `feature-gates` flag")
+		exitWithErrorFunc()
+	}
+
 	k8sclient.SetGlobalClient(mgr.GetClient())
 	kClient := k8sclient.GetGlobalClient()
 
@@ -150,6 +179,42 @@ func main() {
 		exitWithErrorFunc()
 	}
 
+	if !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+		nodeClaimGCReconciler := garbagecollect.NewNodeClaimGCReconciler(
+			kClient,
+			mgr.GetEventRecorderFor("KAITO-NodeClaimGC-controller"),
+			nodeClaimGCGracePeriod,
+			garbagecollect.DefaultNodeClaimGCInterval,
+			nodeClaimGCDryRun,
+		)
+		if err = nodeClaimGCReconciler.SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "NodeClaimGC")
+			exitWithErrorFunc()
+		}
+	}
+
+	nodeHealthReconciler := nodehealth.NewNodeHealthReconciler(
+		kClient,
+		mgr.GetEventRecorderFor("KAITO-NodeHealth-controller"),
+		nodeHealthGracePeriod,
+	)
+	if err = nodeHealthReconciler.SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "NodeHealth")
+		exitWithErrorFunc()
+	}
+
+	usageReportReconciler := usagereport.NewUsageReportReconciler(kClient, usageReportInterval, usageReportPeriod)
+	if err = usageReportReconciler.SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "UsageReport")
+		exitWithErrorFunc()
+	}
+
+	adapterLoaderReconciler := adapterloader.NewAdapterLoaderReconciler(kClient, adapterLoadInterval)
+	if err = adapterLoaderReconciler.SetupWithManager(mgr); err != nil {
+		klog.ErrorS(err, "unable to create controller", "controller", "AdapterLoader")
+		exitWithErrorFunc()
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -181,11 +246,6 @@ func main() {
 		time.Sleep(2 * time.Second)
 	}
 
-	if err := featuregates.ParseAndValidateFeatureGates(featureGates); err != nil {
-		klog.ErrorS(err, "unable to set `feature-gates` flag")
-		exitWithErrorFunc()
-	}
-
 	klog.InfoS("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		klog.ErrorS(err, "problem running manager")
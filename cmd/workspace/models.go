@@ -15,7 +15,9 @@ package main
 
 import (
 	_ "github.com/kaito-project/kaito/presets/workspace/models"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/bge"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/deepseek"
+	_ "github.com/kaito-project/kaito/presets/workspace/models/e5"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/falcon"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/llama3"
 	_ "github.com/kaito-project/kaito/presets/workspace/models/mistral"
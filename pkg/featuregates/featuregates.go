@@ -25,8 +25,10 @@ import (
 var (
 	// FeatureGates is a map that holds the feature gate names and their default values for Kaito.
 	FeatureGates = map[string]bool{
-		consts.FeatureFlagVLLM:            true,
-		consts.FeatureFlagEnsureNodeClass: false,
+		consts.FeatureFlagVLLM:                         true,
+		consts.FeatureFlagEnsureNodeClass:              false,
+		consts.FeatureFlagDisableNodeAutoProvisioning:  false,
+		consts.FeatureFlagGatewayAPIInferenceExtension: false,
 		//	Add more feature gates here
 	}
 )
@@ -0,0 +1,246 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// AdaptersPath is served on the system port of the proxy with the names of the adapters loaded by the
+// inference server, which the controller reports in the status of the workspace.
+const AdaptersPath = "/adapters"
+
+// AdapterLoader keeps the LoRA adapters loaded by vLLM in line with the adapter list of the pod, through the
+// runtime LoRA endpoints of vLLM that are only reachable from the pod. The adapter puller sidecar pulls the
+// listed adapters into the adapter directory and records the image of each adapter in the hidden file
+// `.<name>.image` next to it, so the list is checked every interval and an adapter is only loaded once the image
+// it lists is pulled. An adapter whose image changes is reloaded.
+type AdapterLoader struct {
+	upstream   *url.URL
+	client     *http.Client
+	listPath   string
+	adapterDir string
+
+	// images are the images of the loaded adapters.
+	images map[string]string
+}
+
+// NewAdapterLoader returns the loader of the adapters listed in the mounted adapter ConfigMap. Replicas
+// spreading the adapters load the list of their pod index.
+func NewAdapterLoader(upstream *url.URL, listDir, adapterDir, podIndex string) *AdapterLoader {
+	listPath := filepath.Join(listDir, "adapters")
+	if podIndex != "" {
+		listPath = filepath.Join(listDir, "replica-"+podIndex)
+	}
+	return &AdapterLoader{
+		upstream:   upstream,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		listPath:   listPath,
+		adapterDir: adapterDir,
+		images:     map[string]string{},
+	}
+}
+
+// Run syncs the loaded adapters every interval until the context is done.
+func (l *AdapterLoader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.sync(ctx); err != nil {
+				klog.ErrorS(err, "failed to sync the adapters of the inference server", "list", l.listPath)
+			}
+		}
+	}
+}
+
+// sync loads the listed adapters missing from the inference server, reloads the adapters whose image changed and
+// unloads the others. Adapters are left as is until the sidecar has pulled their listed image.
+func (l *AdapterLoader) sync(ctx context.Context) error {
+	names, listed, err := readAdapterList(l.listPath)
+	if errors.Is(err, os.ErrNotExist) {
+		// The list is missing until the ConfigMap is created, keep the loaded adapters meanwhile.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	loaded, err := l.listAdapters(ctx)
+	if err != nil {
+		return err
+	}
+	loadedSet := sets.New(loaded...)
+	for _, name := range names {
+		pulled := l.pulledImage(name)
+		if loadedSet.Has(name) {
+			if _, found := l.images[name]; !found {
+				// The adapter was loaded before the proxy started, from the image pulled at the time.
+				l.images[name] = pulled
+			}
+			if pulled != listed[name] || l.images[name] == pulled {
+				continue
+			}
+			if err := l.unloadAdapter(ctx, name); err != nil {
+				return err
+			}
+			delete(l.images, name)
+		} else if pulled != listed[name] {
+			klog.InfoS("Adapter is not pulled yet", "adapter", name, "image", listed[name])
+			continue
+		}
+		if err := l.loadAdapter(ctx, name); err != nil {
+			klog.ErrorS(err, "failed to load adapter", "adapter", name)
+			continue
+		}
+		l.images[name] = pulled
+		klog.InfoS("Loaded adapter", "adapter", name, "image", pulled)
+	}
+	for _, name := range loaded {
+		if _, found := listed[name]; found {
+			continue
+		}
+		if err := l.unloadAdapter(ctx, name); err != nil {
+			return err
+		}
+		delete(l.images, name)
+		klog.InfoS("Unloaded adapter", "adapter", name)
+	}
+	return nil
+}
+
+// pulledImage returns the image of the adapter pulled by the sidecar, empty if it is not pulled.
+func (l *AdapterLoader) pulledImage(name string) string {
+	data, err := os.ReadFile(filepath.Join(l.adapterDir, "."+name+".image"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// ServeHTTP serves the names of the adapters loaded by the inference server.
+func (l *AdapterLoader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	loaded, err := l.listAdapters(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]string{"adapters": sets.List(sets.New(loaded...))})
+}
+
+// readAdapterList returns the names of the adapters of a list, one `<name> <image>` per line, and their images.
+func readAdapterList(listPath string) ([]string, map[string]string, error) {
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	images := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+			names = append(names, fields[0])
+			images[fields[0]] = fields[1]
+		}
+	}
+	return names, images, scanner.Err()
+}
+
+// listAdapters returns the names of the adapters loaded by vLLM, the models derived from the base model.
+func (l *AdapterLoader) listAdapters(ctx context.Context) ([]string, error) {
+	var models struct {
+		Data []struct {
+			ID     string  `json:"id"`
+			Parent *string `json:"parent"`
+		} `json:"data"`
+	}
+	body, err := l.do(ctx, http.MethodGet, "/v1/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &models); err != nil {
+		return nil, err
+	}
+	var adapters []string
+	for _, model := range models.Data {
+		if model.Parent != nil {
+			adapters = append(adapters, model.ID)
+		}
+	}
+	return adapters, nil
+}
+
+func (l *AdapterLoader) loadAdapter(ctx context.Context, name string) error {
+	_, err := l.do(ctx, http.MethodPost, "/v1/load_lora_adapter", map[string]string{"lora_name": name, "lora_path": path.Join(l.adapterDir, name)})
+	return err
+}
+
+func (l *AdapterLoader) unloadAdapter(ctx context.Context, name string) error {
+	_, err := l.do(ctx, http.MethodPost, "/v1/unload_lora_adapter", map[string]string{"lora_name": name})
+	return err
+}
+
+func (l *AdapterLoader) do(ctx context.Context, method, endpoint string, payload any) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, l.upstream.JoinPath(endpoint).String(), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, lo.Substring(string(body), 0, 1024))
+	}
+	return body, nil
+}
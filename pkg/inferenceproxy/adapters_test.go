@@ -0,0 +1,120 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVLLM serves the runtime LoRA endpoints of vLLM.
+type fakeVLLM struct {
+	mu       sync.Mutex
+	loaded   map[string]string
+	requests []string
+}
+
+func (f *fakeVLLM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var req struct {
+		Name string `json:"lora_name"`
+		Path string `json:"lora_path"`
+	}
+	switch r.URL.Path {
+	case "/v1/models":
+		base := "base"
+		data := []map[string]any{{"id": base, "parent": nil}}
+		for name := range f.loaded {
+			data = append(data, map[string]any{"id": name, "parent": base})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	case "/v1/load_lora_adapter":
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if _, found := f.loaded[req.Name]; found {
+			http.Error(w, "already loaded", http.StatusBadRequest)
+			return
+		}
+		f.loaded[req.Name] = req.Path
+		f.requests = append(f.requests, "load "+req.Name)
+	case "/v1/unload_lora_adapter":
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		delete(f.loaded, req.Name)
+		f.requests = append(f.requests, "unload "+req.Name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestAdapterLoaderSync(t *testing.T) {
+	vllm := &fakeVLLM{loaded: map[string]string{"stale": "/adapters/stale", "kept": "/adapters/kept"}}
+	upstream := httptest.NewServer(vllm)
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	listDir, adapterDir := t.TempDir(), t.TempDir()
+	pull := func(name, image string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(adapterDir, "."+name+".image"), []byte(image+"\n"), 0o600))
+	}
+	loader := NewAdapterLoader(upstreamURL, listDir, adapterDir, "1")
+
+	// The loaded adapters are kept until the list is mounted.
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Len(t, vllm.loaded, 2)
+	assert.Empty(t, vllm.requests)
+
+	// Replicas load the list of their pod index, adapters whose image is not pulled yet are loaded later.
+	pull("kept", "registry/kept:1")
+	pull("new", "registry/new:1")
+	pull("pending", "registry/pending:0")
+	assert.NoError(t, os.WriteFile(filepath.Join(listDir, "adapters"), []byte("other registry/other:1\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(listDir, "replica-1"), []byte("kept registry/kept:1\nnew registry/new:1\npending registry/pending:1\n"), 0o600))
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Equal(t, map[string]string{"kept": "/adapters/kept", "new": filepath.Join(adapterDir, "new")}, vllm.loaded)
+	assert.Equal(t, []string{"load new", "unload stale"}, vllm.requests)
+
+	pull("pending", "registry/pending:1")
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Contains(t, vllm.loaded, "pending")
+
+	// An adapter pointed at a new image is reloaded once the image is pulled.
+	vllm.requests = nil
+	assert.NoError(t, os.WriteFile(filepath.Join(listDir, "replica-1"), []byte("kept registry/kept:2\nnew registry/new:1\npending registry/pending:1\n"), 0o600))
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Empty(t, vllm.requests)
+	pull("kept", "registry/kept:2")
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Equal(t, []string{"unload kept", "load kept"}, vllm.requests)
+	assert.Equal(t, filepath.Join(adapterDir, "kept"), vllm.loaded["kept"])
+	assert.NoError(t, loader.sync(context.Background()))
+	assert.Len(t, vllm.requests, 2)
+
+	rec := httptest.NewRecorder()
+	loader.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, AdaptersPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"adapters":["kept","new","pending"]}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	loader.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, AdaptersPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// APIKeyStore holds the API keys of the auth Secret of a workspace, which is mounted as a directory with a
+// file per client. The file name is the name of the client and its content is the API key of the client.
+type APIKeyStore struct {
+	dir string
+
+	mu      sync.RWMutex
+	clients map[string]string // API key -> client name
+}
+
+func NewAPIKeyStore(dir string) *APIKeyStore {
+	return &APIKeyStore{dir: dir, clients: map[string]string{}}
+}
+
+// Load reads the API keys from the directory of the store.
+func (s *APIKeyStore) Load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read API keys: %w", err)
+	}
+	clients := map[string]string{}
+	for _, entry := range entries {
+		// Secret volumes keep the data in hidden directories and link each key to them.
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read API key of client %s: %w", entry.Name(), err)
+		}
+		key := strings.TrimSpace(string(content))
+		if key == "" {
+			continue
+		}
+		clients[key] = entry.Name()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients = clients
+	return nil
+}
+
+// Run reloads the API keys every interval until the context is done, so that keys added to or removed
+// from the Secret take effect once the kubelet updates the volume.
+func (s *APIKeyStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(); err != nil {
+				klog.ErrorS(err, "failed to reload API keys", "dir", s.dir)
+			}
+		}
+	}
+}
+
+// Authenticate returns the name of the client the API key belongs to.
+func (s *APIKeyStore) Authenticate(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	client, ok := s.clients[key]
+	return client, ok
+}
@@ -0,0 +1,48 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "team-a"), []byte("key-a\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "team-b"), []byte(""), 0o600))
+	// Secret volumes keep the data in hidden directories.
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0o700))
+
+	store := NewAPIKeyStore(dir)
+	assert.NoError(t, store.Load())
+
+	client, ok := store.Authenticate("key-a")
+	assert.True(t, ok)
+	assert.Equal(t, "team-a", client)
+
+	_, ok = store.Authenticate("")
+	assert.False(t, ok, "clients without a key must not authenticate with an empty key")
+
+	// Removed keys are rejected once the store is reloaded.
+	assert.NoError(t, os.Remove(filepath.Join(dir, "team-a")))
+	assert.NoError(t, store.Load())
+	_, ok = store.Authenticate("key-a")
+	assert.False(t, ok)
+
+	assert.Error(t, NewAPIKeyStore(filepath.Join(dir, "missing")).Load())
+}
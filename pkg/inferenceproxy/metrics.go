@@ -0,0 +1,56 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// PromptTokensMetric is the name of the counter of the prompt tokens processed by the inference server.
+	PromptTokensMetric = "kaito_inference_proxy_prompt_tokens_total"
+	// CompletionTokensMetric is the name of the counter of the completion tokens generated by the inference server.
+	CompletionTokensMetric = "kaito_inference_proxy_completion_tokens_total"
+)
+
+var (
+	// Registry is the registry of the metrics of the proxy.
+	Registry = prometheus.NewRegistry()
+
+	requestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kaito_inference_proxy_requests_total",
+			Help: "Number of requests received by the inference proxy by client and HTTP status code",
+		},
+		[]string{"client", "code"},
+	)
+	promptTokensTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: PromptTokensMetric,
+			Help: "Number of prompt tokens processed by the inference server by workspace, model, adapter and client",
+		},
+		[]string{"workspace", "model", "adapter", "client"},
+	)
+	completionTokensTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: CompletionTokensMetric,
+			Help: "Number of completion tokens generated by the inference server by workspace, model, adapter and client",
+		},
+		[]string{"workspace", "model", "adapter", "client"},
+	)
+)
+
+func init() {
+	Registry.MustRegister(requestsTotal, promptTokensTotal, completionTokensTotal)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// HealthPath is served by the proxy itself without authentication, for the probes of the proxy container.
+	HealthPath = "/healthz"
+
+	// clientAnonymous is the client of requests when authentication is disabled.
+	clientAnonymous = "anonymous"
+	// clientUnauthenticated is the client of requests rejected for a missing or invalid API key.
+	clientUnauthenticated = "unauthenticated"
+)
+
+// publicPaths are the paths of the inference server forwarded without authentication or limits: the health
+// check probed by the kubelet, and the metrics scraped by the router and the endpoint picker. The inference
+// server only listens on the loopback interface behind the proxy, and neither path serves the model.
+var publicPaths = map[string]bool{
+	"/health":  true,
+	"/metrics": true,
+}
+
+// adapterAdminPaths are the runtime LoRA endpoints of vLLM. The clients of the inference service must not load
+// or unload adapters, only the AdapterLoader of the proxy calls them on the loopback interface.
+var adapterAdminPaths = map[string]bool{
+	"/v1/load_lora_adapter":   true,
+	"/v1/unload_lora_adapter": true,
+}
+
+// Proxy forwards the requests of the inference service to the inference server running in the same pod.
+// When an APIKeyStore is set, requests must carry one of its API keys as a bearer token. Requests over
+// the limits of the proxy are rejected. The token usage reported by the responses is metered per client.
+type Proxy struct {
+	keys         *APIKeyStore
+	limiter      *limiter
+	metering     Metering
+	reverseProxy *httputil.ReverseProxy
+}
+
+func NewProxy(upstream *url.URL, keys *APIKeyStore, limits Limits, metering Metering) *Proxy {
+	var l *limiter
+	if limits.Enabled() {
+		l = newLimiter(limits)
+	}
+	return &Proxy{
+		keys:     keys,
+		limiter:  l,
+		metering: metering,
+		reverseProxy: &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(upstream)
+				pr.SetXForwarded()
+			},
+			// Flush immediately so that streamed completions reach the client as they are generated.
+			FlushInterval:  -1,
+			ModifyResponse: meterResponse,
+		},
+	}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == HealthPath {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == http.MethodGet && publicPaths[r.URL.Path] {
+		p.reverseProxy.ServeHTTP(w, r)
+		return
+	}
+	if adapterAdminPaths[r.URL.Path] {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "", "Not found.")
+		return
+	}
+
+	client := clientAnonymous
+	if p.keys != nil {
+		var ok bool
+		client, ok = p.keys.Authenticate(bearerToken(r))
+		if !ok {
+			requestsTotal.WithLabelValues(clientUnauthenticated, strconv.Itoa(http.StatusUnauthorized)).Inc()
+			writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "Incorrect API key provided.")
+			return
+		}
+		// The inference server does not check API keys, so the key does not need to leave the proxy.
+		r.Header.Del("Authorization")
+	}
+
+	if p.limiter != nil {
+		// Clients without an API key are limited by the IP address the request comes from. The X-Forwarded-For
+		// header is not trusted since any client can set it, so all the clients behind a source NAT, an ingress
+		// or the router share a single limit. Enable authentication to limit such clients separately.
+		limitKey := client
+		if p.keys == nil {
+			limitKey = clientIP(r)
+		}
+		release, retryAfter, ok := p.limiter.admit(limitKey)
+		if !ok {
+			requestsTotal.WithLabelValues(client, strconv.Itoa(http.StatusTooManyRequests)).Inc()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "requests", "rate_limit_exceeded", "Rate limit reached, please try again later.")
+			return
+		}
+		defer release()
+	}
+
+	if r.Method == http.MethodPost && meteredPaths[r.URL.Path] {
+		var err error
+		if r, err = prepareMetering(r, p.metering, client); err != nil {
+			requestsTotal.WithLabelValues(client, strconv.Itoa(http.StatusBadRequest)).Inc()
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Failed to read the request body.")
+			return
+		}
+	}
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	p.reverseProxy.ServeHTTP(rec, r)
+	requestsTotal.WithLabelValues(client, strconv.Itoa(rec.status)).Inc()
+}
+
+// bearerToken returns the bearer token of the Authorization header of the request.
+func bearerToken(r *http.Request) string {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// clientIP returns the IP address of the client of the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder records the status code written by the reverse proxy.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets the reverse proxy flush streamed responses through the recorder.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+type errorResponse struct {
+	Error errorObject `json:"error"`
+}
+
+type errorObject struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code"`
+}
+
+// writeError writes an error in the format of the OpenAI API.
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	obj := errorObject{Message: message, Type: errType}
+	if code != "" {
+		obj.Code = &code
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(errorResponse{Error: obj}); err != nil {
+		klog.ErrorS(err, "failed to write response")
+	}
+}
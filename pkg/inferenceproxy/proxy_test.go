@@ -0,0 +1,201 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) {
+	var authorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "team-a"), []byte("key-a"), 0o600))
+	keys := NewAPIKeyStore(dir)
+	assert.NoError(t, keys.Load())
+	proxy := NewProxy(upstreamURL, keys, Limits{}, Metering{})
+
+	tests := []struct {
+		name           string
+		path           string
+		authorization  string
+		expectedStatus int
+		expectedClient string
+	}{
+		{
+			name:           "valid key",
+			path:           "/v1/chat/completions",
+			authorization:  "Bearer key-a",
+			expectedStatus: http.StatusOK,
+			expectedClient: "team-a",
+		},
+		{
+			name:           "invalid key",
+			path:           "/v1/chat/completions",
+			authorization:  "Bearer key-b",
+			expectedStatus: http.StatusUnauthorized,
+			expectedClient: clientUnauthenticated,
+		},
+		{
+			name:           "missing key",
+			path:           "/v1/models",
+			expectedStatus: http.StatusUnauthorized,
+			expectedClient: clientUnauthenticated,
+		},
+		{
+			name:           "health without key",
+			path:           HealthPath,
+			expectedStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var before float64
+			if tc.expectedClient != "" {
+				before = testutil.ToFloat64(requestsTotal.WithLabelValues(tc.expectedClient, strconv.Itoa(tc.expectedStatus)))
+			}
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedClient != "" {
+				after := testutil.ToFloat64(requestsTotal.WithLabelValues(tc.expectedClient, strconv.Itoa(tc.expectedStatus)))
+				assert.Equal(t, before+1, after)
+			}
+		})
+	}
+	assert.Empty(t, authorization, "the API key must not be forwarded to the inference server")
+}
+
+func TestProxyPublicPaths(t *testing.T) {
+	var paths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	keys := NewAPIKeyStore(t.TempDir())
+	assert.NoError(t, keys.Load())
+	proxy := NewProxy(upstreamURL, keys, Limits{RequestsPerMinute: 1}, Metering{})
+
+	// The health check and the metrics are forwarded without an API key, and regardless of the limits.
+	for _, path := range []string{"/health", "/metrics", "/health", "/metrics"} {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+	assert.Equal(t, []string{"/health", "/metrics", "/health", "/metrics"}, paths)
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestProxyAdapterAdminPaths(t *testing.T) {
+	var paths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	// The runtime LoRA endpoints are not served to the clients of the inference service.
+	proxy := NewProxy(upstreamURL, nil, Limits{}, Metering{})
+	for _, path := range []string{"/v1/load_lora_adapter", "/v1/unload_lora_adapter"} {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+	assert.Empty(t, paths)
+}
+
+func TestProxyWithoutAuth(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	before := testutil.ToFloat64(requestsTotal.WithLabelValues(clientAnonymous, strconv.Itoa(http.StatusTeapot)))
+	rec := httptest.NewRecorder()
+	NewProxy(upstreamURL, nil, Limits{}, Metering{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(requestsTotal.WithLabelValues(clientAnonymous, strconv.Itoa(http.StatusTeapot))))
+}
+
+func TestProxyRateLimit(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+	proxy := NewProxy(upstreamURL, nil, Limits{MaxConcurrentRequests: 1}, Metering{})
+
+	send := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		return rec
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		send("/slow", "10.0.0.1:1234")
+	}()
+	assert.Eventually(t, func() bool {
+		proxy.limiter.mu.Lock()
+		defer proxy.limiter.mu.Unlock()
+		return proxy.limiter.inFlight == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The second request of the same client is over its concurrency limit, other clients are not limited.
+	rec := send("/fast", "10.0.0.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, send("/fast", "10.0.0.2:1234").Code)
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusOK, send("/fast", "10.0.0.1:5678").Code)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v2"
+)
+
+// clientIdleTimeout is the time after which the state of a client without requests is dropped.
+const clientIdleTimeout = 10 * time.Minute
+
+// Limits are the limits enforced by the proxy, a zero value disables the limit.
+type Limits struct {
+	// RequestsPerMinute is the number of requests a client can send per minute.
+	RequestsPerMinute int
+	// MaxConcurrentRequests is the number of requests of a client served at the same time.
+	MaxConcurrentRequests int
+	// MaxConcurrentRequestsTotal is the number of requests of all clients served at the same time.
+	MaxConcurrentRequestsTotal int
+}
+
+// Enabled returns true if any limit is set.
+func (l Limits) Enabled() bool {
+	return l.RequestsPerMinute > 0 || l.MaxConcurrentRequests > 0 || l.MaxConcurrentRequestsTotal > 0
+}
+
+// MaxNumSeqsFromConfig returns the max-num-seqs of the vllm section of an inference config file, which is the
+// number of sequences vLLM batches at the same time. Zero is returned if it is not set.
+func MaxNumSeqsFromConfig(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read inference config: %w", err)
+	}
+	var config struct {
+		VLLM map[string]string `yaml:"vllm"`
+	}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return 0, fmt.Errorf("failed to parse inference config: %w", err)
+	}
+	value, ok := config.VLLM["max-num-seqs"]
+	if !ok {
+		return 0, nil
+	}
+	maxNumSeqs, err := strconv.Atoi(value)
+	if err != nil || maxNumSeqs < 0 {
+		return 0, fmt.Errorf("invalid max-num-seqs %q in inference config", value)
+	}
+	return maxNumSeqs, nil
+}
+
+// limiter admits requests within the limits of the proxy.
+type limiter struct {
+	limits Limits
+	now    func() time.Time
+
+	mu       sync.Mutex
+	inFlight int
+	clients  map[string]*clientState
+	lastGC   time.Time
+}
+
+type clientState struct {
+	rate     *rate.Limiter
+	inFlight int
+	lastSeen time.Time
+}
+
+func newLimiter(limits Limits) *limiter {
+	return &limiter{limits: limits, now: time.Now, clients: map[string]*clientState{}}
+}
+
+// admit admits a request of the client. The returned function must be called once the request is served.
+// If the request is over a limit, the time after which the client may retry is returned instead.
+func (l *limiter) admit(client string) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.collectIdleClients(now)
+	state, ok := l.clients[client]
+	if !ok {
+		state = &clientState{}
+		if l.limits.RequestsPerMinute > 0 {
+			// The bucket holds a minute of requests, so that a client can use its budget in bursts.
+			state.rate = rate.NewLimiter(rate.Limit(float64(l.limits.RequestsPerMinute)/60), l.limits.RequestsPerMinute)
+		}
+		l.clients[client] = state
+	}
+	state.lastSeen = now
+
+	if l.limits.MaxConcurrentRequestsTotal > 0 && l.inFlight >= l.limits.MaxConcurrentRequestsTotal {
+		return nil, time.Second, false
+	}
+	if l.limits.MaxConcurrentRequests > 0 && state.inFlight >= l.limits.MaxConcurrentRequests {
+		return nil, time.Second, false
+	}
+	if state.rate != nil {
+		reservation := state.rate.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			return nil, delay, false
+		}
+	}
+
+	l.inFlight++
+	state.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.inFlight--
+			state.inFlight--
+		})
+	}, 0, true
+}
+
+// collectIdleClients drops the state of clients without requests for clientIdleTimeout, so that the state of
+// clients identified by their IP address does not grow without bound.
+func (l *limiter) collectIdleClients(now time.Time) {
+	if now.Sub(l.lastGC) < clientIdleTimeout {
+		return
+	}
+	l.lastGC = now
+	for client, state := range l.clients {
+		if state.inFlight == 0 && now.Sub(state.lastSeen) > clientIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+}
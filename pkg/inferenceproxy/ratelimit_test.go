@@ -0,0 +1,102 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterRequestsPerMinute(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(Limits{RequestsPerMinute: 2})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, _, ok := l.admit("team-a")
+		assert.True(t, ok)
+		release()
+	}
+	_, retryAfter, ok := l.admit("team-a")
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, retryAfter)
+
+	// Other clients have their own budget.
+	_, _, ok = l.admit("team-b")
+	assert.True(t, ok)
+
+	now = now.Add(30 * time.Second)
+	_, _, ok = l.admit("team-a")
+	assert.True(t, ok)
+}
+
+func TestLimiterMaxConcurrentRequestsTotal(t *testing.T) {
+	l := newLimiter(Limits{MaxConcurrentRequestsTotal: 2})
+
+	releaseA, _, ok := l.admit("team-a")
+	assert.True(t, ok)
+	_, _, ok = l.admit("team-b")
+	assert.True(t, ok)
+	_, retryAfter, ok := l.admit("team-c")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// Releasing twice must not free more than one slot.
+	releaseA()
+	releaseA()
+	_, _, ok = l.admit("team-c")
+	assert.True(t, ok)
+	_, _, ok = l.admit("team-d")
+	assert.False(t, ok)
+}
+
+func TestLimiterCollectIdleClients(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(Limits{RequestsPerMinute: 1})
+	l.now = func() time.Time { return now }
+
+	release, _, ok := l.admit("10.0.0.1")
+	assert.True(t, ok)
+	release()
+	_, _, _ = l.admit("10.0.0.2")
+
+	now = now.Add(clientIdleTimeout + time.Second)
+	_, _, _ = l.admit("10.0.0.3")
+	assert.NotContains(t, l.clients, "10.0.0.1")
+	assert.Contains(t, l.clients, "10.0.0.2", "clients with requests in flight are kept")
+}
+
+func TestMaxNumSeqsFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "inference_config.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	maxNumSeqs, err := MaxNumSeqsFromConfig(write("vllm:\n  cpu-offload-gb: 0\n  max-num-seqs: 64\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 64, maxNumSeqs)
+
+	maxNumSeqs, err = MaxNumSeqsFromConfig(write("vllm:\n  cpu-offload-gb: 0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, maxNumSeqs)
+
+	_, err = MaxNumSeqsFromConfig(write("vllm:\n  max-num-seqs: many\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxMeteredBodyBytes bounds the request and response bodies buffered to meter the token usage. Larger
+// bodies are forwarded without being metered.
+const maxMeteredBodyBytes = 32 << 20
+
+// meteredPaths are the paths of the OpenAI API whose responses report the token usage.
+var meteredPaths = map[string]bool{
+	"/v1/chat/completions": true,
+	"/v1/completions":      true,
+	"/v1/embeddings":       true,
+}
+
+// Metering names the workspace and the served model of the inference server, to label the token usage
+// metered by the proxy. Requests for another model are requests for an adapter of the model.
+type Metering struct {
+	Workspace string
+	Model     string
+}
+
+// labels returns the labels of the token usage of a request of the client for the requested model.
+func (m Metering) labels(client, requestedModel string) prometheus.Labels {
+	adapter := ""
+	if requestedModel != "" && requestedModel != m.Model {
+		adapter = requestedModel
+	}
+	return prometheus.Labels{
+		"workspace": m.Workspace,
+		"model":     m.Model,
+		"adapter":   adapter,
+		"client":    client,
+	}
+}
+
+type usage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+// usageMetering is how the token usage of a request is metered.
+type usageMetering struct {
+	labels prometheus.Labels
+	// stripUsage drops the final chunk of a streamed response carrying the usage, which the proxy requested
+	// on behalf of a client that did not request it.
+	stripUsage bool
+}
+
+// usageMeteringKey is the context key of the usageMetering of a request.
+type usageMeteringKey struct{}
+
+// prepareMetering reads the requested model from the request body and returns the request to forward.
+// Streamed completions only report the token usage in a final chunk requested by stream_options, so
+// the proxy always requests it, and hides the chunk from the clients that did not request it.
+func prepareMetering(r *http.Request, metering Metering, client string) (*http.Request, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMeteredBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxMeteredBodyBytes {
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		return r, nil
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// Let the inference server reject the request.
+		return r, nil
+	}
+	var model string
+	_ = json.Unmarshal(fields["model"], &model)
+	var stream bool
+	_ = json.Unmarshal(fields["stream"], &stream)
+	m := usageMetering{labels: metering.labels(client, model)}
+	if stream {
+		streamOptions := map[string]json.RawMessage{}
+		_ = json.Unmarshal(fields["stream_options"], &streamOptions)
+		var includeUsage bool
+		_ = json.Unmarshal(streamOptions["include_usage"], &includeUsage)
+		m.stripUsage = !includeUsage
+		streamOptions["include_usage"] = json.RawMessage("true")
+		fields["stream_options"], _ = json.Marshal(streamOptions)
+		if body, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return r.WithContext(context.WithValue(r.Context(), usageMeteringKey{}, m)), nil
+}
+
+// meterResponse wraps the body of a successful response to record the token usage it reports once the
+// body is read.
+func meterResponse(resp *http.Response) error {
+	metering, ok := resp.Request.Context().Value(usageMeteringKey{}).(usageMetering)
+	if !ok || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	stream := strings.TrimSpace(mediaType) == "text/event-stream"
+	if stream && metering.stripUsage {
+		// The length of the stream changes once the usage chunk is dropped.
+		resp.ContentLength = -1
+		resp.Header.Del("Content-Length")
+	}
+	resp.Body = &usageReader{
+		ReadCloser: resp.Body,
+		stream:     stream,
+		stripUsage: metering.stripUsage,
+		record: func(u usage) {
+			promptTokensTotal.With(metering.labels).Add(float64(u.PromptTokens))
+			completionTokensTotal.With(metering.labels).Add(float64(u.CompletionTokens))
+		},
+	}
+	return nil
+}
+
+// usageReader parses the usage of a response while it is read. JSON responses are buffered to be parsed
+// at the end, server-sent events are parsed line by line for the chunk carrying the usage, and forwarded
+// once their line is complete so that the chunk can be dropped.
+type usageReader struct {
+	io.ReadCloser
+	stream     bool
+	stripUsage bool
+	record     func(usage)
+
+	buf      bytes.Buffer
+	out      bytes.Buffer
+	err      error
+	overflow bool
+	usage    *usage
+	once     sync.Once
+}
+
+func (u *usageReader) Read(p []byte) (int, error) {
+	if !u.stream {
+		n, err := u.ReadCloser.Read(p)
+		u.consume(p[:n])
+		if err == io.EOF {
+			u.finish()
+		}
+		return n, err
+	}
+	for u.out.Len() == 0 && u.err == nil {
+		n, err := u.ReadCloser.Read(p)
+		u.consume(p[:n])
+		if err != nil {
+			// Forward the last line even if it is not terminated.
+			u.out.Write(u.buf.Bytes())
+			u.buf.Reset()
+			u.err = err
+		}
+	}
+	if u.out.Len() > 0 {
+		return u.out.Read(p)
+	}
+	if u.err == io.EOF {
+		u.finish()
+	}
+	return 0, u.err
+}
+
+func (u *usageReader) Close() error {
+	u.finish()
+	return u.ReadCloser.Close()
+}
+
+func (u *usageReader) consume(data []byte) {
+	if !u.stream {
+		if u.overflow || u.buf.Len()+len(data) > maxMeteredBodyBytes {
+			u.overflow = true
+			u.buf.Reset()
+			return
+		}
+		u.buf.Write(data)
+		return
+	}
+	for len(data) > 0 {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		data = rest
+		// Lines too long to be buffered are forwarded as they come without being parsed.
+		if !u.overflow && u.buf.Len()+len(line) > maxMeteredBodyBytes {
+			u.out.Write(u.buf.Bytes())
+			u.buf.Reset()
+			u.overflow = true
+		}
+		if u.overflow {
+			u.out.Write(line)
+		} else {
+			u.buf.Write(line)
+		}
+		if !found {
+			return
+		}
+		if !u.overflow && u.parseEvent(u.buf.Bytes()) {
+			// Drop the line along with its newline.
+			u.buf.Reset()
+			continue
+		}
+		u.out.Write(u.buf.Bytes())
+		u.out.WriteByte('\n')
+		u.overflow = false
+		u.buf.Reset()
+	}
+}
+
+// parseEvent parses a line of server-sent events and keeps the usage of the chunk if it has one. It returns
+// true if the line must be dropped, which is the case of the usage chunk the client did not request.
+func (u *usageReader) parseEvent(line []byte) bool {
+	data, found := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:"))
+	if !found || !bytes.Contains(data, []byte(`"usage"`)) {
+		return false
+	}
+	var chunk struct {
+		Choices []json.RawMessage `json:"choices"`
+		Usage   *usage            `json:"usage"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil || chunk.Usage == nil {
+		return false
+	}
+	u.usage = chunk.Usage
+	return u.stripUsage && len(chunk.Choices) == 0
+}
+
+func (u *usageReader) finish() {
+	u.once.Do(func() {
+		if !u.stream && !u.overflow {
+			var response struct {
+				Usage *usage `json:"usage"`
+			}
+			if err := json.Unmarshal(u.buf.Bytes(), &response); err == nil {
+				u.usage = response.Usage
+			}
+		}
+		u.buf = bytes.Buffer{}
+		if u.usage != nil {
+			u.record(*u.usage)
+		}
+	})
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,109 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferenceproxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyMetering(t *testing.T) {
+	var streamOptions json.RawMessage
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream        bool            `json:"stream"`
+			StreamOptions json.RawMessage `json:"stream_options"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		streamOptions = req.StreamOptions
+		if !req.Stream {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"choices":[{"text":"hi"}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		for _, event := range []string{
+			`data: {"choices":[{"delta":{"content":"hi"}}],"usage":null}`,
+			`data: {"choices":[],"usage":{"prompt_tokens":7,"completion_tokens":3,"total_tokens":10}}`,
+			`data: [DONE]`,
+		} {
+			_, _ = io.WriteString(w, event+"\n\n")
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+	proxy := NewProxy(upstreamURL, nil, Limits{}, Metering{Workspace: "workspace-metering", Model: "phi-4"})
+
+	tests := []struct {
+		name                     string
+		body                     string
+		expectedLabels           map[string]string
+		expectedPromptTokens     float64
+		expectedCompletionTokens float64
+		expectedStreamOptions    string
+		expectedUsageInBody      bool
+	}{
+		{
+			name:                     "completion",
+			body:                     `{"model":"phi-4","prompt":"hello"}`,
+			expectedLabels:           map[string]string{"workspace": "workspace-metering", "model": "phi-4", "adapter": "", "client": clientAnonymous},
+			expectedPromptTokens:     10,
+			expectedCompletionTokens: 5,
+			expectedUsageInBody:      true,
+		},
+		{
+			name:                     "streamed completion of an adapter",
+			body:                     `{"model":"adapter-a","prompt":"hello","stream":true,"stream_options":{"include_usage":false}}`,
+			expectedLabels:           map[string]string{"workspace": "workspace-metering", "model": "phi-4", "adapter": "adapter-a", "client": clientAnonymous},
+			expectedPromptTokens:     7,
+			expectedCompletionTokens: 3,
+			expectedStreamOptions:    `{"include_usage":true}`,
+		},
+		{
+			name:                     "streamed completion requesting the usage",
+			body:                     `{"model":"adapter-b","prompt":"hello","stream":true,"stream_options":{"include_usage":true}}`,
+			expectedLabels:           map[string]string{"workspace": "workspace-metering", "model": "phi-4", "adapter": "adapter-b", "client": clientAnonymous},
+			expectedPromptTokens:     7,
+			expectedCompletionTokens: 3,
+			expectedStreamOptions:    `{"include_usage":true}`,
+			expectedUsageInBody:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/completions", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tc.expectedStreamOptions != "" {
+				assert.JSONEq(t, tc.expectedStreamOptions, string(streamOptions))
+			}
+			assert.Equal(t, tc.expectedUsageInBody, strings.Contains(rec.Body.String(), `"prompt_tokens"`))
+			if strings.Contains(tc.body, `"stream":true`) {
+				assert.Contains(t, rec.Body.String(), "data: [DONE]")
+			}
+			assert.Equal(t, tc.expectedPromptTokens, testutil.ToFloat64(promptTokensTotal.With(tc.expectedLabels)))
+			assert.Equal(t, tc.expectedCompletionTokens, testutil.ToFloat64(completionTokensTotal.With(tc.expectedLabels)))
+		})
+	}
+}
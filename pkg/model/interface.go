@@ -28,6 +28,7 @@ import (
 
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
 type Model interface {
@@ -53,6 +54,11 @@ type RuntimeName string
 const (
 	RuntimeNameHuggingfaceTransformers RuntimeName = "transformers"
 	RuntimeNameVLLM                    RuntimeName = "vllm"
+	RuntimeNameSGLang                  RuntimeName = "sglang"
+	// RuntimeNameLlamaCpp serves quantized GGUF weights with llama.cpp on CPU nodes.
+	RuntimeNameLlamaCpp RuntimeName = "llamacpp"
+	// RuntimeNameTensorRTLLM serves an engine compiled by TensorRT-LLM for the GPU model of the workspace.
+	RuntimeNameTensorRTLLM RuntimeName = "tensorrt-llm"
 
 	ConfigfileNameVLLM    = "inference_config.yaml"
 	DefaultMemoryUtilVLLM = 0.9 // Default gpu memory utilization for VLLM runtime
@@ -62,11 +68,31 @@ const (
 	PortRayCluster = 6379
 )
 
+const (
+	// ModelTypeTextGeneration is the type of models generating text from a prompt.
+	ModelTypeTextGeneration = "text-generation"
+	// ModelTypeTextEmbedding is the type of models returning the embedding vectors of the input text.
+	ModelTypeTextEmbedding = "text-embedding"
+	// ModelTypeImageTextToText is the type of multimodal models generating text from a prompt
+	// interleaving text and images.
+	ModelTypeImageTextToText = "image-text-to-text"
+)
+
 var (
 	// vLLM will do kvcache pre-allocation,
 	// We need to reserve enough memory for other ephemeral operations to avoid OOM.
 	// This is an empirical value.
 	ReservedNonKVCacheMemory = resource.MustParse("1.5Gi")
+
+	// llama.cpp memory-maps the GGUF weights, this is the memory reserved on top of
+	// them for the KV cache and the server itself.
+	ReservedLlamaCppMemory = resource.MustParse("2Gi")
+)
+
+const (
+	// MinLlamaCppCPUs is the minimum number of CPUs requested for llama.cpp, one more CPU is
+	// requested per GiB of weights since token generation on CPU is bound by the number of cores.
+	MinLlamaCppCPUs = 2
 )
 
 // Metadata defines the metadata for a model.
@@ -76,8 +102,8 @@ type Metadata struct {
 	Name string `yaml:"name"`
 
 	// ModelType is the type of the model, which indicates the kind of model
-	// it is. Currently, the only supported types are "text-generation" and
-	// "llama2-completion" (deprecated).
+	// it is. Currently, the supported types are "text-generation", "text-embedding",
+	// "image-text-to-text" and "llama2-completion" (deprecated).
 	ModelType string `yaml:"type"`
 
 	// Version is the version of the model. It is a URL that points to the
@@ -132,6 +158,8 @@ type PresetParam struct {
 	PerGPUMemoryRequirement       string         // GPU memory required per GPU. Used for inference.
 	TuningPerGPUMemoryRequirement map[string]int // Min GPU memory per tuning method (batch size 1). Used for tuning.
 
+	EmbeddingDimension int // Dimension of the embedding vectors. Only set for text-embedding models.
+
 	RuntimeParam
 
 	// ReadinessTimeout defines the maximum duration for creating the workload.
@@ -144,6 +172,9 @@ type PresetParam struct {
 type RuntimeParam struct {
 	Transformers HuggingfaceTransformersParam
 	VLLM         VLLMParam
+	SGLang       SGLangParam
+	TensorRTLLM  TensorRTLLMParam
+	LlamaCpp     LlamaCppParam
 	// Disable the tensor parallelism
 	DisableTensorParallelism bool
 }
@@ -172,6 +203,51 @@ type VLLMParam struct {
 	DisallowLoRA bool
 }
 
+type SGLangParam struct {
+	// BaseCommand is the command used to start the inference server. Models without
+	// a BaseCommand are not supported by the SGLang runtime.
+	BaseCommand string
+	// The model name used in the openai serving API.
+	// see https://platform.openai.com/docs/api-reference/chat/create#chat-create-model.
+	ModelName string
+	// Parameters for running the model inference.
+	ModelRunParams map[string]string
+	// Indicates if SGLang supports LoRA (Low-Rank Adaptation) for this model.
+	// doc: https://docs.sglang.ai/backend/lora.html
+	DisallowLoRA bool
+}
+
+type TensorRTLLMParam struct {
+	// BaseCommand is the command used to serve the compiled engine. Models without
+	// a BaseCommand are not supported by the TensorRT-LLM runtime.
+	BaseCommand string
+	// EngineBuildCommand is the command used to compile the engine of the model.
+	EngineBuildCommand string
+	// Parameters for compiling the engine, e.g. the maximum batch size and sequence length.
+	EngineBuildParams map[string]string
+	// Parameters for running the model inference.
+	ModelRunParams map[string]string
+}
+
+type LlamaCppParam struct {
+	// BaseCommand is the command used to start the llama.cpp server. Models without
+	// a BaseCommand are not supported by the llama.cpp runtime.
+	BaseCommand string
+	// The model name used in the openai serving API.
+	ModelName string
+	// GGUFRepo is the huggingface repository of the quantized GGUF weights.
+	GGUFRepo string
+	// GGUFFile is the GGUF weights file in GGUFRepo, downloaded when the server starts.
+	GGUFFile string
+	// GGUFRevision is the commit of GGUFRepo the GGUF weights are downloaded from. The latest
+	// commit is downloaded if empty.
+	GGUFRevision string
+	// GGUFFileSize is the size of GGUFFile, used to derive the CPU and memory requests.
+	GGUFFileSize string
+	// Parameters for running the model inference.
+	ModelRunParams map[string]string
+}
+
 func (p *PresetParam) DeepCopy() *PresetParam {
 	if p == nil {
 		return nil
@@ -190,6 +266,9 @@ func (rp *RuntimeParam) DeepCopy() RuntimeParam {
 	out := *rp
 	out.Transformers = rp.Transformers.DeepCopy()
 	out.VLLM = rp.VLLM.DeepCopy()
+	out.SGLang = rp.SGLang.DeepCopy()
+	out.TensorRTLLM = rp.TensorRTLLM.DeepCopy()
+	out.LlamaCpp = rp.LlamaCpp.DeepCopy()
 	return out
 }
 
@@ -214,6 +293,34 @@ func (v *VLLMParam) DeepCopy() VLLMParam {
 	return out
 }
 
+func (s *SGLangParam) DeepCopy() SGLangParam {
+	if s == nil {
+		return SGLangParam{}
+	}
+	out := *s
+	out.ModelRunParams = maps.Clone(s.ModelRunParams)
+	return out
+}
+
+func (t *TensorRTLLMParam) DeepCopy() TensorRTLLMParam {
+	if t == nil {
+		return TensorRTLLMParam{}
+	}
+	out := *t
+	out.EngineBuildParams = maps.Clone(t.EngineBuildParams)
+	out.ModelRunParams = maps.Clone(t.ModelRunParams)
+	return out
+}
+
+func (l *LlamaCppParam) DeepCopy() LlamaCppParam {
+	if l == nil {
+		return LlamaCppParam{}
+	}
+	out := *l
+	out.ModelRunParams = maps.Clone(l.ModelRunParams)
+	return out
+}
+
 // RuntimeContext defines the runtime context for a model.
 type RuntimeContext struct {
 	RuntimeName          RuntimeName
@@ -223,6 +330,8 @@ type RuntimeContext struct {
 	NumNodes             int
 	WorkspaceMetadata    metav1.ObjectMeta
 	DistributedInference bool
+	// Host is the address the inference server binds to, all interfaces if empty.
+	Host string
 	RuntimeContextExtraArguments
 }
 
@@ -231,18 +340,39 @@ type RuntimeContextExtraArguments struct {
 	AdapterStrengthEnabled bool
 }
 
+// ServedModelName returns the name the runtime serves the model as in the OpenAI API.
+func (p *PresetParam) ServedModelName(runtimeName RuntimeName) string {
+	switch runtimeName {
+	case RuntimeNameSGLang:
+		return p.SGLang.ModelName
+	case RuntimeNameLlamaCpp:
+		return p.LlamaCpp.ModelName
+	default:
+		return p.VLLM.ModelName
+	}
+}
+
 func (p *PresetParam) GetInferenceCommand(rc RuntimeContext) []string {
 	switch rc.RuntimeName {
 	case RuntimeNameHuggingfaceTransformers:
-		return p.buildHuggingfaceInferenceCommand()
+		return p.buildHuggingfaceInferenceCommand(rc)
 	case RuntimeNameVLLM:
 		return p.buildVLLMInferenceCommand(rc)
+	case RuntimeNameSGLang:
+		return p.buildSGLangInferenceCommand(rc)
+	case RuntimeNameTensorRTLLM:
+		return p.buildTensorRTLLMInferenceCommand(rc)
+	case RuntimeNameLlamaCpp:
+		return p.buildLlamaCppInferenceCommand(rc)
 	default:
 		return nil
 	}
 }
 
-func (p *PresetParam) buildHuggingfaceInferenceCommand() []string {
+func (p *PresetParam) buildHuggingfaceInferenceCommand(rc RuntimeContext) []string {
+	if rc.Host != "" {
+		p.Transformers.ModelRunParams["host"] = rc.Host
+	}
 	if p.DownloadAtRuntime {
 		repoId, revision, _ := utils.ParseHuggingFaceModelVersion(p.Version)
 		p.Transformers.ModelRunParams["pretrained_model_name_or_path"] = repoId
@@ -273,6 +403,9 @@ func (p *PresetParam) buildVLLMInferenceCommand(rc RuntimeContext) []string {
 	if !p.VLLM.DisallowLoRA && rc.AdaptersEnabled {
 		p.VLLM.ModelRunParams["enable-lora"] = ""
 	}
+	if rc.Host != "" {
+		p.VLLM.ModelRunParams["host"] = rc.Host
+	}
 	if p.DownloadAtRuntime {
 		repoId, revision, _ := utils.ParseHuggingFaceModelVersion(p.Version)
 		p.VLLM.ModelRunParams["model"] = repoId
@@ -326,9 +459,129 @@ func (p *PresetParam) buildVLLMInferenceCommand(rc RuntimeContext) []string {
 	return utils.ShellCmd(result)
 }
 
+func (p *PresetParam) buildSGLangInferenceCommand(rc RuntimeContext) []string {
+	if p.SGLang.ModelRunParams == nil {
+		p.SGLang.ModelRunParams = make(map[string]string)
+	}
+	if p.SGLang.ModelName != "" {
+		p.SGLang.ModelRunParams["served-model-name"] = p.SGLang.ModelName
+	}
+	if !p.DisableTensorParallelism {
+		p.SGLang.ModelRunParams["tp-size"] = strconv.Itoa(rc.SKUNumGPUs)
+	}
+	if rc.Host != "" {
+		p.SGLang.ModelRunParams["host"] = rc.Host
+	}
+	if p.DownloadAtRuntime {
+		repoId, revision, _ := utils.ParseHuggingFaceModelVersion(p.Version)
+		p.SGLang.ModelRunParams["model-path"] = repoId
+		if revision != "" {
+			p.SGLang.ModelRunParams["revision"] = revision
+		}
+		p.SGLang.ModelRunParams["download-dir"] = utils.DefaultWeightsVolumePath
+	}
+	// SGLang statically allocates the KV cache pool like vLLM, the same memory has to be reserved.
+	memFraction := getGPUMemoryUtilForVLLM(rc.GPUConfig)
+	p.SGLang.ModelRunParams["mem-fraction-static"] = strconv.FormatFloat(memFraction, 'f', 2, 64)
+	if rc.ConfigVolume != nil {
+		p.SGLang.ModelRunParams["kaito-config-file"] = path.Join(rc.ConfigVolume.MountPath, ConfigfileNameVLLM)
+	}
+
+	modelCommand := utils.BuildCmdStr(p.SGLang.BaseCommand, p.SGLang.ModelRunParams)
+	return utils.ShellCmd(modelCommand)
+}
+
+func (p *PresetParam) buildTensorRTLLMInferenceCommand(rc RuntimeContext) []string {
+	if p.TensorRTLLM.ModelRunParams == nil {
+		p.TensorRTLLM.ModelRunParams = make(map[string]string)
+	}
+	if rc.Host != "" {
+		// Overrides the host of the base command, the last value wins.
+		p.TensorRTLLM.ModelRunParams["host"] = rc.Host
+	}
+	// The tokenizer is saved next to the engine, the raw weights are not needed to serve it.
+	p.TensorRTLLM.ModelRunParams["tokenizer"] = utils.DefaultEnginePath
+
+	modelCommand := utils.BuildCmdStr(p.TensorRTLLM.BaseCommand+" "+utils.DefaultEnginePath, p.TensorRTLLM.ModelRunParams)
+	return utils.ShellCmd(modelCommand)
+}
+
+// GetTensorRTLLMEngineBuildCommand returns the command compiling the TensorRT-LLM engine of the
+// model into utils.DefaultEnginePath, for the number of GPUs of the runtime context.
+func (p *PresetParam) GetTensorRTLLMEngineBuildCommand(rc RuntimeContext) string {
+	if p.TensorRTLLM.EngineBuildParams == nil {
+		p.TensorRTLLM.EngineBuildParams = make(map[string]string)
+	}
+	if !p.DisableTensorParallelism {
+		p.TensorRTLLM.EngineBuildParams["tp-size"] = strconv.Itoa(rc.SKUNumGPUs)
+	}
+	if p.DownloadAtRuntime {
+		repoId, revision, _ := utils.ParseHuggingFaceModelVersion(p.Version)
+		p.TensorRTLLM.EngineBuildParams["model"] = repoId
+		if revision != "" {
+			p.TensorRTLLM.EngineBuildParams["revision"] = revision
+		}
+		p.TensorRTLLM.EngineBuildParams["download-dir"] = utils.DefaultWeightsVolumePath
+	}
+	p.TensorRTLLM.EngineBuildParams["output-dir"] = utils.DefaultEnginePath
+
+	return utils.BuildCmdStr(p.TensorRTLLM.EngineBuildCommand, p.TensorRTLLM.EngineBuildParams)
+}
+
+func (p *PresetParam) buildLlamaCppInferenceCommand(rc RuntimeContext) []string {
+	if p.LlamaCpp.ModelRunParams == nil {
+		p.LlamaCpp.ModelRunParams = make(map[string]string)
+	}
+	if rc.Host != "" {
+		// Overrides the host of the base command, the last value wins.
+		p.LlamaCpp.ModelRunParams["host"] = rc.Host
+	}
+	if p.LlamaCpp.GGUFRevision != "" {
+		// llama.cpp cannot download a revision of a huggingface repository, the file is downloaded
+		// from the URL of the revision instead.
+		p.LlamaCpp.ModelRunParams["model-url"] = fmt.Sprintf("https://huggingface.co/%s/resolve/%s/%s",
+			p.LlamaCpp.GGUFRepo, p.LlamaCpp.GGUFRevision, p.LlamaCpp.GGUFFile)
+	} else {
+		p.LlamaCpp.ModelRunParams["hf-repo"] = p.LlamaCpp.GGUFRepo
+		p.LlamaCpp.ModelRunParams["hf-file"] = p.LlamaCpp.GGUFFile
+	}
+	if p.LlamaCpp.ModelName != "" {
+		p.LlamaCpp.ModelRunParams["alias"] = p.LlamaCpp.ModelName
+	}
+	// An invalid GGUF file size is rejected by Validate, the server then uses all the CPUs of the node.
+	if cpus, _, err := p.GetLlamaCppResourceRequirements(); err == nil {
+		p.LlamaCpp.ModelRunParams["threads"] = strconv.Itoa(cpus)
+	}
+
+	modelCommand := utils.BuildCmdStr(p.LlamaCpp.BaseCommand, p.LlamaCpp.ModelRunParams)
+	return utils.ShellCmd(modelCommand)
+}
+
+// GetLlamaCppResourceRequirements returns the number of CPUs and the memory requested by
+// the llama.cpp runtime, derived from the size of the GGUF weights.
+func (p *PresetParam) GetLlamaCppResourceRequirements() (int, resource.Quantity, error) {
+	fileSize, err := resource.ParseQuantity(p.LlamaCpp.GGUFFileSize)
+	if err != nil {
+		return 0, resource.Quantity{}, fmt.Errorf("invalid GGUF file size %q of model %s: %w", p.LlamaCpp.GGUFFileSize, p.Name, err)
+	}
+	if fileSize.Sign() <= 0 {
+		return 0, resource.Quantity{}, fmt.Errorf("invalid GGUF file size %q of model %s: must be positive", p.LlamaCpp.GGUFFileSize, p.Name)
+	}
+	fileSizeGiB := int(math.Ceil(float64(fileSize.Value()) / consts.GiBToBytes))
+
+	memory := resource.MustParse(fmt.Sprintf("%dGi", fileSizeGiB))
+	memory.Add(ReservedLlamaCppMemory)
+	return max(MinLlamaCppCPUs, fileSizeGiB), memory, nil
+}
+
 func (p *PresetParam) Validate(rc RuntimeContext) error {
 	var errs []string
 	switch rc.RuntimeName {
+	case RuntimeNameHuggingfaceTransformers:
+		// The transformers inference server only runs text-generation pipelines.
+		if p.ModelType == ModelTypeTextEmbedding || p.ModelType == ModelTypeImageTextToText {
+			errs = append(errs, fmt.Sprintf("transformers does not support %s models: %s", p.ModelType, p.Name))
+		}
 	case RuntimeNameVLLM:
 		if rc.AdaptersEnabled && p.VLLM.DisallowLoRA {
 			errs = append(errs, fmt.Sprintf("vLLM does not support LoRA adapters for this model: %s", p.VLLM.ModelName))
@@ -336,6 +589,32 @@ func (p *PresetParam) Validate(rc RuntimeContext) error {
 		if rc.AdapterStrengthEnabled {
 			errs = append(errs, "vLLM does not support adapter strength")
 		}
+	case RuntimeNameSGLang:
+		if p.SGLang.BaseCommand == "" {
+			errs = append(errs, fmt.Sprintf("SGLang does not support this model: %s", p.Name))
+		}
+		if rc.AdaptersEnabled && p.SGLang.DisallowLoRA {
+			errs = append(errs, fmt.Sprintf("SGLang does not support LoRA adapters for this model: %s", p.SGLang.ModelName))
+		}
+		if rc.AdapterStrengthEnabled {
+			errs = append(errs, "SGLang does not support adapter strength")
+		}
+	case RuntimeNameTensorRTLLM:
+		if p.TensorRTLLM.BaseCommand == "" || p.TensorRTLLM.EngineBuildCommand == "" {
+			errs = append(errs, fmt.Sprintf("TensorRT-LLM does not support this model: %s", p.Name))
+		}
+		if rc.AdaptersEnabled {
+			errs = append(errs, "TensorRT-LLM does not support LoRA adapters")
+		}
+	case RuntimeNameLlamaCpp:
+		if p.LlamaCpp.BaseCommand == "" {
+			errs = append(errs, fmt.Sprintf("llama.cpp does not support this model: %s", p.Name))
+		} else if _, _, err := p.GetLlamaCppResourceRequirements(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if rc.AdaptersEnabled {
+			errs = append(errs, "llama.cpp does not support LoRA adapters")
+		}
 	}
 	if len(errs) > 0 {
 		return fmt.Errorf("%s", strings.Join(errs, "; "))
@@ -344,6 +623,9 @@ func (p *PresetParam) Validate(rc RuntimeContext) error {
 }
 
 func getGPUMemoryUtilForVLLM(gpuConfig *sku.GPUConfig) float64 {
+	if gpuConfig.IsPartitioned() {
+		return getGPUMemoryUtilForVLLMPartition(gpuConfig)
+	}
 	if gpuConfig == nil || gpuConfig.GPUMemGB <= 0 || gpuConfig.GPUCount <= 0 {
 		return DefaultMemoryUtilVLLM
 	}
@@ -359,3 +641,30 @@ func getGPUMemoryUtilForVLLM(gpuConfig *sku.GPUConfig) float64 {
 	util := math.Floor((1.0-float64(ReservedNonKVCacheMemory.Value())/gpuMemPerGPU)*100) / 100
 	return math.Min(util, UpperMemoryUtilVLLM)
 }
+
+// getGPUMemoryUtilForVLLMPartition returns the memory utilization for a GPU partition. vLLM sees a MIG
+// instance as a whole device, but sees the whole GPU with time-slicing and must be limited to the share
+// of the replica.
+func getGPUMemoryUtilForVLLMPartition(gpuConfig *sku.GPUConfig) float64 {
+	availableMem := float64(gpuConfig.PerGPUMemGB()) * consts.GiBToBytes
+	deviceMem := availableMem
+	if gpuConfig.MIGProfile == "" && gpuConfig.GPUCount > 0 {
+		deviceMem = float64(gpuConfig.GPUMemGB/gpuConfig.GPUCount) * consts.GiBToBytes
+	}
+	if deviceMem <= 0 {
+		return DefaultMemoryUtilVLLM
+	}
+
+	reserved := float64(ReservedNonKVCacheMemory.Value())
+	if reserved >= availableMem {
+		if gpuConfig.MIGProfile != "" {
+			// looks impossible, just prevent this case
+			return DefaultMemoryUtilVLLM
+		}
+		// never use more than the share of the replica on a time-sliced GPU
+		return math.Floor(availableMem/deviceMem*100) / 100
+	}
+
+	util := math.Floor((availableMem-reserved)/deviceMem*100) / 100
+	return math.Min(util, UpperMemoryUtilVLLM)
+}
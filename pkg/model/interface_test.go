@@ -70,6 +70,33 @@ func TestGetGPUMemoryUtilForVLLM(t *testing.T) {
 			},
 			expected: 0.95,
 		},
+		{
+			name: "A100 MIG 1g.10gb",
+			gpuConfig: &sku.GPUConfig{
+				GPUMemGB:   80,
+				GPUCount:   1,
+				MIGProfile: "1g.10gb",
+			},
+			expected: 0.85,
+		},
+		{
+			name: "A100 x 4 time-sliced into 4 replicas",
+			gpuConfig: &sku.GPUConfig{
+				GPUMemGB:            320,
+				GPUCount:            4,
+				TimeSlicingReplicas: 4,
+			},
+			expected: 0.23,
+		},
+		{
+			name: "V100 time-sliced into a share smaller than the reserved memory",
+			gpuConfig: &sku.GPUConfig{
+				GPUMemGB:            16,
+				GPUCount:            1,
+				TimeSlicingReplicas: 16,
+			},
+			expected: 0.06,
+		},
 		{
 			name: "Invalid",
 			gpuConfig: &sku.GPUConfig{
@@ -89,3 +116,73 @@ func TestGetGPUMemoryUtilForVLLM(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTextEmbeddingModel(t *testing.T) {
+	params := &PresetParam{
+		Metadata: Metadata{Name: "test-embedding", ModelType: ModelTypeTextEmbedding},
+		RuntimeParam: RuntimeParam{
+			VLLM: VLLMParam{BaseCommand: "python3 /workspace/vllm/inference_api.py", DisallowLoRA: true},
+		},
+	}
+
+	if err := params.Validate(RuntimeContext{RuntimeName: RuntimeNameVLLM}); err != nil {
+		t.Errorf("expected text-embedding model to be supported by vLLM, got %v", err)
+	}
+	if err := params.Validate(RuntimeContext{RuntimeName: RuntimeNameHuggingfaceTransformers}); err == nil {
+		t.Errorf("expected text-embedding model to be rejected by transformers")
+	}
+	if err := params.Validate(RuntimeContext{
+		RuntimeName:                  RuntimeNameVLLM,
+		RuntimeContextExtraArguments: RuntimeContextExtraArguments{AdaptersEnabled: true},
+	}); err == nil {
+		t.Errorf("expected adapters to be rejected for text-embedding model")
+	}
+}
+
+func TestValidateTransformersModelTypes(t *testing.T) {
+	tests := []struct {
+		modelType string
+		wantErr   bool
+	}{
+		{modelType: ModelTypeTextGeneration, wantErr: false},
+		{modelType: ModelTypeTextEmbedding, wantErr: true},
+		{modelType: ModelTypeImageTextToText, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.modelType, func(t *testing.T) {
+			params := &PresetParam{Metadata: Metadata{Name: "test-model", ModelType: tt.modelType}}
+			err := params.Validate(RuntimeContext{RuntimeName: RuntimeNameHuggingfaceTransformers})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLlamaCppGGUFFileSize(t *testing.T) {
+	tests := []struct {
+		fileSize string
+		wantErr  bool
+	}{
+		{fileSize: "2.2Gi", wantErr: false},
+		{fileSize: "", wantErr: true},
+		{fileSize: "2.2GB", wantErr: true},
+		{fileSize: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileSize, func(t *testing.T) {
+			params := &PresetParam{
+				Metadata: Metadata{Name: "test-model", ModelType: ModelTypeTextGeneration},
+				RuntimeParam: RuntimeParam{
+					LlamaCpp: LlamaCppParam{BaseCommand: "/app/llama-server", GGUFFileSize: tt.fileSize},
+				},
+			}
+			err := params.Validate(RuntimeContext{RuntimeName: RuntimeNameLlamaCpp})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// Building the command never panics, even with an invalid file size.
+			_ = params.GetInferenceCommand(RuntimeContext{RuntimeName: RuntimeNameLlamaCpp})
+		})
+	}
+}
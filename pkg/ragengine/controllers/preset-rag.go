@@ -69,6 +69,11 @@ var (
 			Operator: corev1.TolerationOpExists,
 			Key:      resources.CapacityNvidiaGPU,
 		},
+		{
+			Effect:   corev1.TaintEffectNoSchedule,
+			Operator: corev1.TolerationOpExists,
+			Key:      resources.CapacityAMDGPU,
+		},
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Value:    consts.GPUString,
@@ -129,10 +134,10 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *v1alpha1.RAGEngine, revi
 
 		resourceReq = corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+				resources.GPUResourceName(gpuConfig.Vendor()): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 			},
 			Limits: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+				resources.GPUResourceName(gpuConfig.Vendor()): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 			},
 		}
 
@@ -27,6 +27,7 @@ import (
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -49,6 +50,8 @@ import (
 	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/exposure"
+	"github.com/kaito-project/kaito/pkg/utils/networkpolicy"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 )
@@ -129,7 +132,11 @@ func (c *RAGEngineReconciler) addRAGEngine(ctx context.Context, ragEngineObj *ka
 		}
 		return reconcile.Result{}, err
 	}
-	if err := c.ensureService(ctx, ragEngineObj); err != nil {
+	err = c.ensureService(ctx, ragEngineObj)
+	if err == nil {
+		err = c.ensureNetworkPolicy(ctx, ragEngineObj)
+	}
+	if err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1alpha1.RAGEngineConditionTypeSucceeded, metav1.ConditionFalse,
 			"ragEngineFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update ragEngine status", "ragEngine", klog.KObj(ragEngineObj))
@@ -155,14 +162,11 @@ func (c *RAGEngineReconciler) addRAGEngine(ctx context.Context, ragEngineObj *ka
 }
 
 func (c *RAGEngineReconciler) ensureService(ctx context.Context, ragObj *kaitov1alpha1.RAGEngine) error {
-	serviceType := corev1.ServiceTypeClusterIP
-	ragAnnotations := ragObj.GetAnnotations()
-
-	if len(ragAnnotations) != 0 {
-		val, found := ragAnnotations[kaitov1alpha1.AnnotationEnableLB]
-		if found && val == "True" {
-			serviceType = corev1.ServiceTypeLoadBalancer
-		}
+	exposureSpec := exposure.FromV1alpha1(ragObj.Spec.Exposure)
+	serviceType := exposure.ServiceType(exposureSpec, ragObj.GetAnnotations()[kaitov1alpha1.AnnotationEnableLB] == "True")
+	serviceAnnotations, err := exposure.ServiceAnnotations(exposureSpec)
+	if err != nil {
+		return err
 	}
 
 	// Ensure Service for index and query
@@ -171,20 +175,64 @@ func (c *RAGEngineReconciler) ensureService(ctx context.Context, ragObj *kaitov1
 	serviceName := ragObj.Name
 
 	existingSVC := &corev1.Service{}
-	err := resources.GetResource(ctx, serviceName, ragObj.Namespace, c.Client, existingSVC)
+	err = resources.GetResource(ctx, serviceName, ragObj.Namespace, c.Client, existingSVC)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
-	} else {
-		return nil
-	}
-	serviceObj := manifests.GenerateRAGServiceManifest(ragObj, serviceName, serviceType)
-	if err := resources.CreateResource(ctx, serviceObj, c.Client); err != nil {
-		return err
+		serviceObj := manifests.GenerateRAGServiceManifest(ragObj, serviceName, serviceType)
+		exposure.UpdateService(serviceObj, serviceType, serviceAnnotations)
+		if err := resources.CreateResource(ctx, serviceObj, c.Client); err != nil {
+			return err
+		}
+	} else if exposure.UpdateService(existingSVC, serviceType, serviceAnnotations) {
+		if err := c.Update(ctx, existingSVC); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return exposure.Ensure(ctx, c.Client, exposure.Target{
+		Owner:       *metav1.NewControllerRef(ragObj, kaitov1alpha1.GroupVersion.WithKind("RAGEngine")),
+		Namespace:   ragObj.Namespace,
+		ServiceName: serviceName,
+		ServicePort: 80,
+	}, exposureSpec)
+}
+
+// ensureNetworkPolicy creates or updates the NetworkPolicy of the RAG engine pods, and deletes it once the
+// network policy of the RAG engine is removed.
+func (c *RAGEngineReconciler) ensureNetworkPolicy(ctx context.Context, ragObj *kaitov1alpha1.RAGEngine) error {
+	var desired *networkingv1.NetworkPolicy
+	if ragObj.Spec.NetworkPolicy != nil {
+		var urls []string
+		if ragObj.Spec.InferenceService != nil {
+			urls = append(urls, ragObj.Spec.InferenceService.URL)
+		}
+		var egress []networkingv1.NetworkPolicyEgressRule
+		if embedding := ragObj.Spec.Embedding; embedding != nil {
+			switch {
+			case embedding.Remote != nil:
+				urls = append(urls, embedding.Remote.URL)
+			case embedding.Workspace != nil:
+				urls = append(urls, manifests.WorkspaceEmbeddingURL(ragObj))
+			case embedding.Local != nil && embedding.Local.ModelID != "":
+				// The embedding model is downloaded from huggingface during startup.
+				egress = append(egress, networkingv1.NetworkPolicyEgressRule{Ports: networkpolicy.TCPPorts(443)})
+			}
+		}
+		for _, u := range urls {
+			rule, err := networkpolicy.EgressRuleForURL(ctx, c.Client, ragObj.Namespace, u)
+			if err != nil {
+				return err
+			}
+			egress = append(egress, rule)
+		}
+
+		serviceType := exposure.ServiceType(exposure.FromV1alpha1(ragObj.Spec.Exposure), ragObj.GetAnnotations()[kaitov1alpha1.AnnotationEnableLB] == "True")
+		desired = manifests.GenerateRAGNetworkPolicyManifest(ragObj, serviceType, PortInferenceServer, egress)
+	}
+	return networkpolicy.Ensure(ctx, c.Client, client.ObjectKeyFromObject(ragObj),
+		*metav1.NewControllerRef(ragObj, kaitov1alpha1.GroupVersion.WithKind("RAGEngine")), desired)
 }
 
 func (c *RAGEngineReconciler) applyRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine) error {
@@ -528,6 +576,10 @@ func (c *RAGEngineReconciler) ensureNodePlugins(ctx context.Context, ragEngineOb
 	tick := timeClock.NewTicker(consts.NodePluginInstallTimeout)
 	defer tick.Stop()
 
+	// the device plugin to wait for is determined by the GPU vendor of the instance type
+	gpuConfig, _ := utils.GetGPUConfigBySKU(ragEngineObj.Spec.Compute.InstanceType)
+	vendor := gpuConfig.Vendor()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -542,14 +594,14 @@ func (c *RAGEngineReconciler) ensureNodePlugins(ctx context.Context, ragEngineOb
 				return err
 			}
 
-			//Nvidia Plugin
-			if found := resources.CheckNvidiaPlugin(ctx, freshNode); found {
+			// GPU device plugin
+			if found := resources.CheckGPUPlugin(ctx, freshNode, gpuConfig); found {
 				return nil
 			}
 
-			err = resources.UpdateNodeWithLabel(ctx, freshNode, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client)
+			err = resources.UpdateNodeWithLabel(ctx, freshNode, resources.LabelKeyNvidia, resources.GPULabelValue(vendor), c.Client)
 			if apierrors.IsNotFound(err) {
-				klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", freshNode.Name)
+				klog.ErrorS(err, "gpu plugin cannot be installed, node not found", "node", freshNode.Name, "vendor", vendor)
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, ragEngineObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
 					"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(ragEngineObj))
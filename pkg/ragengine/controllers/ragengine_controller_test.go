@@ -28,9 +28,11 @@ import (
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -42,6 +44,7 @@ import (
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
@@ -628,6 +631,7 @@ func TestEnsureService(t *testing.T) {
 
 		"Existing service is found for RAGEngine": {
 			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateRAGServiceManifest(test.MockRAGEngineWithPreset, test.MockRAGEngineWithPreset.Name, corev1.ServiceTypeClusterIP))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 			},
 			expectedError: nil,
@@ -635,12 +639,53 @@ func TestEnsureService(t *testing.T) {
 			verifyCalls: func(c *test.MockClient) {
 				c.AssertNumberOfCalls(t, "List", 0)
 				c.AssertNumberOfCalls(t, "Create", 0)
-				c.AssertNumberOfCalls(t, "Get", 1)
+				c.AssertNumberOfCalls(t, "Get", 3)
 				c.AssertNumberOfCalls(t, "Delete", 0)
 				c.AssertNumberOfCalls(t, "Update", 0)
 			},
 		},
 
+		"Existing service is switched to an internal load balancer": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateRAGServiceManifest(test.MockRAGEngineWithPreset, test.MockRAGEngineWithPreset.Name, corev1.ServiceTypeClusterIP))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(svc *corev1.Service) bool {
+					return svc.Spec.Type == corev1.ServiceTypeLoadBalancer &&
+						svc.Annotations["service.beta.kubernetes.io/azure-load-balancer-internal"] == "true"
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+			ragengine: func() v1alpha1.RAGEngine {
+				r := test.MockRAGEngineWithPreset.DeepCopy()
+				r.Spec.Exposure = &v1alpha1.ExposureSpec{Type: v1alpha1.ExposureTypeLoadBalancer, Internal: true}
+				return *r
+			}(),
+			verifyCalls: func(c *test.MockClient) {
+				c.AssertNumberOfCalls(t, "Create", 0)
+				c.AssertNumberOfCalls(t, "Update", 1)
+			},
+		},
+
+		"Creates an ingress for the RAGEngine": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.MatchedBy(func(ingress *networkingv1.Ingress) bool {
+					return len(ingress.Spec.Rules) == 1 && ingress.Spec.Rules[0].Host == "rag.example.com"
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+			ragengine: func() v1alpha1.RAGEngine {
+				r := test.MockRAGEngineWithPreset.DeepCopy()
+				r.Spec.Exposure = &v1alpha1.ExposureSpec{Type: v1alpha1.ExposureTypeIngress, Hostname: "rag.example.com"}
+				return *r
+			}(),
+			verifyCalls: func(c *test.MockClient) {
+				c.AssertNumberOfCalls(t, "Create", 2)
+				c.AssertNumberOfCalls(t, "Update", 0)
+			},
+		},
+
 		"Service creation fails": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(test.NotFoundError())
@@ -667,7 +712,7 @@ func TestEnsureService(t *testing.T) {
 			verifyCalls: func(c *test.MockClient) {
 				c.AssertNumberOfCalls(t, "List", 0)
 				c.AssertNumberOfCalls(t, "Create", 1)
-				c.AssertNumberOfCalls(t, "Get", 4)
+				c.AssertNumberOfCalls(t, "Get", 6)
 				c.AssertNumberOfCalls(t, "Delete", 0)
 				c.AssertNumberOfCalls(t, "Update", 0)
 			},
@@ -676,8 +721,11 @@ func TestEnsureService(t *testing.T) {
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
+			t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 			mockClient := test.NewClient()
 			tc.callMocks(mockClient)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.Ingress{}), mock.Anything).Return(test.NotFoundError())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(test.NotFoundError())
 
 			reconciler := &RAGEngineReconciler{
 				Client: mockClient,
@@ -781,7 +829,11 @@ func TestReconcile(t *testing.T) {
 						*dep = *deployment
 					}).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.RAGEngine{}), mock.Anything).Return(nil)
+				c.CreateOrUpdateObjectInMap(manifests.GenerateRAGServiceManifest(ragengine, ragengine.Name, corev1.ServiceTypeClusterIP))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.Ingress{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(test.NotFoundError())
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).
 					Run(func(args mock.Arguments) {
 						dep := args.Get(2).(*appsv1.Deployment)
@@ -14,6 +14,8 @@
 package manifests
 
 import (
+	"fmt"
+
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -137,6 +139,28 @@ func RAGSetEnv(ragEngineObj *kaitov1alpha1.RAGEngine) []corev1.EnvVar {
 	} else if ragEngineObj.Spec.Embedding.Remote != nil {
 		embeddingType = "remote"
 		// TODO: Model ID Env
+	} else if ragEngineObj.Spec.Embedding.Workspace != nil {
+		embeddingType = "workspace"
+		// The inference service of a workspace is exposed by a service named after the workspace.
+		workspaceURLEnv := corev1.EnvVar{
+			Name:  "WORKSPACE_EMBEDDING_URL",
+			Value: WorkspaceEmbeddingURL(ragEngineObj),
+		}
+		envs = append(envs, workspaceURLEnv)
+		if ragEngineObj.Spec.Embedding.Workspace.AccessSecret != "" {
+			accessSecretEnv := corev1.EnvVar{
+				Name: "WORKSPACE_EMBEDDING_ACCESS_SECRET",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: ragEngineObj.Spec.Embedding.Workspace.AccessSecret,
+						},
+						Key: "WORKSPACE_EMBEDDING_ACCESS_SECRET",
+					},
+				},
+			}
+			envs = append(envs, accessSecretEnv)
+		}
 	}
 	embeddingTypeEnv := corev1.EnvVar{
 		Name:  "EMBEDDING_TYPE",
@@ -173,6 +197,11 @@ func RAGSetEnv(ragEngineObj *kaitov1alpha1.RAGEngine) []corev1.EnvVar {
 	return envs
 }
 
+// WorkspaceEmbeddingURL returns the URL of the inference service of the embedding workspace of the RAG engine.
+func WorkspaceEmbeddingURL(ragEngineObj *kaitov1alpha1.RAGEngine) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local", ragEngineObj.Spec.Embedding.Workspace.Name, ragEngineObj.Namespace)
+}
+
 func GenerateRAGServiceManifest(ragObj *kaitov1alpha1.RAGEngine, serviceName string, serviceType corev1.ServiceType) *corev1.Service {
 	selector := map[string]string{
 		kaitov1alpha1.LabelRAGEngineName: ragObj.Name,
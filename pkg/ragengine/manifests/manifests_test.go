@@ -18,8 +18,10 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/networkpolicy"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -166,3 +168,74 @@ func TestGenerateRAGServiceManifest(t *testing.T) {
 		}
 	})
 }
+
+func TestRAGSetEnvWithWorkspaceEmbedding(t *testing.T) {
+	ragEngine := test.MockRAGEngineWithPreset.DeepCopy()
+	ragEngine.Spec.Embedding = &kaitov1alpha1.EmbeddingSpec{
+		Workspace: &kaitov1alpha1.WorkspaceEmbeddingSpec{Name: "workspace-bge"},
+	}
+
+	envs := map[string]string{}
+	for _, env := range RAGSetEnv(ragEngine) {
+		envs[env.Name] = env.Value
+	}
+
+	if envs["EMBEDDING_TYPE"] != "workspace" {
+		t.Errorf("Expected EMBEDDING_TYPE workspace, got %s", envs["EMBEDDING_TYPE"])
+	}
+	expectedURL := "http://workspace-bge." + ragEngine.Namespace + ".svc.cluster.local"
+	if envs["WORKSPACE_EMBEDDING_URL"] != expectedURL {
+		t.Errorf("Expected WORKSPACE_EMBEDDING_URL %s, got %s", expectedURL, envs["WORKSPACE_EMBEDDING_URL"])
+	}
+	if _, found := envs["WORKSPACE_EMBEDDING_ACCESS_SECRET"]; found {
+		t.Error("Expected no WORKSPACE_EMBEDDING_ACCESS_SECRET without an access secret")
+	}
+
+	ragEngine.Spec.Embedding.Workspace.AccessSecret = "rag-api-key"
+	for _, env := range RAGSetEnv(ragEngine) {
+		if env.Name != "WORKSPACE_EMBEDDING_ACCESS_SECRET" {
+			continue
+		}
+		if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil || env.ValueFrom.SecretKeyRef.Name != "rag-api-key" ||
+			env.ValueFrom.SecretKeyRef.Key != "WORKSPACE_EMBEDDING_ACCESS_SECRET" {
+			t.Errorf("Expected WORKSPACE_EMBEDDING_ACCESS_SECRET from the rag-api-key secret, got %+v", env.ValueFrom)
+		}
+		return
+	}
+	t.Error("Expected WORKSPACE_EMBEDDING_ACCESS_SECRET with an access secret")
+}
+
+func TestGenerateRAGNetworkPolicyManifest(t *testing.T) {
+	ragEngine := test.MockRAGEngineWithPreset.DeepCopy()
+	ragEngine.Spec.NetworkPolicy = &kaitov1alpha1.NetworkPolicySpec{AllowedPodLabels: map[string]string{"role": "client"}}
+	inference := networkingv1.NetworkPolicyEgressRule{Ports: networkpolicy.TCPPorts(443)}
+
+	policy := GenerateRAGNetworkPolicyManifest(ragEngine, v1.ServiceTypeClusterIP, 5000, []networkingv1.NetworkPolicyEgressRule{inference})
+
+	expectedSelector := map[string]string{kaitov1alpha1.LabelRAGEngineName: ragEngine.Name}
+	if !reflect.DeepEqual(policy.Spec.PodSelector.MatchLabels, expectedSelector) {
+		t.Errorf("Expected pod selector %v, got %v", expectedSelector, policy.Spec.PodSelector.MatchLabels)
+	}
+	if len(policy.Spec.PolicyTypes) != 2 {
+		t.Errorf("Expected ingress and egress policy types, got %v", policy.Spec.PolicyTypes)
+	}
+
+	serviceRule := policy.Spec.Ingress[0]
+	if len(serviceRule.From) != 1 || !reflect.DeepEqual(serviceRule.From[0].PodSelector.MatchLabels, ragEngine.Spec.NetworkPolicy.AllowedPodLabels) {
+		t.Errorf("Expected the service to be reachable from the allowed pods, got %v", serviceRule.From)
+	}
+	if !reflect.DeepEqual(serviceRule.Ports, networkpolicy.TCPPorts(5000)) {
+		t.Errorf("Expected the service port 5000, got %v", serviceRule.Ports)
+	}
+
+	// DNS comes first, then the given rules.
+	if len(policy.Spec.Egress) != 2 || !reflect.DeepEqual(policy.Spec.Egress[0], networkpolicy.DNSEgressRule()) ||
+		!reflect.DeepEqual(policy.Spec.Egress[1], inference) {
+		t.Errorf("Unexpected egress rules %v", policy.Spec.Egress)
+	}
+
+	policy = GenerateRAGNetworkPolicyManifest(ragEngine, v1.ServiceTypeLoadBalancer, 5000, nil)
+	if len(policy.Spec.Ingress[0].From) != 0 {
+		t.Errorf("Expected the LoadBalancer service to be reachable from all sources, got %v", policy.Spec.Ingress[0].From)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/networkpolicy"
+)
+
+// GenerateRAGNetworkPolicyManifest generates the NetworkPolicy of the RAG engine pods. The service is reachable
+// from the allowed peers, or from all sources if it is a LoadBalancer service, and the pods can only reach DNS
+// and the destinations of the egress rules, i.e. the inference and embedding services.
+func GenerateRAGNetworkPolicyManifest(ragObj *kaitov1alpha1.RAGEngine, serviceType corev1.ServiceType, servicePort int32, egress []networkingv1.NetworkPolicyEgressRule) *networkingv1.NetworkPolicy {
+	selector := v1.LabelSelector{
+		MatchLabels: map[string]string{kaitov1alpha1.LabelRAGEngineName: ragObj.Name},
+	}
+	spec := networkpolicy.FromV1alpha1(ragObj.Spec.NetworkPolicy)
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      ragObj.Name,
+			Namespace: ragObj.Namespace,
+			Labels: map[string]string{
+				kaitov1alpha1.LabelRAGEngineName: ragObj.Name,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(ragObj, kaitov1alpha1.GroupVersion.WithKind("RAGEngine")),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: selector,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				networkpolicy.ServiceIngressRule(spec, ragObj.Namespace, serviceType, servicePort),
+				{
+					From: []networkingv1.NetworkPolicyPeer{{PodSelector: &selector}},
+				},
+			},
+			Egress: append([]networkingv1.NetworkPolicyEgressRule{networkpolicy.DNSEgressRule()}, egress...),
+		},
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// RoutingStrategyService forwards the requests to the service of the workspace, which spreads them randomly.
+	RoutingStrategyService = "service"
+	// RoutingStrategyPrefixAware forwards related requests to the same replica of the workspace.
+	RoutingStrategyPrefixAware = "prefix-aware"
+
+	// SessionHeader pins the requests of a session to the same replica.
+	SessionHeader = "X-Session-Id"
+)
+
+// Balancer picks the replica of a workspace serving a request. With prefix-aware routing, requests with the
+// same session header, or else with the same prompt prefix, are routed to the same replica, so that they hit
+// its prefix cache, unless the replica is overloaded compared to the least loaded replica. Requests for an
+// adapter spread across the replicas are routed to the replicas that loaded the adapter.
+type Balancer struct {
+	Replicas *ReplicaTable
+	// PrefixAware routes related requests to the same replica. Otherwise, only the requests for spread
+	// adapters are routed to a replica, the least loaded one, and the others to the service of the workspace.
+	PrefixAware bool
+	// PrefixLength is the number of bytes of the prompt hashed to pick the replica.
+	PrefixLength int
+	// ImbalanceThreshold is the number of requests the replica of a request may have over the least
+	// loaded replica before the request falls back to the least loaded replica.
+	ImbalanceThreshold float64
+}
+
+// Pick returns the replica of the workspace the request is routed to and the routing decision. The replicas
+// are limited to the given pods, if any. The affinity key identifies the related requests, requests without key
+// are routed to the least loaded replica. It returns false if no replica of the workspace is known.
+func (b *Balancer) Pick(workspace types.NamespacedName, pods []string, key, decision string) (Replica, string, bool) {
+	replicas := b.Replicas.Replicas(workspace)
+	if len(pods) > 0 {
+		replicas = slices.DeleteFunc(slices.Clone(replicas), func(r Replica) bool { return !slices.Contains(pods, r.Pod) })
+	}
+	if len(replicas) == 0 {
+		return Replica{}, DecisionService, false
+	}
+
+	least, leastLoad := replicas[0], b.Replicas.Load(replicas[0])
+	for _, r := range replicas[1:] {
+		if load := b.Replicas.Load(r); load < leastLoad {
+			least, leastLoad = r, load
+		}
+	}
+	if key == "" {
+		return least, DecisionLeastLoaded, true
+	}
+
+	// Rendezvous hashing keeps most keys on their replica when replicas are added or removed, and routes
+	// consistently across router replicas.
+	var preferred Replica
+	var best uint64
+	for i, r := range replicas {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(r.Pod))
+		if score := h.Sum64(); i == 0 || score > best {
+			preferred, best = r, score
+		}
+	}
+	if b.Replicas.Load(preferred) >= leastLoad+b.ImbalanceThreshold {
+		return least, DecisionLoadFallback, true
+	}
+	return preferred, decision, true
+}
+
+// affinityRequest holds the fields of an OpenAI request identifying related requests.
+type affinityRequest struct {
+	Messages json.RawMessage `json:"messages"`
+	Prompt   json.RawMessage `json:"prompt"`
+}
+
+// AffinityKey returns the key of the related requests of the request to the model and the routing decision:
+// the session header if set, or else the prefix of the messages or prompt, which starts with the same bytes
+// for the requests of a conversation or sharing a system prompt.
+func (b *Balancer) AffinityKey(r *http.Request, model string, body []byte) (string, string) {
+	if session := r.Header.Get(SessionHeader); session != "" {
+		return model + "\x00" + session, DecisionSession
+	}
+	var request affinityRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return "", DecisionLeastLoaded
+	}
+	prefix := request.Messages
+	if len(prefix) == 0 {
+		prefix = request.Prompt
+	}
+	if len(prefix) == 0 {
+		return "", DecisionLeastLoaded
+	}
+	if len(prefix) > b.PrefixLength {
+		prefix = prefix[:b.PrefixLength]
+	}
+	return model + "\x00" + string(prefix), DecisionPrefix
+}
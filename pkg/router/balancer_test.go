@@ -0,0 +1,135 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestBalancer(workspace types.NamespacedName, pods int) *Balancer {
+	replicas := NewReplicaTable()
+	var rs []Replica
+	for i := range pods {
+		rs = append(rs, Replica{Pod: fmt.Sprintf("pod-%d", i), IP: fmt.Sprintf("10.0.0.%d", i), Port: 5000})
+	}
+	replicas.Set(workspace, rs)
+	return &Balancer{Replicas: replicas, PrefixAware: true, PrefixLength: 16, ImbalanceThreshold: 4}
+}
+
+func TestBalancerPick(t *testing.T) {
+	workspace := types.NamespacedName{Namespace: "default", Name: "ws"}
+	b := newTestBalancer(workspace, 3)
+
+	// The same key is routed to the same replica.
+	first, decision, ok := b.Pick(workspace, nil, "key", DecisionPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, DecisionPrefix, decision)
+	for range 5 {
+		replica, _, _ := b.Pick(workspace, nil, "key", DecisionPrefix)
+		assert.Equal(t, first, replica)
+	}
+
+	// The keys are spread over the replicas.
+	pods := map[string]bool{}
+	for i := range 100 {
+		replica, _, _ := b.Pick(workspace, nil, fmt.Sprintf("key-%d", i), DecisionPrefix)
+		pods[replica.Pod] = true
+	}
+	assert.Len(t, pods, 3)
+
+	// An overloaded replica falls back to the least loaded replica.
+	var releases []func()
+	for range 4 {
+		releases = append(releases, b.Replicas.Acquire(first))
+	}
+	replica, decision, _ := b.Pick(workspace, nil, "key", DecisionPrefix)
+	assert.NotEqual(t, first, replica)
+	assert.Equal(t, DecisionLoadFallback, decision)
+	for _, release := range releases {
+		release()
+	}
+	replica, _, _ = b.Pick(workspace, nil, "key", DecisionPrefix)
+	assert.Equal(t, first, replica)
+
+	// Requests without key are routed to the least loaded replica.
+	replicas := b.Replicas.Replicas(workspace)
+	b.Replicas.SetScrapedLoad(replicas[0], 2, 1)
+	b.Replicas.SetScrapedLoad(replicas[2], 1, 0)
+	replica, decision, _ = b.Pick(workspace, nil, "", DecisionLeastLoaded)
+	assert.Equal(t, replicas[1], replica)
+	assert.Equal(t, DecisionLeastLoaded, decision)
+
+	// Requests for an adapter are routed to the replicas that loaded the adapter.
+	for i := range 10 {
+		replica, _, _ = b.Pick(workspace, []string{"pod-2"}, fmt.Sprintf("key-%d", i), DecisionPrefix)
+		assert.Equal(t, "pod-2", replica.Pod)
+	}
+	_, _, ok = b.Pick(workspace, []string{"pod-9"}, "key", DecisionPrefix)
+	assert.False(t, ok)
+
+	_, _, ok = b.Pick(types.NamespacedName{Namespace: "default", Name: "unknown"}, nil, "key", DecisionPrefix)
+	assert.False(t, ok)
+}
+
+func TestBalancerAffinityKey(t *testing.T) {
+	b := &Balancer{PrefixLength: 16}
+	testcases := map[string]struct {
+		session      string
+		body         string
+		wantKey      string
+		wantDecision string
+	}{
+		"Session header": {
+			session:      "abc",
+			body:         `{"messages":[{"role":"user","content":"hi"}]}`,
+			wantKey:      "model\x00abc",
+			wantDecision: DecisionSession,
+		},
+		"Prefix of the messages": {
+			body:         `{"messages":[{"role":"system","content":"You are a helpful assistant."}]}`,
+			wantKey:      "model\x00" + `[{"role":"system"`[:16],
+			wantDecision: DecisionPrefix,
+		},
+		"Prompt": {
+			body:         `{"prompt":"hello"}`,
+			wantKey:      "model\x00" + `"hello"`,
+			wantDecision: DecisionPrefix,
+		},
+		"No prompt": {
+			body:         `{"input":"hello"}`,
+			wantDecision: DecisionLeastLoaded,
+		},
+		"Invalid body": {
+			body:         `not json`,
+			wantDecision: DecisionLeastLoaded,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+			if tc.session != "" {
+				r.Header.Set(SessionHeader, tc.session)
+			}
+			key, decision := b.AffinityKey(r, "model", []byte(tc.body))
+			assert.Equal(t, tc.wantKey, key)
+			assert.Equal(t, tc.wantDecision, decision)
+		})
+	}
+}
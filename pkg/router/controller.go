@@ -0,0 +1,56 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// WorkspaceRouteReconciler rebuilds the route table of the router whenever a workspace changes.
+type WorkspaceRouteReconciler struct {
+	client.Client
+	Table *RouteTable
+}
+
+func NewWorkspaceRouteReconciler(client client.Client, table *RouteTable) *WorkspaceRouteReconciler {
+	return &WorkspaceRouteReconciler{
+		Client: client,
+		Table:  table,
+	}
+}
+
+func (c *WorkspaceRouteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	// The table is rebuilt from all workspaces, so that a model name served by several workspaces
+	// is routed consistently regardless of the order of the events.
+	workspaces := &kaitov1beta1.WorkspaceList{}
+	if err := c.List(ctx, workspaces); err != nil {
+		return reconcile.Result{}, err
+	}
+	c.Table.Rebuild(workspaces.Items)
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *WorkspaceRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("router").
+		For(&kaitov1beta1.Workspace{}).
+		Complete(c)
+}
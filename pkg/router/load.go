@@ -0,0 +1,235 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	metricRequestsRunning    = "vllm:num_requests_running"
+	metricRequestsWaiting    = "vllm:num_requests_waiting"
+	metricPrefixCacheHits    = "vllm:prefix_cache_hits_total"
+	metricPrefixCacheQueries = "vllm:prefix_cache_queries_total"
+	// metricPrefixCacheHitRate is reported instead of the prefix cache counters by the V0 engine of vLLM.
+	metricPrefixCacheHitRate = "vllm:gpu_prefix_cache_hit_rate"
+)
+
+// LoadScraper periodically scrapes the metrics of the vLLM server of every replica, to track the load of the
+// replicas and report the hit ratio of their prefix caches.
+type LoadScraper struct {
+	Replicas *ReplicaTable
+	Interval time.Duration
+
+	client *http.Client
+	// counters are the prefix cache counters of the replicas in their previous scrape.
+	counters map[string]cacheCounters
+	// hitRatios are the last known prefix cache hit ratios of the workspaces.
+	hitRatios map[types.NamespacedName]float64
+}
+
+type cacheCounters struct {
+	hits    float64
+	queries float64
+}
+
+// vllmMetrics are the metrics of a vLLM server used by the router.
+type vllmMetrics struct {
+	running float64
+	waiting float64
+	// cache holds the prefix cache counters, if reported.
+	cache *cacheCounters
+	// hitRate is the prefix cache hit rate of the V0 engine, if reported.
+	hitRate *float64
+}
+
+func NewLoadScraper(replicas *ReplicaTable, interval time.Duration) *LoadScraper {
+	return &LoadScraper{
+		Replicas:  replicas,
+		Interval:  interval,
+		client:    &http.Client{Timeout: interval},
+		counters:  map[string]cacheCounters{},
+		hitRatios: map[types.NamespacedName]float64{},
+	}
+}
+
+// Start scrapes the replicas every interval until the context is done.
+func (s *LoadScraper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.scrapeAll(ctx)
+		}
+	}
+}
+
+type scrapeResult struct {
+	workspace types.NamespacedName
+	replica   Replica
+	metrics   vllmMetrics
+	err       error
+}
+
+func (s *LoadScraper) scrapeAll(ctx context.Context) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var results []scrapeResult
+	for _, workspace := range s.Replicas.Workspaces() {
+		for _, replica := range s.Replicas.Replicas(workspace) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m, err := s.scrape(ctx, replica)
+				mu.Lock()
+				defer mu.Unlock()
+				results = append(results, scrapeResult{workspace: workspace, replica: replica, metrics: m, err: err})
+			}()
+		}
+	}
+	wg.Wait()
+	s.record(results)
+}
+
+// record updates the load of the replicas and the prefix cache hit ratios of the workspaces.
+func (s *LoadScraper) record(results []scrapeResult) {
+	type cacheUsage struct {
+		hits, queries float64
+		rateSum       float64
+		rates         int
+	}
+	usage := map[types.NamespacedName]*cacheUsage{}
+	counters := map[string]cacheCounters{}
+	workspaces := map[types.NamespacedName]bool{}
+
+	replicaLoadGauge.Reset()
+	for _, res := range results {
+		workspaces[res.workspace] = true
+		if res.err != nil {
+			klog.V(4).InfoS("Failed to scrape the metrics of a replica", "workspace", res.workspace, "pod", res.replica.Pod, "err", res.err)
+			continue
+		}
+		m := res.metrics
+		s.Replicas.SetScrapedLoad(res.replica, m.running, m.waiting)
+		replicaLoadGauge.WithLabelValues(res.workspace.String(), res.replica.Pod).Set(m.running + m.waiting)
+
+		u := usage[res.workspace]
+		if u == nil {
+			u = &cacheUsage{}
+			usage[res.workspace] = u
+		}
+		switch {
+		case m.cache != nil:
+			address := res.replica.Address()
+			counters[address] = *m.cache
+			// The counters of a restarted server start over, its first scrape is the baseline.
+			if prev, ok := s.counters[address]; ok && m.cache.queries >= prev.queries && m.cache.hits >= prev.hits {
+				u.hits += m.cache.hits - prev.hits
+				u.queries += m.cache.queries - prev.queries
+			}
+		case m.hitRate != nil:
+			u.rateSum += *m.hitRate
+			u.rates++
+		}
+	}
+	s.counters = counters
+
+	for workspace, u := range usage {
+		if u.queries > 0 {
+			s.hitRatios[workspace] = u.hits / u.queries
+		} else if u.rates > 0 {
+			s.hitRatios[workspace] = u.rateSum / float64(u.rates)
+		}
+	}
+	prefixCacheHitRatio.Reset()
+	for workspace, ratio := range s.hitRatios {
+		if !workspaces[workspace] {
+			delete(s.hitRatios, workspace)
+			continue
+		}
+		prefixCacheHitRatio.WithLabelValues(workspace.String()).Set(ratio)
+	}
+}
+
+// scrape returns the metrics of the vLLM server of the replica.
+func (s *LoadScraper) scrape(ctx context.Context, replica Replica) (vllmMetrics, error) {
+	// The inference proxy forwards the metrics of the vLLM server without authentication.
+	url := fmt.Sprintf("http://%s/metrics", replica.Address())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return vllmMetrics{}, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return vllmMetrics{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return vllmMetrics{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return vllmMetrics{}, err
+	}
+	return parseVLLMMetrics(families), nil
+}
+
+func parseVLLMMetrics(families map[string]*dto.MetricFamily) vllmMetrics {
+	m := vllmMetrics{}
+	m.running, _ = sumFamily(families, metricRequestsRunning)
+	m.waiting, _ = sumFamily(families, metricRequestsWaiting)
+	hits, hasHits := sumFamily(families, metricPrefixCacheHits)
+	queries, hasQueries := sumFamily(families, metricPrefixCacheQueries)
+	if hasHits && hasQueries {
+		m.cache = &cacheCounters{hits: hits, queries: queries}
+	} else if rate, ok := sumFamily(families, metricPrefixCacheHitRate); ok {
+		m.hitRate = &rate
+	}
+	return m
+}
+
+// sumFamily returns the sum of the samples of the metric family, e.g. of all models served by the server.
+func sumFamily(families map[string]*dto.MetricFamily, name string) (float64, bool) {
+	family, ok := families[name]
+	if !ok {
+		return 0, false
+	}
+	var sum float64
+	for _, metric := range family.GetMetric() {
+		switch {
+		case metric.GetGauge() != nil:
+			sum += metric.GetGauge().GetValue()
+		case metric.GetCounter() != nil:
+			sum += metric.GetCounter().GetValue()
+		case metric.GetUntyped() != nil:
+			sum += metric.GetUntyped().GetValue()
+		}
+	}
+	return sum, true
+}
@@ -0,0 +1,99 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseVLLMMetrics(t *testing.T) {
+	testcases := map[string]struct {
+		metrics string
+		want    vllmMetrics
+	}{
+		"Prefix cache counters": {
+			metrics: `# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="phi-4"} 2
+vllm:num_requests_running{model_name="adapter"} 1
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="phi-4"} 3
+# TYPE vllm:prefix_cache_hits_total counter
+vllm:prefix_cache_hits_total{model_name="phi-4"} 40
+# TYPE vllm:prefix_cache_queries_total counter
+vllm:prefix_cache_queries_total{model_name="phi-4"} 100
+`,
+			want: vllmMetrics{running: 3, waiting: 3, cache: &cacheCounters{hits: 40, queries: 100}},
+		},
+		"Prefix cache hit rate of the V0 engine": {
+			metrics: `# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="phi-4"} 1
+# TYPE vllm:gpu_prefix_cache_hit_rate gauge
+vllm:gpu_prefix_cache_hit_rate{model_name="phi-4"} 0.5
+`,
+			want: vllmMetrics{running: 1, hitRate: func() *float64 { r := 0.5; return &r }()},
+		},
+		"No metrics": {
+			want: vllmMetrics{},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			var parser expfmt.TextParser
+			families, err := parser.TextToMetricFamilies(strings.NewReader(tc.metrics))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, parseVLLMMetrics(families))
+		})
+	}
+}
+
+func TestLoadScraperRecord(t *testing.T) {
+	workspace := types.NamespacedName{Namespace: "default", Name: "ws"}
+	a := Replica{Pod: "a", IP: "10.0.0.1", Port: 5000}
+	b := Replica{Pod: "b", IP: "10.0.0.2", Port: 5000}
+	replicas := NewReplicaTable()
+	replicas.Set(workspace, []Replica{a, b})
+	s := NewLoadScraper(replicas, time.Second)
+
+	result := func(r Replica, running, hits, queries float64) scrapeResult {
+		return scrapeResult{workspace: workspace, replica: r, metrics: vllmMetrics{
+			running: running,
+			cache:   &cacheCounters{hits: hits, queries: queries},
+		}}
+	}
+
+	// The first scrape is the baseline of the counters.
+	s.record([]scrapeResult{result(a, 2, 10, 20), result(b, 1, 0, 10)})
+	assert.Equal(t, float64(2), replicas.Load(a))
+	assert.Equal(t, float64(1), replicas.Load(b))
+	assert.NotContains(t, s.hitRatios, workspace)
+
+	s.record([]scrapeResult{result(a, 0, 16, 30), result(b, 0, 2, 20)})
+	assert.InDelta(t, 0.4, s.hitRatios[workspace], 1e-9)
+
+	// The counters of a restarted replica start over and the last ratio is kept.
+	s.record([]scrapeResult{result(a, 0, 1, 1), {workspace: workspace, replica: b, err: assert.AnError}})
+	assert.InDelta(t, 0.4, s.hitRatios[workspace], 1e-9)
+	s.record([]scrapeResult{result(a, 0, 4, 5)})
+	assert.InDelta(t, 0.75, s.hitRatios[workspace], 1e-9)
+
+	// The ratio of a workspace without replicas is dropped.
+	s.record(nil)
+	assert.Empty(t, s.hitRatios)
+}
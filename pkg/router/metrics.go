@@ -0,0 +1,60 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DecisionSession routes the request to the replica of its session header.
+	DecisionSession = "session"
+	// DecisionPrefix routes the request to the replica of its prompt prefix.
+	DecisionPrefix = "prefix"
+	// DecisionLoadFallback routes the request to the least loaded replica, its replica being overloaded.
+	DecisionLoadFallback = "load_fallback"
+	// DecisionLeastLoaded routes a request without session or prompt, e.g. embeddings, to the least loaded replica.
+	DecisionLeastLoaded = "least_loaded"
+	// DecisionService routes the request to the service of the workspace, no replica being known.
+	DecisionService = "service"
+)
+
+var (
+	routingDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kaito_router_routing_decisions_total",
+			Help: "Number of requests routed to a workspace by routing decision (session, prefix, load_fallback, least_loaded, service)",
+		},
+		[]string{"workspace", "decision"},
+	)
+	prefixCacheHitRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_router_prefix_cache_hit_ratio",
+			Help: "Ratio of prompt tokens served from the prefix cache of the replicas of a workspace since the previous scrape",
+		},
+		[]string{"workspace"},
+	)
+	replicaLoadGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_router_replica_load",
+			Help: "Number of requests running and waiting on a replica of a workspace in its last scraped metrics",
+		},
+		[]string{"workspace", "pod"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(routingDecisions, prefixCacheHitRatio, replicaLoadGauge)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"k8s.io/klog/v2"
+)
+
+// MaxRequestBodyBytes is the largest request body the router reads to find the model name. Requests of
+// vision-language models carry base64 encoded images, so the limit is well above plain text prompts.
+const MaxRequestBodyBytes = 32 << 20
+
+// Proxy is an OpenAI compatible reverse proxy forwarding each request to the workspace serving the model
+// named in its body. The balancer, if any, picks the replica of the workspace that requests are forwarded to
+// instead of the service of the workspace.
+type Proxy struct {
+	Table    *RouteTable
+	Balancer *Balancer
+	mux      *http.ServeMux
+}
+
+func NewProxy(table *RouteTable, balancer *Balancer) *Proxy {
+	p := &Proxy{Table: table, Balancer: balancer, mux: http.NewServeMux()}
+	p.mux.HandleFunc("GET /v1/models", p.listModels)
+	p.mux.HandleFunc("POST /v1/chat/completions", p.forward)
+	p.mux.HandleFunc("POST /v1/completions", p.forward)
+	p.mux.HandleFunc("POST /v1/embeddings", p.forward)
+	return p
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+type modelObject struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+type modelList struct {
+	Object string        `json:"object"`
+	Data   []modelObject `json:"data"`
+}
+
+// listModels serves the models of all workspaces in the format of the OpenAI list models API.
+func (p *Proxy) listModels(w http.ResponseWriter, _ *http.Request) {
+	models := modelList{Object: "list", Data: []modelObject{}}
+	for _, name := range p.Table.ModelNames() {
+		models.Data = append(models.Data, modelObject{ID: name, Object: "model", OwnedBy: "kaito"})
+	}
+	writeJSON(w, http.StatusOK, models)
+}
+
+// forward proxies the request to the workspace serving the model of the request.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes))
+	if err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, "invalid_request_error", "", fmt.Sprintf("failed to read the request body: %v", err))
+		return
+	}
+	var request struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", fmt.Sprintf("failed to parse the request body: %v", err))
+		return
+	}
+	if request.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "the model field is required")
+		return
+	}
+	route, ok := p.Table.Lookup(request.Model)
+	if !ok {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "model_not_found", fmt.Sprintf("the model %q does not exist", request.Model))
+		return
+	}
+	target, err := url.Parse(route.URL)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "", err.Error())
+		return
+	}
+	decision := DecisionService
+	if p.Balancer != nil && (p.Balancer.PrefixAware || len(route.Pods) > 0) {
+		key, keyDecision := "", DecisionLeastLoaded
+		if p.Balancer.PrefixAware {
+			key, keyDecision = p.Balancer.AffinityKey(r, request.Model, body)
+		}
+		replica, replicaDecision, ok := p.Balancer.Pick(route.Workspace, route.Pods, key, keyDecision)
+		switch {
+		case ok:
+			decision = replicaDecision
+			target = &url.URL{Scheme: "http", Host: replica.Address()}
+			release := p.Balancer.Replicas.Acquire(replica)
+			defer release()
+		case len(route.Pods) > 0:
+			// The other replicas of the workspace did not load the adapter.
+			writeError(w, http.StatusServiceUnavailable, "server_error", "", fmt.Sprintf("no replica serving the model %q is ready", request.Model))
+			return
+		}
+	}
+	routingDecisions.WithLabelValues(route.Workspace.String(), decision).Inc()
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		// Flush immediately so that streamed completions reach the client as they are generated.
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			klog.ErrorS(err, "failed to proxy request", "model", request.Model, "workspace", route.Workspace)
+			writeError(w, http.StatusBadGateway, "server_error", "", fmt.Sprintf("the workspace serving the model %q is unavailable", request.Model))
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+type errorResponse struct {
+	Error errorObject `json:"error"`
+}
+
+type errorObject struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code"`
+}
+
+// writeError writes an error in the format of the OpenAI API.
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	obj := errorObject{Message: message, Type: errType}
+	if code != "" {
+		obj.Code = &code
+	}
+	writeJSON(w, status, errorResponse{Error: obj})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.ErrorS(err, "failed to write response")
+	}
+}
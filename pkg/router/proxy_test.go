@@ -0,0 +1,179 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestProxy(routes map[string]Route) *Proxy {
+	table := NewRouteTable()
+	table.routes = routes
+	return NewProxy(table, nil)
+}
+
+func TestProxyForward(t *testing.T) {
+	var received string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = r.URL.Path + " " + string(body)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":"cmpl-1"}`))
+	}))
+	defer backend.Close()
+
+	proxy := newTestProxy(map[string]Route{
+		"mymodel": {Workspace: types.NamespacedName{Namespace: "default", Name: "ws"}, URL: backend.URL},
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "known model",
+			body:           `{"model":"mymodel","messages":[]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown model",
+			body:           `{"model":"other","messages":[]}`,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "model_not_found",
+		},
+		{
+			name:           "missing model",
+			body:           `{"messages":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			received = ""
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, "/v1/chat/completions "+tc.body, received)
+				return
+			}
+			assert.Empty(t, received)
+			var resp errorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.NotEmpty(t, resp.Error.Message)
+			if tc.expectedCode != "" {
+				assert.Equal(t, tc.expectedCode, *resp.Error.Code)
+			}
+		})
+	}
+}
+
+func TestProxyForwardToReplica(t *testing.T) {
+	var received string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(backendURL.Port())
+	assert.NoError(t, err)
+
+	workspace := types.NamespacedName{Namespace: "default", Name: "ws"}
+	replicas := NewReplicaTable()
+	replicas.Set(workspace, []Replica{{Pod: "ws-0", IP: backendURL.Hostname(), Port: int32(port)}})
+	table := NewRouteTable()
+	// The service of the workspace is not reachable, the request must be forwarded to the replica.
+	table.routes = map[string]Route{"mymodel": {Workspace: workspace, URL: "http://ws.default.svc.cluster.local"}}
+	proxy := NewProxy(table, &Balancer{Replicas: replicas, PrefixAware: true, PrefixLength: 1024, ImbalanceThreshold: 4})
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"mymodel","messages":[]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, backendURL.Host, received)
+	// The request is no longer in flight.
+	assert.Equal(t, float64(0), replicas.Load(replicas.Replicas(workspace)[0]))
+}
+
+func TestProxyForwardAdapterToReplica(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(backendURL.Port())
+	assert.NoError(t, err)
+
+	workspace := types.NamespacedName{Namespace: "default", Name: "ws"}
+	replicas := NewReplicaTable()
+	replicas.Set(workspace, []Replica{
+		{Pod: "ws-0", IP: "192.0.2.1", Port: int32(port)},
+		{Pod: "ws-1", IP: backendURL.Hostname(), Port: int32(port)},
+	})
+	table := NewRouteTable()
+	table.routes = map[string]Route{
+		"adapter-a": {Workspace: workspace, URL: "http://ws.default.svc.cluster.local", Pods: []string{"ws-1"}},
+		"adapter-b": {Workspace: workspace, URL: "http://ws.default.svc.cluster.local", Pods: []string{"ws-2"}},
+	}
+	// The requests for spread adapters are routed to their replicas without prefix-aware routing.
+	proxy := NewProxy(table, &Balancer{Replicas: replicas})
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"adapter-a","messages":[]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"adapter-b","messages":[]}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestProxyListModels(t *testing.T) {
+	proxy := newTestProxy(map[string]Route{
+		"mymodel":   {URL: "http://ws.default.svc.cluster.local"},
+		"adapter-a": {URL: "http://ws.default.svc.cluster.local"},
+	})
+
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var models modelList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &models))
+	assert.Equal(t, "list", models.Object)
+	var ids []string
+	for _, m := range models.Data {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"adapter-a", "mymodel"}, ids)
+}
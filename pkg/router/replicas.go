@@ -0,0 +1,239 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// servicePortName is the name of the port of the inference service of a workspace.
+const servicePortName = "http"
+
+// Replica is a ready inference pod of a workspace, i.e. an endpoint of the inference service.
+type Replica struct {
+	// Pod is the name of the pod.
+	Pod string
+	// IP is the IP address of the pod.
+	IP string
+	// Port is the port the inference service targets on the pod.
+	Port int32
+}
+
+// Address returns the host and port of the inference service on the pod.
+func (r Replica) Address() string {
+	return net.JoinHostPort(r.IP, strconv.Itoa(int(r.Port)))
+}
+
+// replicaLoad is the load of a replica: the requests the inference server reported in its last scraped
+// metrics and the requests the router is forwarding to it.
+type replicaLoad struct {
+	running  float64
+	waiting  float64
+	inflight atomic.Int64
+}
+
+func (l *replicaLoad) value() float64 {
+	return max(l.running+l.waiting, float64(l.inflight.Load()))
+}
+
+// ReplicaTable tracks the ready replicas of the workspaces and their load.
+type ReplicaTable struct {
+	mu       sync.RWMutex
+	replicas map[types.NamespacedName][]Replica
+	loads    map[string]*replicaLoad
+}
+
+// NewReplicaTable returns an empty replica table.
+func NewReplicaTable() *ReplicaTable {
+	return &ReplicaTable{
+		replicas: map[types.NamespacedName][]Replica{},
+		loads:    map[string]*replicaLoad{},
+	}
+}
+
+// Replicas returns the ready replicas of the workspace, sorted by pod name.
+func (t *ReplicaTable) Replicas(workspace types.NamespacedName) []Replica {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.replicas[workspace]
+}
+
+// Workspaces returns the workspaces with ready replicas.
+func (t *ReplicaTable) Workspaces() []types.NamespacedName {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	workspaces := make([]types.NamespacedName, 0, len(t.replicas))
+	for workspace := range t.replicas {
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces
+}
+
+// Set replaces the replicas of the workspace, an empty list removes the workspace.
+func (t *ReplicaTable) Set(workspace types.NamespacedName, replicas []Replica) {
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].Pod < replicas[j].Pod })
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(replicas) == 0 {
+		delete(t.replicas, workspace)
+	} else {
+		t.replicas[workspace] = replicas
+	}
+
+	// Keep the load of the replicas that are still ready in any workspace.
+	ready := map[string]bool{}
+	for _, rs := range t.replicas {
+		for _, r := range rs {
+			ready[r.Address()] = true
+			if t.loads[r.Address()] == nil {
+				t.loads[r.Address()] = &replicaLoad{}
+			}
+		}
+	}
+	for address := range t.loads {
+		if !ready[address] {
+			delete(t.loads, address)
+		}
+	}
+}
+
+// Load returns the load of the replica.
+func (t *ReplicaTable) Load(r Replica) float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if l := t.loads[r.Address()]; l != nil {
+		return l.value()
+	}
+	return 0
+}
+
+// SetScrapedLoad records the requests running and waiting on the replica reported by the inference server.
+func (t *ReplicaTable) SetScrapedLoad(r Replica, running, waiting float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if l := t.loads[r.Address()]; l != nil {
+		l.running, l.waiting = running, waiting
+	}
+}
+
+// Acquire counts a request forwarded to the replica until the returned function is called.
+func (t *ReplicaTable) Acquire(r Replica) func() {
+	t.mu.RLock()
+	l := t.loads[r.Address()]
+	t.mu.RUnlock()
+	if l == nil {
+		return func() {}
+	}
+	l.inflight.Add(1)
+	return func() { l.inflight.Add(-1) }
+}
+
+// ReplicaReconciler keeps the replicas of the workspaces up to date with the EndpointSlices of their
+// inference services, which are named after the workspaces.
+type ReplicaReconciler struct {
+	client.Client
+	Replicas *ReplicaTable
+}
+
+func NewReplicaReconciler(client client.Client, replicas *ReplicaTable) *ReplicaReconciler {
+	return &ReplicaReconciler{
+		Client:   client,
+		Replicas: replicas,
+	}
+}
+
+// Reconcile rebuilds the replicas of the workspace named in the request from the EndpointSlices of its service.
+func (c *ReplicaReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if err := c.Get(ctx, req.NamespacedName, &kaitov1beta1.Workspace{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Not the service of a workspace, or the workspace was deleted.
+			c.Replicas.Set(req.NamespacedName, nil)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := c.List(ctx, slices, client.InNamespace(req.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: req.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+	c.Replicas.Set(req.NamespacedName, readyReplicas(slices.Items))
+	return reconcile.Result{}, nil
+}
+
+// readyReplicas returns the ready endpoints of the inference service port in the EndpointSlices.
+func readyReplicas(slices []discoveryv1.EndpointSlice) []Replica {
+	var replicas []Replica
+	seen := map[string]bool{}
+	for _, slice := range slices {
+		var port *int32
+		for _, p := range slice.Ports {
+			if p.Name != nil && *p.Name == servicePortName {
+				port = p.Port
+			}
+		}
+		if port == nil {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			for _, ip := range endpoint.Addresses {
+				replica := Replica{Pod: ip, IP: ip, Port: *port}
+				if endpoint.TargetRef != nil && endpoint.TargetRef.Name != "" {
+					replica.Pod = endpoint.TargetRef.Name
+				}
+				if !seen[replica.Address()] {
+					seen[replica.Address()] = true
+					replicas = append(replicas, replica)
+				}
+			}
+		}
+	}
+	return replicas
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *ReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("router-replicas").
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(
+			func(_ context.Context, obj client.Object) []reconcile.Request {
+				service := obj.GetLabels()[discoveryv1.LabelServiceName]
+				if service == "" {
+					return nil
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: service}}}
+			})).
+		Watches(&kaitov1beta1.Workspace{}, &handler.EnqueueRequestForObject{}).
+		Complete(c)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestReadyReplicas(t *testing.T) {
+	slices := []discoveryv1.EndpointSlice{
+		{
+			Ports: []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](5000)}},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}, TargetRef: &corev1.ObjectReference{Name: "ws-1"}},
+				{Addresses: []string{"10.0.0.1"}, TargetRef: &corev1.ObjectReference{Name: "ws-0"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}},
+				{Addresses: []string{"10.0.0.3"}, TargetRef: &corev1.ObjectReference{Name: "ws-2"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}},
+			},
+		},
+		{
+			// The endpoints of another port of the service are ignored.
+			Ports:     []discoveryv1.EndpointPort{{Name: ptr.To("torch"), Port: ptr.To[int32](29500)}},
+			Endpoints: []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+		},
+	}
+	assert.Equal(t, []Replica{
+		{Pod: "ws-1", IP: "10.0.0.2", Port: 5000},
+		{Pod: "ws-0", IP: "10.0.0.1", Port: 5000},
+	}, readyReplicas(slices))
+}
+
+func TestReplicaTableSet(t *testing.T) {
+	workspace := types.NamespacedName{Namespace: "default", Name: "ws"}
+	table := NewReplicaTable()
+	a := Replica{Pod: "b", IP: "10.0.0.2", Port: 5000}
+	b := Replica{Pod: "a", IP: "10.0.0.1", Port: 5000}
+	table.Set(workspace, []Replica{a, b})
+	assert.Equal(t, []Replica{b, a}, table.Replicas(workspace))
+
+	release := table.Acquire(a)
+	assert.Equal(t, float64(1), table.Load(a))
+	release()
+	table.SetScrapedLoad(a, 1, 2)
+	assert.Equal(t, float64(3), table.Load(a))
+
+	// The load of a removed replica is dropped.
+	table.Set(workspace, []Replica{b})
+	assert.Equal(t, float64(0), table.Load(a))
+	table.Set(workspace, nil)
+	assert.Empty(t, table.Workspaces())
+}
@@ -0,0 +1,139 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+)
+
+// Route is the workspace serving a model name.
+type Route struct {
+	// Workspace is the namespaced name of the workspace.
+	Workspace types.NamespacedName
+	// URL is the base URL of the inference service of the workspace.
+	URL string
+	// Pods are the replicas of the workspace that loaded the adapter when the adapters are spread across the
+	// replicas, empty if every replica serves the model name.
+	Pods []string
+}
+
+// RouteTable maps the model names of OpenAI requests to the workspaces serving them.
+type RouteTable struct {
+	mu     sync.RWMutex
+	routes map[string]Route
+}
+
+// NewRouteTable returns an empty route table.
+func NewRouteTable() *RouteTable {
+	return &RouteTable{routes: map[string]Route{}}
+}
+
+// Lookup returns the route of the model name.
+func (t *RouteTable) Lookup(modelName string) (Route, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	route, ok := t.routes[modelName]
+	return route, ok
+}
+
+// ModelNames returns the sorted model names of the table.
+func (t *RouteTable) ModelNames() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	names := make([]string, 0, len(t.routes))
+	for name := range t.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rebuild replaces the routes of the table with the model names served by the workspaces. Workspaces are
+// visited in namespace/name order, so a model name served by several workspaces is routed to the first one.
+func (t *RouteTable) Rebuild(workspaces []kaitov1beta1.Workspace) {
+	sorted := make([]*kaitov1beta1.Workspace, 0, len(workspaces))
+	for i := range workspaces {
+		sorted = append(sorted, &workspaces[i])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	routes := map[string]Route{}
+	for _, wObj := range sorted {
+		if !wObj.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// The search domains of the pod resolve the service, whatever the domain of the cluster.
+		route := Route{
+			Workspace: types.NamespacedName{Namespace: wObj.Namespace, Name: wObj.Name},
+			URL:       fmt.Sprintf("http://%s.%s.svc", wObj.Name, wObj.Namespace),
+		}
+		adapterPods := map[string][]string{}
+		for _, replica := range wObj.Status.AdapterPlacement {
+			for _, adapter := range replica.Adapters {
+				adapterPods[adapter] = append(adapterPods[adapter], replica.Pod)
+			}
+		}
+		for _, name := range ServedModelNames(wObj) {
+			if existing, ok := routes[name]; ok {
+				klog.InfoS("Model name is served by several workspaces", "model", name,
+					"workspace", route.Workspace, "routedTo", existing.Workspace)
+				continue
+			}
+			modelRoute := route
+			modelRoute.Pods = adapterPods[name]
+			routes[name] = modelRoute
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes = routes
+}
+
+// ServedModelNames returns the model names a workspace serves in the OpenAI API: the served model name of the
+// preset and the name of each adapter. Workspaces without a preset are not routed.
+func ServedModelNames(wObj *kaitov1beta1.Workspace) []string {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return nil
+	}
+	presetName := string(wObj.Inference.Preset.Name)
+	if !plugin.IsValidPreset(presetName) {
+		return nil
+	}
+	inferenceParam := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters()
+
+	var names []string
+	if modelName := inferenceParam.ServedModelName(kaitov1beta1.GetWorkspaceRuntimeName(wObj)); modelName != "" {
+		names = append(names, modelName)
+	}
+	for _, adapter := range wObj.Inference.Adapters {
+		if adapter.Source != nil && adapter.Source.Name != "" {
+			names = append(names, adapter.Source.Name)
+		}
+	}
+	return names
+}
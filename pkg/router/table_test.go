@@ -0,0 +1,100 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+)
+
+func newTestWorkspace(namespace, name string, adapters ...string) kaitov1beta1.Workspace {
+	wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	wObj.Namespace = namespace
+	wObj.Name = name
+	for _, adapter := range adapters {
+		wObj.Inference.Adapters = append(wObj.Inference.Adapters, kaitov1beta1.AdapterSpec{
+			Source: &kaitov1beta1.DataSource{Name: adapter},
+		})
+	}
+	return *wObj
+}
+
+func TestRouteTableRebuild(t *testing.T) {
+	test.RegisterTestModel()
+
+	templateWorkspace := newTestWorkspace("default", "custom")
+	templateWorkspace.Inference.Preset = nil
+	workspaces := []kaitov1beta1.Workspace{
+		newTestWorkspace("team-b", "base"),
+		newTestWorkspace("team-a", "base", "adapter-a"),
+		templateWorkspace,
+	}
+
+	table := NewRouteTable()
+	table.Rebuild(workspaces)
+
+	assert.Equal(t, []string{"adapter-a", "mymodel"}, table.ModelNames())
+
+	// The model name served by both workspaces is routed to the first one in namespace/name order.
+	route, ok := table.Lookup("mymodel")
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "base"}, route.Workspace)
+	assert.Equal(t, "http://base.team-a.svc", route.URL)
+
+	route, ok = table.Lookup("adapter-a")
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "base"}, route.Workspace)
+
+	table.Rebuild(workspaces[:1])
+	_, ok = table.Lookup("adapter-a")
+	assert.False(t, ok)
+	route, ok = table.Lookup("mymodel")
+	assert.True(t, ok)
+	assert.Equal(t, types.NamespacedName{Namespace: "team-b", Name: "base"}, route.Workspace)
+}
+
+func TestRouteTableRebuildWithAdapterPlacement(t *testing.T) {
+	test.RegisterTestModel()
+
+	wObj := newTestWorkspace("default", "ws", "adapter-a", "adapter-b")
+	wObj.Status.AdapterPlacement = []kaitov1beta1.ReplicaAdapters{
+		{Pod: "ws-0", Adapters: []string{"adapter-a"}},
+		{Pod: "ws-1", Adapters: []string{"adapter-a", "adapter-b"}},
+	}
+	table := NewRouteTable()
+	table.Rebuild([]kaitov1beta1.Workspace{wObj})
+
+	route, _ := table.Lookup("adapter-a")
+	assert.Equal(t, []string{"ws-0", "ws-1"}, route.Pods)
+	route, _ = table.Lookup("adapter-b")
+	assert.Equal(t, []string{"ws-1"}, route.Pods)
+	// Every replica serves the base model.
+	route, _ = table.Lookup("mymodel")
+	assert.Empty(t, route.Pods)
+}
+
+func TestServedModelNames(t *testing.T) {
+	test.RegisterTestModel()
+
+	wObj := newTestWorkspace("default", "ws", "adapter-a", "adapter-b")
+	assert.Equal(t, []string{"mymodel", "adapter-a", "adapter-b"}, ServedModelNames(&wObj))
+
+	wObj.Inference.Preset.Name = "unknown-model"
+	assert.Empty(t, ServedModelNames(&wObj))
+}
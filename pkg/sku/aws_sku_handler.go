@@ -59,11 +59,11 @@ func NewAwsSKUHandler() CloudSKUHandler {
 		{SKU: "g4dn.16xlarge", GPUCount: 1, GPUMemGB: 16, GPUModel: "NVIDIA T4", NVMeDiskEnabled: true},
 		{SKU: "g4dn.12xlarge", GPUCount: 4, GPUMemGB: 64, GPUModel: "NVIDIA T4", NVMeDiskEnabled: true},
 		{SKU: "g4dn.metal", GPUCount: 8, GPUMemGB: 128, GPUModel: "NVIDIA T4", NVMeDiskEnabled: true},
-		{SKU: "g4ad.xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true},
-		{SKU: "g4ad.2xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true},
-		{SKU: "g4ad.4xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true},
-		{SKU: "g4ad.8xlarge", GPUCount: 2, GPUMemGB: 16, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true},
-		{SKU: "g4ad.16xlarge", GPUCount: 4, GPUMemGB: 32, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true},
+		{SKU: "g4ad.xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true, GPUVendor: GPUVendorAMD},
+		{SKU: "g4ad.2xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true, GPUVendor: GPUVendorAMD},
+		{SKU: "g4ad.4xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true, GPUVendor: GPUVendorAMD},
+		{SKU: "g4ad.8xlarge", GPUCount: 2, GPUMemGB: 16, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true, GPUVendor: GPUVendorAMD},
+		{SKU: "g4ad.16xlarge", GPUCount: 4, GPUMemGB: 32, GPUModel: "AMD Radeon Pro V520", NVMeDiskEnabled: true, GPUVendor: GPUVendorAMD},
 		{SKU: "g3s.xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "NVIDIA M60"},
 		{SKU: "g3s.4xlarge", GPUCount: 1, GPUMemGB: 8, GPUModel: "NVIDIA M60"},
 		{SKU: "g3s.8xlarge", GPUCount: 2, GPUMemGB: 16, GPUModel: "NVIDIA M60"},
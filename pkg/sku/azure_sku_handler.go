@@ -37,22 +37,22 @@ func NewAzureSKUHandler() CloudSKUHandler {
 		{SKU: "Standard_NCC40ads_H100_v5", GPUCount: 1, GPUMemGB: 94, GPUModel: "NVIDIA H100"},
 		// https://learn.microsoft.com/en-us/azure/virtual-machines/sizes/gpu-accelerated/nd-h200-v5-series
 		{SKU: "Standard_ND96isr_H200_v5", GPUCount: 8, GPUMemGB: 1128, GPUModel: "NVIDIA H200", NVMeDiskEnabled: true},
-		{SKU: "Standard_NG32ads_V620_v1", GPUCount: 1, GPUMemGB: 32, GPUModel: "AMD Radeon PRO V620"},
-		{SKU: "Standard_NG32adms_V620_v1", GPUCount: 1, GPUMemGB: 32, GPUModel: "AMD Radeon PRO V620"},
+		{SKU: "Standard_NG32ads_V620_v1", GPUCount: 1, GPUMemGB: 32, GPUModel: "AMD Radeon PRO V620", GPUVendor: GPUVendorAMD},
+		{SKU: "Standard_NG32adms_V620_v1", GPUCount: 1, GPUMemGB: 32, GPUModel: "AMD Radeon PRO V620", GPUVendor: GPUVendorAMD},
 		{SKU: "Standard_NV6", GPUCount: 1, GPUMemGB: 8, GPUModel: "NVIDIA M60"},
 		{SKU: "Standard_NV12", GPUCount: 2, GPUMemGB: 16, GPUModel: "NVIDIA M60"},
 		{SKU: "Standard_NV24", GPUCount: 4, GPUMemGB: 32, GPUModel: "NVIDIA M60"},
 		{SKU: "Standard_NV12s_v3", GPUCount: 1, GPUMemGB: 8, GPUModel: "NVIDIA M60"},
 		{SKU: "Standard_NV24s_v3", GPUCount: 2, GPUMemGB: 16, GPUModel: "NVIDIA M60"},
 		{SKU: "Standard_NV48s_v3", GPUCount: 4, GPUMemGB: 32, GPUModel: "NVIDIA M60"},
-		{SKU: "Standard_NV32as_v4", GPUCount: 1, GPUMemGB: 16, GPUModel: "AMD Radeon Instinct MI25"},
+		{SKU: "Standard_NV32as_v4", GPUCount: 1, GPUMemGB: 16, GPUModel: "AMD Radeon Instinct MI25", GPUVendor: GPUVendorAMD},
 
 		// Not supporting partial gpu skus for now
-		// {SKU: "Standard_NG8ads_V620_v1", GPUCount: 1.0 / 4.0, GPUMem: 8, GPUModel: "AMD Radeon PRO V620"},
-		// {SKU: "Standard_NG16ads_V620_v1", GPUCount: 1.0 / 2.0, GPUMem: 16, GPUModel: "AMD Radeon PRO V620"},
-		// {SKU: "Standard_NV4as_v4", GPUCount: 1.0 / 8.0, GPUMem: 2, GPUModel: "AMD Radeon Instinct MI25"},
-		// {SKU: "Standard_NV8as_v4", GPUCount: 1.0 / 4.0, GPUMem: 4, GPUModel: "AMD Radeon Instinct MI25"},
-		// {SKU: "Standard_NV16as_v4", GPUCount: 1.0 / 2.0, GPUMem: 8, GPUModel: "AMD Radeon Instinct MI25"},
+		// {SKU: "Standard_NG8ads_V620_v1", GPUCount: 1.0 / 4.0, GPUMem: 8, GPUModel: "AMD Radeon PRO V620", GPUVendor: GPUVendorAMD},
+		// {SKU: "Standard_NG16ads_V620_v1", GPUCount: 1.0 / 2.0, GPUMem: 16, GPUModel: "AMD Radeon PRO V620", GPUVendor: GPUVendorAMD},
+		// {SKU: "Standard_NV4as_v4", GPUCount: 1.0 / 8.0, GPUMem: 2, GPUModel: "AMD Radeon Instinct MI25", GPUVendor: GPUVendorAMD},
+		// {SKU: "Standard_NV8as_v4", GPUCount: 1.0 / 4.0, GPUMem: 4, GPUModel: "AMD Radeon Instinct MI25", GPUVendor: GPUVendorAMD},
+		// {SKU: "Standard_NV16as_v4", GPUCount: 1.0 / 2.0, GPUMem: 8, GPUModel: "AMD Radeon Instinct MI25", GPUVendor: GPUVendorAMD},
 	}
 	return NewGeneralSKUHandler(supportedSKUs)
 }
@@ -14,6 +14,10 @@
 package sku
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 )
 
@@ -22,12 +26,86 @@ type CloudSKUHandler interface {
 	GetGPUConfigBySKU(sku string) *GPUConfig
 }
 
+// GPUVendor is the vendor of the GPUs of a SKU. It determines the extended resource name,
+// the device plugin and the runtime image used for the SKU.
+type GPUVendor string
+
+const (
+	GPUVendorNvidia GPUVendor = "nvidia"
+	GPUVendorAMD    GPUVendor = "amd"
+)
+
 type GPUConfig struct {
 	SKU             string
 	GPUCount        int
 	GPUMemGB        int
 	GPUModel        string
 	NVMeDiskEnabled bool
+	// GPUVendor defaults to GPUVendorNvidia when empty.
+	GPUVendor GPUVendor
+	// MIGProfile is set when a MIG instance of a GPU is requested instead of whole GPUs, e.g. "1g.10gb".
+	MIGProfile string
+	// TimeSlicingReplicas is set when a time-sliced share of a GPU is requested instead of whole GPUs.
+	// Each GPU is shared by this number of replicas.
+	TimeSlicingReplicas int
+}
+
+// Vendor returns the GPU vendor of the SKU, defaulting to Nvidia for SKUs that do not set one.
+func (c *GPUConfig) Vendor() GPUVendor {
+	if c == nil || c.GPUVendor == "" {
+		return GPUVendorNvidia
+	}
+	return c.GPUVendor
+}
+
+// WithPartition returns a copy of the GPU config where a single partition of a GPU is requested instead
+// of whole GPUs. GPUCount and GPUMemGB keep describing the physical GPUs of the SKU. A nil config
+// describes a single GPU of unknown memory.
+func (c *GPUConfig) WithPartition(migProfile string, timeSlicingReplicas int) *GPUConfig {
+	out := GPUConfig{SKU: "unknown", GPUCount: 1, GPUModel: "unknown"}
+	if c != nil {
+		out = *c
+	}
+	out.MIGProfile = migProfile
+	out.TimeSlicingReplicas = timeSlicingReplicas
+	return &out
+}
+
+// IsPartitioned returns true if a partition of a GPU is requested instead of whole GPUs.
+func (c *GPUConfig) IsPartitioned() bool {
+	return c != nil && (c.MIGProfile != "" || c.TimeSlicingReplicas > 0)
+}
+
+// PerGPUMemGB returns the GPU memory available to a pod per requested GPU, which is the memory of
+// the MIG instance or the time-sliced share of the GPU if a partition is requested. It returns 0 if
+// the memory is unknown.
+func (c *GPUConfig) PerGPUMemGB() int {
+	if c == nil {
+		return 0
+	}
+	if c.MIGProfile != "" {
+		memGB, _ := ParseMIGProfile(c.MIGProfile)
+		return memGB
+	}
+	if c.GPUCount <= 0 {
+		return 0
+	}
+	perGPUMemGB := c.GPUMemGB / c.GPUCount
+	if c.TimeSlicingReplicas > 0 {
+		return perGPUMemGB / c.TimeSlicingReplicas
+	}
+	return perGPUMemGB
+}
+
+var migProfileRegex = regexp.MustCompile(`^[1-7]g\.([1-9][0-9]*)gb$`)
+
+// ParseMIGProfile returns the GPU memory of a MIG profile such as "1g.10gb" or "3g.40gb".
+func ParseMIGProfile(profile string) (int, error) {
+	match := migProfileRegex.FindStringSubmatch(profile)
+	if match == nil {
+		return 0, fmt.Errorf("invalid MIG profile %q, expected a profile such as 1g.10gb", profile)
+	}
+	return strconv.Atoi(match[1])
 }
 
 func GetCloudSKUHandler(cloud string) CloudSKUHandler {
@@ -38,6 +116,8 @@ func GetCloudSKUHandler(cloud string) CloudSKUHandler {
 		return NewAwsSKUHandler()
 	case consts.ArcCloudName:
 		return NewArcSKUHandler()
+	case consts.GCPCloudName:
+		return NewGCPSKUHandler()
 	default:
 		return nil
 	}
@@ -70,3 +70,145 @@ func TestAwsSKUHandler(t *testing.T) {
 		t.Errorf("Unsupported SKU found in GPUConfigs")
 	}
 }
+
+func TestGCPSKUHandler(t *testing.T) {
+	handler := NewGCPSKUHandler()
+
+	// Test GetSupportedSKUs
+	skus := handler.GetSupportedSKUs()
+	if len(skus) == 0 {
+		t.Errorf("GetSupportedSKUs returned an empty array")
+	}
+
+	// Test GetGPUConfigs with a SKU that is supported
+	sku := "a3-highgpu-8g"
+	gpuConfig1 := handler.GetGPUConfigBySKU(sku)
+	if gpuConfig1 == nil {
+		t.Fatalf("Supported SKU missing from GPUConfigs")
+	}
+	if gpuConfig1.SKU != sku || gpuConfig1.GPUCount != 8 {
+		t.Errorf("Incorrect config returned for a supported SKU")
+	}
+
+	// Test GetGPUConfigs with a SKU that is not supported
+	sku = "n2-standard-8"
+	gpuConfig2 := handler.GetGPUConfigBySKU(sku)
+	if gpuConfig2 != nil {
+		t.Errorf("Unsupported SKU found in GPUConfigs")
+	}
+}
+
+func TestGetGKEAcceleratorType(t *testing.T) {
+	testcases := map[string]string{
+		"a2-highgpu-1g":  "nvidia-tesla-a100",
+		"a2-megagpu-16g": "nvidia-tesla-a100",
+		"a2-ultragpu-8g": "nvidia-a100-80gb",
+		"a3-highgpu-8g":  "nvidia-h100-80gb",
+		"a3-megagpu-8g":  "nvidia-h100-mega-80gb",
+		"g2-standard-48": "nvidia-l4",
+		"n2-standard-8":  "",
+		"invalid":        "",
+	}
+
+	for sku, expected := range testcases {
+		if got := GetGKEAcceleratorType(sku); got != expected {
+			t.Errorf("GetGKEAcceleratorType(%q) = %q, expected %q", sku, got, expected)
+		}
+	}
+}
+
+func TestGPUConfigVendor(t *testing.T) {
+	testcases := map[string]struct {
+		gpuConfig *GPUConfig
+		expected  GPUVendor
+	}{
+		"nil config defaults to nvidia": {
+			gpuConfig: nil,
+			expected:  GPUVendorNvidia,
+		},
+		"nvidia sku": {
+			gpuConfig: NewAzureSKUHandler().GetGPUConfigBySKU("Standard_NC6s_v3"),
+			expected:  GPUVendorNvidia,
+		},
+		"azure amd sku": {
+			gpuConfig: NewAzureSKUHandler().GetGPUConfigBySKU("Standard_NG32ads_V620_v1"),
+			expected:  GPUVendorAMD,
+		},
+		"aws amd sku": {
+			gpuConfig: NewAwsSKUHandler().GetGPUConfigBySKU("g4ad.xlarge"),
+			expected:  GPUVendorAMD,
+		},
+	}
+
+	for name, tc := range testcases {
+		if got := tc.gpuConfig.Vendor(); got != tc.expected {
+			t.Errorf("%s: Vendor() = %q, expected %q", name, got, tc.expected)
+		}
+	}
+}
+
+func TestGPUConfigPerGPUMemGB(t *testing.T) {
+	a100 := &GPUConfig{SKU: "Standard_NC96ads_A100_v4", GPUCount: 4, GPUMemGB: 320}
+
+	testcases := map[string]struct {
+		gpuConfig *GPUConfig
+		expected  int
+	}{
+		"nil config": {
+			gpuConfig: nil,
+			expected:  0,
+		},
+		"whole gpu": {
+			gpuConfig: a100,
+			expected:  80,
+		},
+		"mig profile": {
+			gpuConfig: a100.WithPartition("1g.10gb", 0),
+			expected:  10,
+		},
+		"time slicing": {
+			gpuConfig: a100.WithPartition("", 4),
+			expected:  20,
+		},
+		"mig profile of unknown sku": {
+			gpuConfig: (*GPUConfig)(nil).WithPartition("3g.40gb", 0),
+			expected:  40,
+		},
+	}
+
+	for name, tc := range testcases {
+		if got := tc.gpuConfig.PerGPUMemGB(); got != tc.expected {
+			t.Errorf("%s: PerGPUMemGB() = %d, expected %d", name, got, tc.expected)
+		}
+	}
+	if a100.IsPartitioned() {
+		t.Errorf("WithPartition must not modify the original config")
+	}
+}
+
+func TestParseMIGProfile(t *testing.T) {
+	testcases := map[string]struct {
+		expected  int
+		expectErr bool
+	}{
+		"1g.10gb":  {expected: 10},
+		"3g.40gb":  {expected: 40},
+		"7g.80gb":  {expected: 80},
+		"1g.10":    {expectErr: true},
+		"8g.80gb":  {expectErr: true},
+		"mig-1g":   {expectErr: true},
+		"1g.0gb":   {expectErr: true},
+		"":         {expectErr: true},
+		"1g.10gb+": {expectErr: true},
+	}
+
+	for profile, tc := range testcases {
+		got, err := ParseMIGProfile(profile)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("ParseMIGProfile(%q) error = %v, expectErr %v", profile, err, tc.expectErr)
+		}
+		if got != tc.expected {
+			t.Errorf("ParseMIGProfile(%q) = %d, expected %d", profile, got, tc.expected)
+		}
+	}
+}
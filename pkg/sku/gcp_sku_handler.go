@@ -0,0 +1,76 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sku
+
+import (
+	"strings"
+)
+
+var (
+	// gkeAcceleratorTypes maps a GCP accelerator-optimized machine series to the value
+	// of the `cloud.google.com/gke-accelerator` label that GKE sets on its nodes.
+	// Reference: https://cloud.google.com/kubernetes-engine/docs/how-to/gpus#available-gpus
+	gkeAcceleratorTypes = map[string]string{
+		"a2-highgpu":  "nvidia-tesla-a100",
+		"a2-megagpu":  "nvidia-tesla-a100",
+		"a2-ultragpu": "nvidia-a100-80gb",
+		"a3-highgpu":  "nvidia-h100-80gb",
+		"a3-megagpu":  "nvidia-h100-mega-80gb",
+		"a3-ultragpu": "nvidia-h200-141gb",
+		"g2-standard": "nvidia-l4",
+	}
+)
+
+func NewGCPSKUHandler() CloudSKUHandler {
+	// Reference: https://cloud.google.com/compute/docs/gpus
+	supportedSKUs := []GPUConfig{
+		// https://cloud.google.com/compute/docs/accelerator-optimized-machines#a2-vms
+		{SKU: "a2-highgpu-1g", GPUCount: 1, GPUMemGB: 40, GPUModel: "NVIDIA A100"},
+		{SKU: "a2-highgpu-2g", GPUCount: 2, GPUMemGB: 80, GPUModel: "NVIDIA A100"},
+		{SKU: "a2-highgpu-4g", GPUCount: 4, GPUMemGB: 160, GPUModel: "NVIDIA A100"},
+		{SKU: "a2-highgpu-8g", GPUCount: 8, GPUMemGB: 320, GPUModel: "NVIDIA A100"},
+		{SKU: "a2-megagpu-16g", GPUCount: 16, GPUMemGB: 640, GPUModel: "NVIDIA A100"},
+		{SKU: "a2-ultragpu-1g", GPUCount: 1, GPUMemGB: 80, GPUModel: "NVIDIA A100", NVMeDiskEnabled: true},
+		{SKU: "a2-ultragpu-2g", GPUCount: 2, GPUMemGB: 160, GPUModel: "NVIDIA A100", NVMeDiskEnabled: true},
+		{SKU: "a2-ultragpu-4g", GPUCount: 4, GPUMemGB: 320, GPUModel: "NVIDIA A100", NVMeDiskEnabled: true},
+		{SKU: "a2-ultragpu-8g", GPUCount: 8, GPUMemGB: 640, GPUModel: "NVIDIA A100", NVMeDiskEnabled: true},
+		// https://cloud.google.com/compute/docs/accelerator-optimized-machines#a3-vms
+		{SKU: "a3-highgpu-1g", GPUCount: 1, GPUMemGB: 80, GPUModel: "NVIDIA H100", NVMeDiskEnabled: true},
+		{SKU: "a3-highgpu-2g", GPUCount: 2, GPUMemGB: 160, GPUModel: "NVIDIA H100", NVMeDiskEnabled: true},
+		{SKU: "a3-highgpu-4g", GPUCount: 4, GPUMemGB: 320, GPUModel: "NVIDIA H100", NVMeDiskEnabled: true},
+		{SKU: "a3-highgpu-8g", GPUCount: 8, GPUMemGB: 640, GPUModel: "NVIDIA H100", NVMeDiskEnabled: true},
+		{SKU: "a3-megagpu-8g", GPUCount: 8, GPUMemGB: 640, GPUModel: "NVIDIA H100", NVMeDiskEnabled: true},
+		{SKU: "a3-ultragpu-8g", GPUCount: 8, GPUMemGB: 1128, GPUModel: "NVIDIA H200", NVMeDiskEnabled: true},
+		// https://cloud.google.com/compute/docs/accelerator-optimized-machines#g2-vms
+		{SKU: "g2-standard-4", GPUCount: 1, GPUMemGB: 24, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-8", GPUCount: 1, GPUMemGB: 24, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-12", GPUCount: 1, GPUMemGB: 24, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-16", GPUCount: 1, GPUMemGB: 24, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-24", GPUCount: 2, GPUMemGB: 48, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-32", GPUCount: 1, GPUMemGB: 24, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-48", GPUCount: 4, GPUMemGB: 96, GPUModel: "NVIDIA L4"},
+		{SKU: "g2-standard-96", GPUCount: 8, GPUMemGB: 192, GPUModel: "NVIDIA L4"},
+	}
+	return NewGeneralSKUHandler(supportedSKUs)
+}
+
+// GetGKEAcceleratorType returns the GKE accelerator label value for the given GCP machine type.
+// An empty string is returned if the machine type does not belong to a known accelerator series.
+func GetGKEAcceleratorType(sku string) string {
+	idx := strings.LastIndex(sku, "-")
+	if idx <= 0 {
+		return ""
+	}
+	return gkeAcceleratorTypes[sku[:idx]]
+}
@@ -26,6 +26,9 @@ const (
 	DefaultDataVolumePath     = "/mnt/data"
 	DefaultAdapterVolumePath  = "/mnt/adapter"
 	DefaultWeightsVolumePath  = "/workspace/weights"
+	DefaultEngineVolumePath   = "/mnt/engine"
+	// DefaultEnginePath is where the TensorRT-LLM engine is compiled to and pulled to.
+	DefaultEnginePath = DefaultEngineVolumePath + "/engine"
 
 	DefaultORASToolImage = "mcr.microsoft.com/oss/v2/oras-project/oras:v1.2.3"
 )
@@ -209,6 +212,21 @@ func ConfigAdapterVolume() (corev1.Volume, corev1.VolumeMount) {
 	return volume, volumeMount
 }
 
+func ConfigEngineVolume() (corev1.Volume, corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: "engine-volume",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+
+	volumeMount := corev1.VolumeMount{
+		Name:      "engine-volume",
+		MountPath: DefaultEngineVolumePath,
+	}
+	return volume, volumeMount
+}
+
 func GetPresetImageName(name, tag string) string {
 	return fmt.Sprintf("%s/kaito-%s:%s",
 		os.Getenv("PRESET_REGISTRY_NAME"),
@@ -112,10 +112,18 @@ func MergeConfigMaps(baseMap, overrideMap map[string]string) map[string]string {
 	return merged
 }
 
+// BuildCmdStr appends the run parameters to the base command. The parameters of each map are sorted, so that
+// the command, and thus the pod template, only changes when the parameters change.
 func BuildCmdStr(baseCommand string, runParams ...map[string]string) string {
 	updatedBaseCommand := baseCommand
 	for _, runParam := range runParams {
-		for key, value := range runParam {
+		keys := make([]string, 0, len(runParam))
+		for key := range runParam {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := runParam[key]
 			if value == "" {
 				updatedBaseCommand = fmt.Sprintf("%s --%s", updatedBaseCommand, key)
 			} else {
@@ -183,22 +191,35 @@ func GetGPUConfigBySKU(instanceType string) (*sku.GPUConfig, error) {
 }
 
 func TryGetGPUConfigFromNode(ctx context.Context, kubeClient client.Client, workerNodes []string) (*sku.GPUConfig, error) {
-	skuGPUCount, err := FetchGPUCountFromNodes(ctx, kubeClient, workerNodes)
-	if err != nil || skuGPUCount == 0 {
+	nodeList, err := listNodesByName(ctx, kubeClient, workerNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GPU count from nodes: %w", err)
+	}
+	skuGPUCount, vendor := getPerNodeGPUCapacity(nodeList)
+	if skuGPUCount == 0 {
 		return nil, fmt.Errorf("failed to fetch GPU count from nodes: %w", err)
 	}
 
 	return &sku.GPUConfig{
-		SKU:      "unknown", // SKU is not available from nodes
-		GPUCount: skuGPUCount,
-		GPUModel: "unknown", // GPU model is not available from nodes
+		SKU:       "unknown", // SKU is not available from nodes
+		GPUCount:  skuGPUCount,
+		GPUModel:  "unknown", // GPU model is not available from nodes
+		GPUVendor: vendor,
 	}, nil
 }
 
 // FetchGPUCountFromNodes retrieves the GPU count from the given node names.
 func FetchGPUCountFromNodes(ctx context.Context, kubeClient client.Client, nodeNames []string) (int, error) {
+	nodeList, err := listNodesByName(ctx, kubeClient, nodeNames)
+	if err != nil {
+		return 0, err
+	}
+	return GetPerNodeGPUCountFromNodes(nodeList), nil
+}
+
+func listNodesByName(ctx context.Context, kubeClient client.Client, nodeNames []string) (*v1.NodeList, error) {
 	if len(nodeNames) == 0 {
-		return 0, fmt.Errorf("no worker nodes found in the workspace")
+		return nil, fmt.Errorf("no worker nodes found in the workspace")
 	}
 
 	var allNodes v1.NodeList
@@ -214,18 +235,29 @@ func FetchGPUCountFromNodes(ctx context.Context, kubeClient client.Client, nodeN
 		}
 		allNodes.Items = append(allNodes.Items, nodeList.Items...)
 	}
-
-	return GetPerNodeGPUCountFromNodes(&allNodes), nil
+	return &allNodes, nil
 }
 
 func GetPerNodeGPUCountFromNodes(nodeList *v1.NodeList) int {
+	gpuCount, _ := getPerNodeGPUCapacity(nodeList)
+	return gpuCount
+}
+
+// getPerNodeGPUCapacity returns the GPU capacity of the first node that advertises GPUs
+// and the vendor of its device plugin.
+func getPerNodeGPUCapacity(nodeList *v1.NodeList) (int, sku.GPUVendor) {
 	for _, node := range nodeList.Items {
-		gpuCount, exists := node.Status.Capacity[consts.NvidiaGPU]
-		if exists && gpuCount.String() != "" {
-			return int(gpuCount.Value())
+		for resourceName, vendor := range map[v1.ResourceName]sku.GPUVendor{
+			consts.NvidiaGPU: sku.GPUVendorNvidia,
+			consts.AMDGPU:    sku.GPUVendorAMD,
+		} {
+			gpuCount, exists := node.Status.Capacity[resourceName]
+			if exists && gpuCount.String() != "" && !gpuCount.IsZero() {
+				return int(gpuCount.Value()), vendor
+			}
 		}
 	}
-	return 0
+	return 0, sku.GPUVendorNvidia
 }
 
 func ExtractAndValidateRepoName(image string) error {
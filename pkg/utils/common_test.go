@@ -52,6 +52,17 @@ func TestFetchGPUCountFromNodes(t *testing.T) {
 		},
 	}
 
+	amdNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "amd-node",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				"amd.com/gpu": resource.MustParse("4"),
+			},
+		},
+	}
+
 	node3 := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "node-3",
@@ -81,6 +92,12 @@ func TestFetchGPUCountFromNodes(t *testing.T) {
 			nodes:       []runtime.Object{node1, node2},
 			expectedGPU: 2,
 		},
+		{
+			name:        "Node with AMD GPU",
+			nodeNames:   []string{"amd-node"},
+			nodes:       []runtime.Object{amdNode},
+			expectedGPU: 4,
+		},
 		{
 			name:        "Node without GPU",
 			nodeNames:   []string{"node-3"},
@@ -354,6 +371,19 @@ func TestBuildIfElseCmdStr(t *testing.T) {
 			falseCmdParams: nil,
 			expected:       "if nil_false_params; then true_cmd --t_param=t_val; else false_cmd; fi",
 		},
+		{
+			name:      "Multiple parameters are sorted",
+			condition: "sorted_params",
+			trueCmd:   "true_cmd",
+			trueCmdParams: map[string]string{
+				"c": "",
+				"a": "1",
+				"b": "2",
+			},
+			falseCmd:       "false_cmd",
+			falseCmdParams: map[string]string{"z": "", "y": "3"},
+			expected:       "if sorted_params; then true_cmd --a=1 --b=2 --c; else false_cmd --y=3 --z; fi",
+		},
 	}
 
 	for _, tt := range tests {
@@ -24,15 +24,20 @@ const (
 	AzureCloudName                = "azure"
 	AWSCloudName                  = "aws"
 	ArcCloudName                  = "arc"
+	GCPCloudName                  = "gcp"
 	GPUString                     = "gpu"
 	SKUString                     = "sku"
 	MaxRevisionHistoryLimit       = 10
 	GiBToBytes                    = 1024 * 1024 * 1024 // Conversion factor from GiB to bytes
 	NvidiaGPU                     = "nvidia.com/gpu"
+	AMDGPU                        = "amd.com/gpu"
 
 	// Feature flags
-	FeatureFlagVLLM            = "vLLM"
-	FeatureFlagEnsureNodeClass = "ensureNodeClass"
+	FeatureFlagVLLM                        = "vLLM"
+	FeatureFlagEnsureNodeClass             = "ensureNodeClass"
+	FeatureFlagDisableNodeAutoProvisioning = "disableNodeAutoProvisioning"
+	// FeatureFlagGatewayAPIInferenceExtension generates Gateway API Inference Extension resources for workspaces.
+	FeatureFlagGatewayAPIInferenceExtension = "gatewayAPIInferenceExtension"
 
 	// Nodeclaim related consts
 	KaitoNodePoolName             = "kaito"
@@ -40,6 +45,12 @@ const (
 	ErrorInstanceTypesUnavailable = "all requested instance types were unavailable during launch"
 	NodeClassName                 = "default"
 
+	// gcp related consts
+	LabelGKEAccelerator = "cloud.google.com/gke-accelerator"
+	GCPNodeClassGroup   = "karpenter.k8s.gcp"
+	GCPNodeClassVersion = "v1alpha1"
+	GCPNodeClassKind    = "GCENodeClass"
+
 	// machine related consts
 	ProvisionerName           = "default"
 	LabelGPUProvisionerCustom = "kaito.sh/machine-type"
@@ -48,6 +59,10 @@ const (
 	// azure gpu sku prefix
 	GpuSkuPrefix = "Standard_N"
 
+	// Azure taints spot nodes with kubernetes.azure.com/scalesetpriority=spot:NoSchedule.
+	LabelAzureScaleSetPriority = "kubernetes.azure.com/scalesetpriority"
+	AzureSpotPriority          = "spot"
+
 	NodePluginInstallTimeout = 60 * time.Second
 )
 
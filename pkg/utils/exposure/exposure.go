@@ -0,0 +1,403 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+const (
+	// EnvInternalLoadBalancerAnnotations is the environment variable holding the annotations, as a JSON
+	// object, that make a LoadBalancer service internal. The annotations of the cloud provider are used if unset.
+	EnvInternalLoadBalancerAnnotations = "INTERNAL_LOAD_BALANCER_ANNOTATIONS"
+
+	// AnnotationServiceAnnotations records the annotations of the service set from the exposure, so that
+	// they are removed from the service once they are no longer set.
+	AnnotationServiceAnnotations = kaitov1beta1.KAITOPrefix + "exposure-service-annotations"
+
+	annotationCertManagerIssuer        = "cert-manager.io/issuer"
+	annotationCertManagerClusterIssuer = "cert-manager.io/cluster-issuer"
+)
+
+// HTTPRouteGVK is the kind of the Gateway API HTTPRoute, which is generated as an unstructured object to not
+// depend on the Gateway API module.
+var HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// defaultInternalLoadBalancerAnnotations are the annotations that make a LoadBalancer service internal
+// for each cloud provider.
+var defaultInternalLoadBalancerAnnotations = map[string]map[string]string{
+	consts.AzureCloudName: {"service.beta.kubernetes.io/azure-load-balancer-internal": "true"},
+	consts.AWSCloudName:   {"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"},
+	consts.GCPCloudName:   {"networking.gke.io/load-balancer-type": "Internal"},
+}
+
+// Target is the service exposed and the object owning the resources exposing it.
+type Target struct {
+	Owner       metav1.OwnerReference
+	Namespace   string
+	ServiceName string
+	ServicePort int32
+}
+
+// FromV1alpha1 converts the exposure of a v1alpha1 resource, e.g. a RAGEngine.
+func FromV1alpha1(spec *kaitov1alpha1.ExposureSpec) *kaitov1beta1.ExposureSpec {
+	if spec == nil {
+		return nil
+	}
+	out := &kaitov1beta1.ExposureSpec{
+		Type:               kaitov1beta1.ExposureType(spec.Type),
+		Hostname:           spec.Hostname,
+		PathPrefix:         spec.PathPrefix,
+		IngressClassName:   spec.IngressClassName,
+		Internal:           spec.Internal,
+		Annotations:        spec.Annotations,
+		ServiceAnnotations: spec.ServiceAnnotations,
+	}
+	if spec.Gateway != nil {
+		out.Gateway = &kaitov1beta1.GatewayReference{
+			Name:        spec.Gateway.Name,
+			Namespace:   spec.Gateway.Namespace,
+			SectionName: spec.Gateway.SectionName,
+		}
+	}
+	if spec.TLS != nil {
+		out.TLS = &kaitov1beta1.ExposureTLSSpec{SecretName: spec.TLS.SecretName}
+		if spec.TLS.Issuer != nil {
+			out.TLS.Issuer = &kaitov1beta1.IssuerReference{Name: spec.TLS.Issuer.Name, Kind: spec.TLS.Issuer.Kind}
+		}
+	}
+	return out
+}
+
+// ServiceType returns the type of the exposed service. Without exposure, the service is a LoadBalancer
+// service if the kaito.sh/enablelb annotation is set.
+func ServiceType(spec *kaitov1beta1.ExposureSpec, enableLB bool) corev1.ServiceType {
+	if spec == nil {
+		if enableLB {
+			return corev1.ServiceTypeLoadBalancer
+		}
+		return corev1.ServiceTypeClusterIP
+	}
+	if spec.Type == kaitov1beta1.ExposureTypeLoadBalancer {
+		return corev1.ServiceTypeLoadBalancer
+	}
+	return corev1.ServiceTypeClusterIP
+}
+
+// InternalLoadBalancerAnnotations returns the annotations that make a LoadBalancer service internal.
+func InternalLoadBalancerAnnotations() (map[string]string, error) {
+	if value := os.Getenv(EnvInternalLoadBalancerAnnotations); value != "" {
+		annotations := map[string]string{}
+		if err := json.Unmarshal([]byte(value), &annotations); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvInternalLoadBalancerAnnotations, err)
+		}
+		return annotations, nil
+	}
+	cloudProvider := os.Getenv("CLOUD_PROVIDER")
+	annotations, found := defaultInternalLoadBalancerAnnotations[cloudProvider]
+	if !found {
+		return nil, fmt.Errorf("no internal load balancer annotations for cloud provider %q, set %s", cloudProvider, EnvInternalLoadBalancerAnnotations)
+	}
+	return annotations, nil
+}
+
+// ServiceAnnotations returns the annotations of the exposed service set from the exposure.
+func ServiceAnnotations(spec *kaitov1beta1.ExposureSpec) (map[string]string, error) {
+	annotations := map[string]string{}
+	if spec == nil {
+		return annotations, nil
+	}
+	if spec.Type == kaitov1beta1.ExposureTypeLoadBalancer && spec.Internal {
+		internal, err := InternalLoadBalancerAnnotations()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range internal {
+			annotations[k] = v
+		}
+	}
+	for k, v := range spec.ServiceAnnotations {
+		annotations[k] = v
+	}
+	return annotations, nil
+}
+
+// UpdateService sets the type and the annotations of the exposed service, removing the annotations that were
+// set from the exposure before and are no longer desired. It returns true if the service was changed.
+func UpdateService(serviceObj *corev1.Service, serviceType corev1.ServiceType, annotations map[string]string) bool {
+	changed := false
+	if serviceObj.Spec.Type != serviceType {
+		serviceObj.Spec.Type = serviceType
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			// Fields only valid for LoadBalancer services must be cleared.
+			for i := range serviceObj.Spec.Ports {
+				serviceObj.Spec.Ports[i].NodePort = 0
+			}
+			serviceObj.Spec.ExternalTrafficPolicy = ""
+			serviceObj.Spec.HealthCheckNodePort = 0
+			serviceObj.Spec.AllocateLoadBalancerNodePorts = nil
+			serviceObj.Spec.LoadBalancerClass = nil
+		}
+		changed = true
+	}
+
+	existing := serviceObj.GetAnnotations()
+	if existing == nil {
+		existing = map[string]string{}
+	}
+	for _, key := range strings.Split(existing[AnnotationServiceAnnotations], ",") {
+		if _, desired := annotations[key]; key != "" && !desired {
+			delete(existing, key)
+			changed = true
+		}
+	}
+	keys := make([]string, 0, len(annotations))
+	for k, v := range annotations {
+		keys = append(keys, k)
+		if existing[k] != v {
+			existing[k] = v
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	if managed := strings.Join(keys, ","); existing[AnnotationServiceAnnotations] != managed {
+		existing[AnnotationServiceAnnotations] = managed
+		if managed == "" {
+			delete(existing, AnnotationServiceAnnotations)
+		}
+		changed = true
+	}
+	serviceObj.SetAnnotations(existing)
+	return changed
+}
+
+func pathPrefix(spec *kaitov1beta1.ExposureSpec) string {
+	if spec.PathPrefix == "" {
+		return "/"
+	}
+	return spec.PathPrefix
+}
+
+// GenerateIngressManifest generates the Ingress routing the host name of the exposure to the service. When a
+// cert-manager issuer is set, cert-manager issues the certificate of the Ingress.
+func GenerateIngressManifest(target Target, spec *kaitov1beta1.ExposureSpec) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            target.ServiceName,
+			Namespace:       target.Namespace,
+			Annotations:     map[string]string{},
+			OwnerReferences: []metav1.OwnerReference{target.Owner},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: spec.Hostname,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: target.ServiceName,
+											Port: networkingv1.ServiceBackendPort{Number: target.ServicePort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for k, v := range spec.Annotations {
+		ingress.Annotations[k] = v
+	}
+	if tls := spec.TLS; tls != nil {
+		secretName := tls.SecretName
+		if secretName == "" {
+			secretName = target.ServiceName + "-tls"
+		}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{spec.Hostname}, SecretName: secretName}}
+		if issuer := tls.Issuer; issuer != nil {
+			if issuer.Kind == "ClusterIssuer" {
+				ingress.Annotations[annotationCertManagerClusterIssuer] = issuer.Name
+			} else {
+				ingress.Annotations[annotationCertManagerIssuer] = issuer.Name
+			}
+		}
+	}
+	return ingress
+}
+
+// GenerateHTTPRouteManifest generates the HTTPRoute attaching the host name and path prefix of the exposure
+// to the Gateway. The path prefix is stripped before the requests are forwarded to the service.
+func GenerateHTTPRouteManifest(target Target, spec *kaitov1beta1.ExposureSpec) *unstructured.Unstructured {
+	// The defaults of the Gateway API are set explicitly, so that the route matches the stored one.
+	parentRef := map[string]interface{}{"group": HTTPRouteGVK.Group, "kind": "Gateway", "name": spec.Gateway.Name}
+	if spec.Gateway.Namespace != "" {
+		parentRef["namespace"] = spec.Gateway.Namespace
+	}
+	if spec.Gateway.SectionName != "" {
+		parentRef["sectionName"] = spec.Gateway.SectionName
+	}
+	rule := map[string]interface{}{
+		"matches": []interface{}{
+			map[string]interface{}{
+				"path": map[string]interface{}{"type": "PathPrefix", "value": pathPrefix(spec)},
+			},
+		},
+		"backendRefs": []interface{}{
+			map[string]interface{}{"group": "", "kind": "Service", "name": target.ServiceName, "port": int64(target.ServicePort), "weight": int64(1)},
+		},
+	}
+	if pathPrefix(spec) != "/" {
+		rule["filters"] = []interface{}{
+			map[string]interface{}{
+				"type": "URLRewrite",
+				"urlRewrite": map[string]interface{}{
+					"path": map[string]interface{}{"type": "ReplacePrefixMatch", "replacePrefixMatch": "/"},
+				},
+			},
+		}
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(target.ServiceName)
+	route.SetNamespace(target.Namespace)
+	route.SetOwnerReferences([]metav1.OwnerReference{target.Owner})
+	if len(spec.Annotations) > 0 {
+		route.SetAnnotations(spec.Annotations)
+	}
+	route.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  []interface{}{spec.Hostname},
+		"rules":      []interface{}{rule},
+	}
+	return route
+}
+
+// Ensure creates or updates the Ingress or HTTPRoute exposing the service, and deletes the ones of the
+// target that are no longer desired, e.g. after the exposure type changed. An Ingress or HTTPRoute of the
+// same name that the target does not own is never changed.
+func Ensure(ctx context.Context, kubeClient client.Client, target Target, spec *kaitov1beta1.ExposureSpec) error {
+	var desiredIngress *networkingv1.Ingress
+	var desiredRoute *unstructured.Unstructured
+	if spec != nil {
+		switch spec.Type {
+		case kaitov1beta1.ExposureTypeIngress:
+			desiredIngress = GenerateIngressManifest(target, spec)
+		case kaitov1beta1.ExposureTypeHTTPRoute:
+			desiredRoute = GenerateHTTPRouteManifest(target, spec)
+		}
+	}
+	key := client.ObjectKey{Namespace: target.Namespace, Name: target.ServiceName}
+
+	existingIngress := &networkingv1.Ingress{}
+	if err := kubeClient.Get(ctx, key, existingIngress); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		existingIngress = nil
+	}
+	switch {
+	case desiredIngress != nil && existingIngress == nil:
+		klog.InfoS("CreateIngress", "ingress", klog.KObj(desiredIngress))
+		if err := kubeClient.Create(ctx, desiredIngress); err != nil {
+			return err
+		}
+	case desiredIngress != nil:
+		if !ownedBy(existingIngress, target.Owner) {
+			return fmt.Errorf("ingress %s already exists and is not owned by %s %s", key, target.Owner.Kind, target.Owner.Name)
+		}
+		if !equality.Semantic.DeepEqual(existingIngress.Spec, desiredIngress.Spec) ||
+			!equality.Semantic.DeepEqual(existingIngress.Annotations, desiredIngress.Annotations) {
+			existingIngress.Spec = desiredIngress.Spec
+			existingIngress.Annotations = desiredIngress.Annotations
+			klog.InfoS("UpdateIngress", "ingress", klog.KObj(existingIngress))
+			if err := kubeClient.Update(ctx, existingIngress); err != nil {
+				return err
+			}
+		}
+	case existingIngress != nil && ownedBy(existingIngress, target.Owner):
+		klog.InfoS("DeleteIngress", "ingress", klog.KObj(existingIngress))
+		if err := kubeClient.Delete(ctx, existingIngress); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	existingRoute := &unstructured.Unstructured{}
+	existingRoute.SetGroupVersionKind(HTTPRouteGVK)
+	if err := kubeClient.Get(ctx, key, existingRoute); err != nil {
+		// The Gateway API CRDs are only needed for HTTPRoute exposure.
+		if !apierrors.IsNotFound(err) && (desiredRoute != nil || !meta.IsNoMatchError(err)) {
+			return err
+		}
+		existingRoute = nil
+	}
+	switch {
+	case desiredRoute != nil && existingRoute == nil:
+		klog.InfoS("CreateHTTPRoute", "httproute", klog.KObj(desiredRoute))
+		return kubeClient.Create(ctx, desiredRoute)
+	case desiredRoute != nil:
+		if !ownedBy(existingRoute, target.Owner) {
+			return fmt.Errorf("httproute %s already exists and is not owned by %s %s", key, target.Owner.Kind, target.Owner.Name)
+		}
+		if !equality.Semantic.DeepEqual(existingRoute.Object["spec"], desiredRoute.Object["spec"]) ||
+			!equality.Semantic.DeepEqual(existingRoute.GetAnnotations(), desiredRoute.GetAnnotations()) {
+			existingRoute.Object["spec"] = desiredRoute.Object["spec"]
+			existingRoute.SetAnnotations(desiredRoute.GetAnnotations())
+			klog.InfoS("UpdateHTTPRoute", "httproute", klog.KObj(existingRoute))
+			return kubeClient.Update(ctx, existingRoute)
+		}
+	case existingRoute != nil && ownedBy(existingRoute, target.Owner):
+		klog.InfoS("DeleteHTTPRoute", "httproute", klog.KObj(existingRoute))
+		return client.IgnoreNotFound(kubeClient.Delete(ctx, existingRoute))
+	}
+	return nil
+}
+
+func ownedBy(obj client.Object, owner metav1.OwnerReference) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.UID {
+			return true
+		}
+	}
+	return false
+}
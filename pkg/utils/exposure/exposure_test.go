@@ -0,0 +1,245 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+)
+
+var testTarget = Target{
+	Owner:       metav1.OwnerReference{APIVersion: "kaito.sh/v1beta1", Kind: "Workspace", Name: "ws", UID: "uid-1", Controller: ptr.To(true)},
+	Namespace:   "default",
+	ServiceName: "ws",
+	ServicePort: 80,
+}
+
+func TestServiceAnnotations(t *testing.T) {
+	testcases := map[string]struct {
+		spec        *kaitov1beta1.ExposureSpec
+		cloud       string
+		override    string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		"No exposure": {
+			expected: map[string]string{},
+		},
+		"Internal load balancer on Azure": {
+			spec:     &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeLoadBalancer, Internal: true},
+			cloud:    consts.AzureCloudName,
+			expected: map[string]string{"service.beta.kubernetes.io/azure-load-balancer-internal": "true"},
+		},
+		"Internal load balancer on AWS with extra annotations": {
+			spec: &kaitov1beta1.ExposureSpec{
+				Type:               kaitov1beta1.ExposureTypeLoadBalancer,
+				Internal:           true,
+				ServiceAnnotations: map[string]string{"example.com/team": "ml"},
+			},
+			cloud: consts.AWSCloudName,
+			expected: map[string]string{
+				"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal",
+				"example.com/team": "ml",
+			},
+		},
+		"Internal load balancer annotations overridden": {
+			spec:     &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeLoadBalancer, Internal: true},
+			cloud:    consts.AzureCloudName,
+			override: `{"example.com/internal":"yes"}`,
+			expected: map[string]string{"example.com/internal": "yes"},
+		},
+		"Internal load balancer on unknown cloud": {
+			spec:        &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeLoadBalancer, Internal: true},
+			cloud:       "onprem",
+			expectedErr: true,
+		},
+		"Invalid override": {
+			spec:        &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeLoadBalancer, Internal: true},
+			override:    `not-json`,
+			expectedErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv("CLOUD_PROVIDER", tc.cloud)
+			t.Setenv(EnvInternalLoadBalancerAnnotations, tc.override)
+			annotations, err := ServiceAnnotations(tc.spec)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, annotations)
+		})
+	}
+}
+
+func TestUpdateService(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"user/owned": "keep"}},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 80}}},
+	}
+
+	changed := UpdateService(svc, corev1.ServiceTypeLoadBalancer, map[string]string{"a": "1", "b": "2"})
+	assert.True(t, changed)
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	assert.Equal(t, map[string]string{"user/owned": "keep", "a": "1", "b": "2", AnnotationServiceAnnotations: "a,b"}, svc.Annotations)
+
+	assert.False(t, UpdateService(svc, corev1.ServiceTypeLoadBalancer, map[string]string{"a": "1", "b": "2"}))
+
+	svc.Spec.Ports[0].NodePort = 30080
+	svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	svc.Spec.HealthCheckNodePort = 31000
+	changed = UpdateService(svc, corev1.ServiceTypeClusterIP, map[string]string{})
+	assert.True(t, changed)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, svc.Spec.Type)
+	assert.Equal(t, int32(0), svc.Spec.Ports[0].NodePort)
+	assert.Empty(t, svc.Spec.ExternalTrafficPolicy)
+	assert.Equal(t, int32(0), svc.Spec.HealthCheckNodePort)
+	assert.Equal(t, map[string]string{"user/owned": "keep"}, svc.Annotations)
+}
+
+func TestGenerateIngressManifest(t *testing.T) {
+	spec := &kaitov1beta1.ExposureSpec{
+		Type:             kaitov1beta1.ExposureTypeIngress,
+		Hostname:         "ws.example.com",
+		IngressClassName: ptr.To("nginx"),
+		Annotations:      map[string]string{"example.com/a": "b"},
+		TLS: &kaitov1beta1.ExposureTLSSpec{
+			Issuer: &kaitov1beta1.IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		},
+	}
+
+	ingress := GenerateIngressManifest(testTarget, spec)
+	assert.Equal(t, "ws", ingress.Name)
+	assert.Equal(t, []metav1.OwnerReference{testTarget.Owner}, ingress.OwnerReferences)
+	assert.Equal(t, ptr.To("nginx"), ingress.Spec.IngressClassName)
+	assert.Equal(t, map[string]string{"example.com/a": "b", annotationCertManagerClusterIssuer: "letsencrypt"}, ingress.Annotations)
+	assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"ws.example.com"}, SecretName: "ws-tls"}}, ingress.Spec.TLS)
+	rule := ingress.Spec.Rules[0]
+	assert.Equal(t, "ws.example.com", rule.Host)
+	assert.Equal(t, "/", rule.HTTP.Paths[0].Path)
+	assert.Equal(t, "ws", rule.HTTP.Paths[0].Backend.Service.Name)
+	assert.Equal(t, int32(80), rule.HTTP.Paths[0].Backend.Service.Port.Number)
+
+	spec.TLS = &kaitov1beta1.ExposureTLSSpec{SecretName: "my-cert", Issuer: &kaitov1beta1.IssuerReference{Name: "ca"}}
+	ingress = GenerateIngressManifest(testTarget, spec)
+	assert.Equal(t, "my-cert", ingress.Spec.TLS[0].SecretName)
+	assert.Equal(t, "ca", ingress.Annotations[annotationCertManagerIssuer])
+}
+
+func TestGenerateHTTPRouteManifest(t *testing.T) {
+	spec := &kaitov1beta1.ExposureSpec{
+		Type:     kaitov1beta1.ExposureTypeHTTPRoute,
+		Hostname: "ws.example.com",
+		Gateway:  &kaitov1beta1.GatewayReference{Name: "gw", Namespace: "gateways", SectionName: "https"},
+	}
+
+	route := GenerateHTTPRouteManifest(testTarget, spec)
+	assert.Equal(t, HTTPRouteGVK, route.GroupVersionKind())
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gw", "namespace": "gateways", "sectionName": "https",
+	}}, parentRefs)
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	assert.Len(t, rules, 1)
+	_, hasFilters := rules[0].(map[string]interface{})["filters"]
+	assert.False(t, hasFilters)
+
+	spec.PathPrefix = "/ws"
+	route = GenerateHTTPRouteManifest(testTarget, spec)
+	rules, _, _ = unstructured.NestedSlice(route.Object, "spec", "rules")
+	rule := rules[0].(map[string]interface{})
+	filters := rule["filters"].([]interface{})
+	replace, _, _ := unstructured.NestedString(filters[0].(map[string]interface{}), "urlRewrite", "path", "replacePrefixMatch")
+	assert.Equal(t, "/", replace)
+}
+
+func TestEnsureIngress(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, networkingv1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+	ctx := context.Background()
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+	spec := &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeIngress, Hostname: "ws.example.com"}
+
+	assert.NoError(t, Ensure(ctx, c, testTarget, spec))
+	ingress := &networkingv1.Ingress{}
+	assert.NoError(t, c.Get(ctx, key, ingress))
+	assert.Equal(t, "ws.example.com", ingress.Spec.Rules[0].Host)
+
+	spec.Hostname = "new.example.com"
+	assert.NoError(t, Ensure(ctx, c, testTarget, spec))
+	assert.NoError(t, c.Get(ctx, key, ingress))
+	assert.Equal(t, "new.example.com", ingress.Spec.Rules[0].Host)
+
+	// Removing the exposure deletes the Ingress.
+	assert.NoError(t, Ensure(ctx, c, testTarget, nil))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, key, ingress)))
+}
+
+func TestEnsureDoesNotChangeResourcesOfOthers(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, networkingv1.AddToScheme(s))
+	ctx := context.Background()
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+	other := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "other.example.com"}}},
+	}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName("ws")
+	route.SetNamespace("default")
+	route.Object["spec"] = map[string]interface{}{"hostnames": []interface{}{"other.example.com"}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(other, route).Build()
+
+	err := Ensure(ctx, c, testTarget, &kaitov1beta1.ExposureSpec{Type: kaitov1beta1.ExposureTypeIngress, Hostname: "ws.example.com"})
+	assert.ErrorContains(t, err, "not owned by Workspace ws")
+	ingress := &networkingv1.Ingress{}
+	assert.NoError(t, c.Get(ctx, key, ingress))
+	assert.Equal(t, "other.example.com", ingress.Spec.Rules[0].Host)
+
+	err = Ensure(ctx, c, testTarget, &kaitov1beta1.ExposureSpec{
+		Type:     kaitov1beta1.ExposureTypeHTTPRoute,
+		Hostname: "ws.example.com",
+		Gateway:  &kaitov1beta1.GatewayReference{Name: "gw"},
+	})
+	assert.ErrorContains(t, err, "not owned by Workspace ws")
+	existingRoute := &unstructured.Unstructured{}
+	existingRoute.SetGroupVersionKind(HTTPRouteGVK)
+	assert.NoError(t, c.Get(ctx, key, existingRoute))
+	hostnames, _, _ := unstructured.NestedSlice(existingRoute.Object, "spec", "hostnames")
+	assert.Equal(t, []interface{}{"other.example.com"}, hostnames)
+
+	// Without exposure, the resources of others are not deleted either.
+	assert.NoError(t, Ensure(ctx, c, testTarget, nil))
+	assert.NoError(t, c.Get(ctx, key, ingress))
+	assert.NoError(t, c.Get(ctx, key, existingRoute))
+}
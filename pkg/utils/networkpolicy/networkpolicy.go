@@ -0,0 +1,215 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// FromV1alpha1 converts the network policy of a v1alpha1 resource, e.g. a RAGEngine.
+func FromV1alpha1(spec *kaitov1alpha1.NetworkPolicySpec) *kaitov1beta1.NetworkPolicySpec {
+	if spec == nil {
+		return nil
+	}
+	return &kaitov1beta1.NetworkPolicySpec{
+		AllowedNamespaces:      spec.AllowedNamespaces,
+		AllowedNamespaceLabels: spec.AllowedNamespaceLabels,
+		AllowedPodLabels:       spec.AllowedPodLabels,
+	}
+}
+
+// NamespacePeer returns the peer selecting the pods of the namespace, all pods if podSelector is nil.
+func NamespacePeer(namespace string, podSelector *metav1.LabelSelector) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}},
+		PodSelector:       podSelector,
+	}
+}
+
+// AllowedPeers returns the peers that can reach the service of a resource in the namespace: the pods of the
+// namespace of the resource and of the allowed namespaces, restricted to the allowed pod labels.
+func AllowedPeers(spec *kaitov1beta1.NetworkPolicySpec, namespace string) []networkingv1.NetworkPolicyPeer {
+	var podSelector *metav1.LabelSelector
+	if len(spec.AllowedPodLabels) > 0 {
+		podSelector = &metav1.LabelSelector{MatchLabels: spec.AllowedPodLabels}
+	}
+	peers := []networkingv1.NetworkPolicyPeer{NamespacePeer(namespace, podSelector)}
+	for _, allowed := range spec.AllowedNamespaces {
+		if allowed != namespace {
+			peers = append(peers, NamespacePeer(allowed, podSelector))
+		}
+	}
+	if len(spec.AllowedNamespaceLabels) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: spec.AllowedNamespaceLabels},
+			PodSelector:       podSelector,
+		})
+	}
+	return peers
+}
+
+// ServiceIngressRule returns the rule allowing the peers to reach the ports of the service. The ports of a
+// LoadBalancer service are reachable from outside the cluster, so they are open to all sources.
+func ServiceIngressRule(spec *kaitov1beta1.NetworkPolicySpec, namespace string, serviceType corev1.ServiceType, ports ...int32) networkingv1.NetworkPolicyIngressRule {
+	rule := networkingv1.NetworkPolicyIngressRule{Ports: TCPPorts(ports...)}
+	if serviceType != corev1.ServiceTypeLoadBalancer {
+		rule.From = AllowedPeers(spec, namespace)
+	}
+	return rule
+}
+
+// TCPPorts returns the TCP ports of a rule.
+func TCPPorts(ports ...int32) []networkingv1.NetworkPolicyPort {
+	out := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		out = append(out, tcpPort(intstr.FromInt32(port)))
+	}
+	return out
+}
+
+func tcpPort(port intstr.IntOrString) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+// DNSEgressRule returns the rule allowing the pods to resolve names with the DNS servers of the cluster.
+func DNSEgressRule() networkingv1.NetworkPolicyEgressRule {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	port := intstr.FromInt32(53)
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &port}, {Protocol: &tcp, Port: &port}},
+	}
+}
+
+// EgressRuleForURL returns the rule allowing the pods in the namespace to reach the service at the URL.
+// A URL of a service of the cluster only allows the pods of the service, an IP address only the address.
+// The addresses behind an external host name can change, so only its port is restricted.
+func EgressRuleForURL(ctx context.Context, kubeClient client.Client, namespace, rawURL string) (networkingv1.NetworkPolicyEgressRule, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("invalid url %q: missing host", rawURL)
+	}
+	port := int32(80)
+	if u.Port() != "" {
+		p, err := strconv.ParseInt(u.Port(), 10, 32)
+		if err != nil {
+			return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("invalid url %q: %w", rawURL, err)
+		}
+		port = int32(p)
+	} else if u.Scheme == "https" {
+		port = 443
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		bits := 32
+		if ip.To4() == nil {
+			bits = 128
+		}
+		return networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: fmt.Sprintf("%s/%d", ip, bits)}}},
+			Ports: TCPPorts(port),
+		}, nil
+	}
+
+	if name, svcNamespace, ok := clusterService(host, namespace); ok {
+		svc := &corev1.Service{}
+		err := kubeClient.Get(ctx, client.ObjectKey{Namespace: svcNamespace, Name: name}, svc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return networkingv1.NetworkPolicyEgressRule{}, err
+		}
+		// Services without selector, e.g. ExternalName services, are treated as external hosts.
+		if err == nil && len(svc.Spec.Selector) > 0 {
+			// Policies apply after the service address is translated, so the rule selects the target port of the pods.
+			targetPort := intstr.FromInt32(port)
+			for _, p := range svc.Spec.Ports {
+				if p.Port == port && (p.TargetPort.Type == intstr.String || p.TargetPort.IntVal != 0) {
+					targetPort = p.TargetPort
+				}
+			}
+			return networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{NamespacePeer(svcNamespace, &metav1.LabelSelector{MatchLabels: svc.Spec.Selector})},
+				Ports: []networkingv1.NetworkPolicyPort{tcpPort(targetPort)},
+			}, nil
+		}
+	}
+	return networkingv1.NetworkPolicyEgressRule{Ports: TCPPorts(port)}, nil
+}
+
+// clusterService returns the service and namespace of a host name that may name a service of the cluster,
+// i.e. "<service>", "<service>.<namespace>" or "<service>.<namespace>.svc[.<cluster domain>]".
+func clusterService(host, namespace string) (string, string, bool) {
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	switch {
+	case len(parts) == 1:
+		return parts[0], namespace, true
+	case len(parts) == 2:
+		return parts[0], parts[1], true
+	case parts[2] == "svc":
+		return parts[0], parts[1], true
+	}
+	return "", "", false
+}
+
+// Ensure creates or updates the NetworkPolicy, or deletes the NetworkPolicy of the owner when desired is nil,
+// e.g. after the network policy of the owner was removed.
+func Ensure(ctx context.Context, kubeClient client.Client, key client.ObjectKey, owner metav1.OwnerReference, desired *networkingv1.NetworkPolicy) error {
+	existing := &networkingv1.NetworkPolicy{}
+	if err := kubeClient.Get(ctx, key, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if desired == nil {
+			return nil
+		}
+		klog.InfoS("CreateNetworkPolicy", "networkpolicy", klog.KObj(desired))
+		return kubeClient.Create(ctx, desired)
+	}
+
+	if desired == nil {
+		for _, ref := range existing.GetOwnerReferences() {
+			if ref.UID == owner.UID {
+				klog.InfoS("DeleteNetworkPolicy", "networkpolicy", klog.KObj(existing))
+				return client.IgnoreNotFound(kubeClient.Delete(ctx, existing))
+			}
+		}
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return nil
+	}
+	existing.Spec = desired.Spec
+	klog.InfoS("UpdateNetworkPolicy", "networkpolicy", klog.KObj(existing))
+	return kubeClient.Update(ctx, existing)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func TestAllowedPeers(t *testing.T) {
+	spec := &kaitov1beta1.NetworkPolicySpec{
+		AllowedNamespaces:      []string{"default", "apps"},
+		AllowedNamespaceLabels: map[string]string{"team": "ml"},
+		AllowedPodLabels:       map[string]string{"role": "client"},
+	}
+	podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"role": "client"}}
+
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{
+		NamespacePeer("default", podSelector),
+		NamespacePeer("apps", podSelector),
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ml"}}, PodSelector: podSelector},
+	}, AllowedPeers(spec, "default"))
+
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{NamespacePeer("default", nil)},
+		AllowedPeers(&kaitov1beta1.NetworkPolicySpec{}, "default"))
+}
+
+func TestServiceIngressRule(t *testing.T) {
+	spec := &kaitov1beta1.NetworkPolicySpec{}
+
+	rule := ServiceIngressRule(spec, "default", corev1.ServiceTypeClusterIP, 5000)
+	assert.Equal(t, AllowedPeers(spec, "default"), rule.From)
+	assert.Equal(t, TCPPorts(5000), rule.Ports)
+
+	rule = ServiceIngressRule(spec, "default", corev1.ServiceTypeLoadBalancer, 5000)
+	assert.Empty(t, rule.From)
+	assert.Equal(t, TCPPorts(5000), rule.Ports)
+}
+
+func TestEgressRuleForURL(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(s))
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "workspace-phi", Namespace: "models"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"kaito.sh/workspace": "workspace-phi"},
+				Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt32(5001)}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "api.example.com"},
+		},
+	).Build()
+	workspacePeer := NamespacePeer("models", &metav1.LabelSelector{MatchLabels: map[string]string{"kaito.sh/workspace": "workspace-phi"}})
+
+	testcases := map[string]struct {
+		url         string
+		expected    networkingv1.NetworkPolicyEgressRule
+		expectedErr bool
+	}{
+		"Service of the cluster": {
+			url:      "http://workspace-phi.models.svc.cluster.local/v1/chat/completions",
+			expected: networkingv1.NetworkPolicyEgressRule{To: []networkingv1.NetworkPolicyPeer{workspacePeer}, Ports: TCPPorts(5001)},
+		},
+		"Short service name in another namespace": {
+			url:      "http://workspace-phi.models",
+			expected: networkingv1.NetworkPolicyEgressRule{To: []networkingv1.NetworkPolicyPeer{workspacePeer}, Ports: TCPPorts(5001)},
+		},
+		"Service without selector": {
+			url:      "https://external/v1",
+			expected: networkingv1.NetworkPolicyEgressRule{Ports: TCPPorts(443)},
+		},
+		"External host name": {
+			url:      "https://api.openai.com/v1",
+			expected: networkingv1.NetworkPolicyEgressRule{Ports: TCPPorts(443)},
+		},
+		"IP address": {
+			url: "http://10.0.0.4:8080/v1",
+			expected: networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.4/32"}}},
+				Ports: TCPPorts(8080),
+			},
+		},
+		"Invalid URL": {
+			url:         "not a url",
+			expectedErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			rule, err := EgressRuleForURL(context.Background(), c, "default", tc.url)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rule)
+		})
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, networkingv1.AddToScheme(s))
+	ctx := context.Background()
+	owner := metav1.OwnerReference{APIVersion: "kaito.sh/v1beta1", Kind: "Workspace", Name: "ws", UID: "uid-1"}
+	key := client.ObjectKey{Namespace: "default", Name: "ws"}
+	policy := func(port int32) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", OwnerReferences: []metav1.OwnerReference{owner}},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				Ingress:     []networkingv1.NetworkPolicyIngressRule{{Ports: TCPPorts(port)}},
+			},
+		}
+	}
+
+	t.Run("Should create, update and delete the policy of the owner", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(s).Build()
+
+		assert.NoError(t, Ensure(ctx, c, key, owner, policy(5000)))
+		existing := &networkingv1.NetworkPolicy{}
+		assert.NoError(t, c.Get(ctx, key, existing))
+		assert.Equal(t, TCPPorts(5000), existing.Spec.Ingress[0].Ports)
+
+		assert.NoError(t, Ensure(ctx, c, key, owner, policy(5001)))
+		assert.NoError(t, c.Get(ctx, key, existing))
+		assert.Equal(t, TCPPorts(5001), existing.Spec.Ingress[0].Ports)
+
+		assert.NoError(t, Ensure(ctx, c, key, owner, nil))
+		assert.True(t, apierrors.IsNotFound(c.Get(ctx, key, existing)))
+	})
+
+	t.Run("Should not delete a policy of another owner", func(t *testing.T) {
+		other := policy(5000)
+		other.OwnerReferences = nil
+		c := fake.NewClientBuilder().WithScheme(s).WithObjects(other).Build()
+
+		assert.NoError(t, Ensure(ctx, c, key, owner, nil))
+		assert.NoError(t, c.Get(ctx, key, &networkingv1.NetworkPolicy{}))
+	})
+}
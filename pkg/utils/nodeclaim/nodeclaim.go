@@ -31,6 +31,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -45,6 +46,7 @@ import (
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 )
@@ -112,12 +114,18 @@ func GenerateNodeClaimManifest(storageRequirement string, obj client.Object) *ka
 
 	cloudName := os.Getenv("CLOUD_PROVIDER")
 
+	// CPU nodes are neither tainted nor required to have GPUs.
+	ws, isWorkspace := obj.(*kaitov1beta1.Workspace)
+	requireGPU := !isWorkspace || !kaitov1beta1.IsCPUInference(ws)
+
 	var nodeClassRefKind string
 
 	if cloudName == consts.AzureCloudName {
 		nodeClassRefKind = "AKSNodeClass"
 	} else if cloudName == consts.AWSCloudName { //aws
 		nodeClassRefKind = "EC2NodeClass"
+	} else if cloudName == consts.GCPCloudName {
+		nodeClassRefKind = consts.GCPNodeClassKind
 	}
 	nodeClaimObj := &karpenterv1.NodeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -131,13 +139,6 @@ func GenerateNodeClaimManifest(storageRequirement string, obj client.Object) *ka
 				Name: consts.NodeClassName,
 				Kind: nodeClassRefKind,
 			},
-			Taints: []v1.Taint{
-				{
-					Key:    consts.SKUString,
-					Value:  consts.GPUString,
-					Effect: v1.TaintEffectNoSchedule,
-				},
-			},
 			Requirements: []karpenterv1.NodeSelectorRequirementWithMinValues{
 				{
 					NodeSelectorRequirement: v1.NodeSelectorRequirement{
@@ -169,6 +170,14 @@ func GenerateNodeClaimManifest(storageRequirement string, obj client.Object) *ka
 		},
 	}
 
+	if requireGPU {
+		nodeClaimObj.Spec.Taints = append(nodeClaimObj.Spec.Taints, v1.Taint{
+			Key:    consts.SKUString,
+			Value:  consts.GPUString,
+			Effect: v1.TaintEffectNoSchedule,
+		})
+	}
+
 	if cloudName == consts.AzureCloudName {
 		nodeSelector := karpenterv1.NodeSelectorRequirementWithMinValues{
 			NodeSelectorRequirement: v1.NodeSelectorRequirement{
@@ -180,7 +189,7 @@ func GenerateNodeClaimManifest(storageRequirement string, obj client.Object) *ka
 		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, nodeSelector)
 	}
 
-	if cloudName == consts.AWSCloudName {
+	if cloudName == consts.AWSCloudName && requireGPU {
 		nodeSelector := karpenterv1.NodeSelectorRequirementWithMinValues{
 			NodeSelectorRequirement: v1.NodeSelectorRequirement{
 				Key:      "karpenter.k8s.aws/instance-gpu-count",
@@ -191,9 +200,60 @@ func GenerateNodeClaimManifest(storageRequirement string, obj client.Object) *ka
 		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, nodeSelector)
 	}
 
+	if cloudName == consts.GCPCloudName && requireGPU {
+		if acceleratorType := sku.GetGKEAcceleratorType(instanceType); acceleratorType != "" {
+			nodeSelector := karpenterv1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{
+					Key:      consts.LabelGKEAccelerator,
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{acceleratorType},
+				},
+			}
+			nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, nodeSelector)
+		}
+
+		// GKE taints GPU nodes with nvidia.com/gpu=present:NoSchedule, declare it so that
+		// Karpenter does not treat the taint as unexpected when the node registers.
+		nodeClaimObj.Spec.Taints = append(nodeClaimObj.Spec.Taints, v1.Taint{
+			Key:    resources.CapacityNvidiaGPU,
+			Value:  "present",
+			Effect: v1.TaintEffectNoSchedule,
+		})
+	}
+
+	if isWorkspace && ws.IsSpot() {
+		SetCapacityType(nodeClaimObj, karpenterv1.CapacityTypeSpot)
+	}
+
 	return nodeClaimObj
 }
 
+// SetCapacityType sets the Karpenter capacity type requirement of the nodeClaim. Azure spot nodes are
+// tainted by the cloud provider, so the taint is declared on the nodeClaim as well.
+func SetCapacityType(nodeClaimObj *karpenterv1.NodeClaim, capacityType string) {
+	nodeClaimObj.Spec.Requirements = lo.Reject(nodeClaimObj.Spec.Requirements, func(requirement karpenterv1.NodeSelectorRequirementWithMinValues, _ int) bool {
+		return requirement.Key == karpenterv1.CapacityTypeLabelKey
+	})
+	nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, karpenterv1.NodeSelectorRequirementWithMinValues{
+		NodeSelectorRequirement: v1.NodeSelectorRequirement{
+			Key:      karpenterv1.CapacityTypeLabelKey,
+			Operator: v1.NodeSelectorOpIn,
+			Values:   []string{capacityType},
+		},
+	})
+
+	nodeClaimObj.Spec.Taints = lo.Reject(nodeClaimObj.Spec.Taints, func(taint v1.Taint, _ int) bool {
+		return taint.Key == consts.LabelAzureScaleSetPriority
+	})
+	if capacityType == karpenterv1.CapacityTypeSpot && os.Getenv("CLOUD_PROVIDER") == consts.AzureCloudName {
+		nodeClaimObj.Spec.Taints = append(nodeClaimObj.Spec.Taints, v1.Taint{
+			Key:    consts.LabelAzureScaleSetPriority,
+			Value:  consts.AzureSpotPriority,
+			Effect: v1.TaintEffectNoSchedule,
+		})
+	}
+}
+
 // GenerateNodeClaimName generates a nodeClaim name from the given workspace or RAGEngine.
 func GenerateNodeClaimName(obj client.Object) string {
 	// Determine the type of the input object and extract relevant fields
@@ -252,6 +312,32 @@ func GenerateEC2NodeClassManifest(ctx context.Context) *awsv1beta1.EC2NodeClass
 	}
 }
 
+func GenerateGCENodeClassManifest(ctx context.Context) *unstructured.Unstructured {
+	clusterName := os.Getenv("CLUSTER_NAME")
+	nodeClass := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"imageSelectorTerms": []interface{}{
+					map[string]interface{}{
+						"alias": "ContainerOptimizedOS@latest",
+					},
+				},
+				"serviceAccount": os.Getenv("GCP_SERVICE_ACCOUNT"),
+				"tags": map[string]interface{}{
+					"karpenter.sh/discovery": clusterName,
+				},
+			},
+		},
+	}
+	nodeClass.SetAPIVersion(consts.GCPNodeClassGroup + "/" + consts.GCPNodeClassVersion)
+	nodeClass.SetKind(consts.GCPNodeClassKind)
+	nodeClass.SetName(consts.NodeClassName)
+	nodeClass.SetAnnotations(map[string]string{
+		"kubernetes.io/description": "General purpose GCENodeClass for running Container-Optimized OS nodes",
+	})
+	return nodeClass
+}
+
 // CreateNodeClaim creates a nodeClaim object.
 func CreateNodeClaim(ctx context.Context, nodeClaimObj *karpenterv1.NodeClaim, kubeClient client.Client) error {
 	klog.InfoS("CreateNodeClaim", "nodeClaim", klog.KObj(nodeClaimObj))
@@ -276,6 +362,13 @@ func CreateKarpenterNodeClass(ctx context.Context, kubeClient client.Client) err
 	} else if cloudName == consts.AWSCloudName {
 		nodeClassObj := GenerateEC2NodeClassManifest(ctx)
 		return kubeClient.Create(ctx, nodeClassObj, &client.CreateOptions{})
+	} else if cloudName == consts.GCPCloudName {
+		// The nodes cannot pull images or register without a service account.
+		if os.Getenv("GCP_SERVICE_ACCOUNT") == "" {
+			return errors.New("GCP_SERVICE_ACCOUNT environment variable cannot be empty when the cloud provider is gcp, set gcpServiceAccount in the chart values")
+		}
+		nodeClassObj := GenerateGCENodeClassManifest(ctx)
+		return kubeClient.Create(ctx, nodeClassObj, &client.CreateOptions{})
 	} else {
 		return errors.New("unsupported cloud provider " + cloudName)
 	}
@@ -412,6 +505,12 @@ func IsNodeClassAvailable(ctx context.Context, cloudName string, kubeClient clie
 		err := kubeClient.Get(ctx, client.ObjectKey{Name: consts.NodeClassName},
 			&awsv1beta1.EC2NodeClass{}, &client.GetOptions{})
 		return err == nil
+	} else if cloudName == consts.GCPCloudName {
+		nodeClass := &unstructured.Unstructured{}
+		nodeClass.SetAPIVersion(consts.GCPNodeClassGroup + "/" + consts.GCPNodeClassVersion)
+		nodeClass.SetKind(consts.GCPNodeClassKind)
+		err := kubeClient.Get(ctx, client.ObjectKey{Name: consts.NodeClassName}, nodeClass, &client.GetOptions{})
+		return err == nil
 	}
 	klog.Error("unsupported cloud provider ", cloudName)
 	return false
@@ -428,7 +527,7 @@ func CheckNodeClass(ctx context.Context, kClient client.Client) error {
 		klog.Infof("NodeClass is not available, creating NodeClass")
 		if err := CreateKarpenterNodeClass(ctx, kClient); err != nil && client.IgnoreAlreadyExists(err) != nil {
 			klog.ErrorS(err, "unable to create NodeClass")
-			return errors.New("error while creating NodeClass")
+			return fmt.Errorf("error while creating NodeClass: %w", err)
 		}
 	}
 	return nil
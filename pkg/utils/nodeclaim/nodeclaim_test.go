@@ -24,13 +24,17 @@ import (
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
@@ -75,6 +79,73 @@ func TestCreateNodeClaim(t *testing.T) {
 	}
 }
 
+func TestCreateNodeClaimWithGCENodeClass(t *testing.T) {
+	testcases := map[string]struct {
+		callMocks           func(c *test.MockClient)
+		expectNodeClassCall bool
+		expectedError       error
+	}{
+		"GCENodeClass is created before the nodeClaim when it does not exist": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(apierrors.NewNotFound(schema.GroupResource{Group: consts.GCPNodeClassGroup, Resource: "gcenodeclasses"}, consts.NodeClassName))
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).Return(nil)
+			},
+			expectNodeClassCall: true,
+			expectedError:       nil,
+		},
+		"Existing GCENodeClass is reused": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).Return(nil)
+			},
+			expectNodeClassCall: false,
+			expectedError:       nil,
+		},
+		"GCENodeClass creation fails": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(apierrors.NewNotFound(schema.GroupResource{Group: consts.GCPNodeClassGroup, Resource: "gcenodeclasses"}, consts.NodeClassName))
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(errors.New("failed to create GCENodeClass"))
+			},
+			expectNodeClassCall: true,
+			expectedError:       errors.New("error while creating NodeClass: failed to create GCENodeClass"),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			originalGate := featuregates.FeatureGates[consts.FeatureFlagEnsureNodeClass]
+			featuregates.FeatureGates[consts.FeatureFlagEnsureNodeClass] = true
+			defer func() {
+				featuregates.FeatureGates[consts.FeatureFlagEnsureNodeClass] = originalGate
+			}()
+
+			t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+			t.Setenv("CLUSTER_NAME", "test-cluster")
+			t.Setenv("GCP_SERVICE_ACCOUNT", "kaito@test-project.iam.gserviceaccount.com")
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.InstanceType = "g2-standard-8"
+			nodeClaim := GenerateNodeClaimManifest("0", workspace)
+
+			err := CreateNodeClaim(context.Background(), nodeClaim, mockClient)
+			if tc.expectedError == nil {
+				assert.Check(t, err == nil, "Not expected to return error")
+				mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything)
+			} else {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			}
+			if tc.expectNodeClassCall {
+				mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything)
+			} else {
+				mockClient.AssertNotCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything)
+			}
+		})
+	}
+}
+
 func TestWaitForPendingNodeClaims(t *testing.T) {
 	testcases := map[string]struct {
 		callMocks           func(c *test.MockClient)
@@ -198,6 +269,58 @@ func TestGenerateNodeClaimManifest(t *testing.T) {
 		assert.Check(t, nodeClaim.Spec.NodeClassRef != nil, "NodeClaim must have NodeClassRef")
 		assert.Equal(t, nodeClaim.Spec.NodeClassRef.Kind, "EC2NodeClass", "NodeClaim must have 'EC2NodeClass' kind")
 	})
+
+	t.Run("Should generate a nodeClaim object from the given workspace when cloud provider set to gcp", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.InstanceType = "a2-ultragpu-1g"
+		t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+
+		nodeClaim := GenerateNodeClaimManifest("0", mockWorkspace)
+
+		assert.Check(t, nodeClaim != nil, "NodeClaim must not be nil")
+		assert.Equal(t, nodeClaim.Namespace, mockWorkspace.Namespace, "NodeClaim must have same namespace as workspace")
+		assert.Equal(t, nodeClaim.Labels[kaitov1beta1.LabelWorkspaceName], mockWorkspace.Name, "label must have same workspace name as workspace")
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 4, " NodeClaim must have 4 NodeSelector Requirements")
+		assert.Equal(t, nodeClaim.Spec.Requirements[1].NodeSelectorRequirement.Values[0], "a2-ultragpu-1g", "NodeClaim must have same instance type as workspace")
+		assert.Equal(t, nodeClaim.Spec.Requirements[3].NodeSelectorRequirement.Key, consts.LabelGKEAccelerator, "NodeClaim must have GKE accelerator label")
+		assert.Equal(t, nodeClaim.Spec.Requirements[3].NodeSelectorRequirement.Values[0], "nvidia-a100-80gb", "NodeClaim must have the GKE accelerator type of the instance type")
+		assert.Equal(t, len(nodeClaim.Spec.Taints), 2, "NodeClaim must have 2 taints")
+		assert.Equal(t, nodeClaim.Spec.Taints[1].Key, "nvidia.com/gpu", "NodeClaim must have the GKE GPU taint")
+		assert.Check(t, nodeClaim.Spec.NodeClassRef != nil, "NodeClaim must have NodeClassRef")
+		assert.Equal(t, nodeClaim.Spec.NodeClassRef.Kind, "GCENodeClass", "NodeClaim must have 'GCENodeClass' kind")
+	})
+
+	t.Run("Should generate a spot nodeClaim object from the given spot workspace", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.CapacityType = kaitov1beta1.CapacityTypeSpot
+		t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+		nodeClaim := GenerateNodeClaimManifest("0", mockWorkspace)
+
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 5, " NodeClaim must have 5 NodeSelector Requirements")
+		assert.Equal(t, nodeClaim.Spec.Requirements[4].NodeSelectorRequirement.Key, karpenterv1.CapacityTypeLabelKey, "NodeClaim must have capacity type requirement")
+		assert.Equal(t, nodeClaim.Spec.Requirements[4].NodeSelectorRequirement.Values[0], karpenterv1.CapacityTypeSpot, "NodeClaim must request spot capacity")
+		assert.Equal(t, len(nodeClaim.Spec.Taints), 2, "NodeClaim must have 2 taints")
+		assert.Equal(t, nodeClaim.Spec.Taints[1].Key, consts.LabelAzureScaleSetPriority, "NodeClaim must have the Azure spot taint")
+
+		SetCapacityType(nodeClaim, karpenterv1.CapacityTypeOnDemand)
+
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 5, " NodeClaim must have 5 NodeSelector Requirements")
+		assert.Equal(t, nodeClaim.Spec.Requirements[4].NodeSelectorRequirement.Values[0], karpenterv1.CapacityTypeOnDemand, "NodeClaim must request on-demand capacity")
+		assert.Equal(t, len(nodeClaim.Spec.Taints), 1, "NodeClaim must not have the Azure spot taint")
+	})
+
+	t.Run("Should generate a nodeClaim object without GPU requirements for a CPU workspace", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.InstanceType = "m6i.2xlarge"
+		mockWorkspace.Annotations = map[string]string{kaitov1beta1.AnnotationWorkspaceRuntime: "llamacpp"}
+		t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+
+		nodeClaim := GenerateNodeClaimManifest("0", mockWorkspace)
+
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 3, " NodeClaim must not require instance GPUs")
+		assert.Equal(t, len(nodeClaim.Spec.Taints), 0, "NodeClaim must not have the GPU taint")
+	})
 }
 
 func TestGenerateAKSNodeClassManifest(t *testing.T) {
@@ -263,6 +386,26 @@ func TestGenerateEC2NodeClassManifest(t *testing.T) {
 	})
 }
 
+func TestGenerateGCENodeClassManifest(t *testing.T) {
+	t.Run("Should generate a valid GCENodeClass object with correct name and annotations", func(t *testing.T) {
+		t.Setenv("CLUSTER_NAME", "test-cluster")
+		t.Setenv("GCP_SERVICE_ACCOUNT", "kaito@test-project.iam.gserviceaccount.com")
+
+		nodeClass := GenerateGCENodeClassManifest(context.Background())
+
+		assert.Check(t, nodeClass != nil, "GCENodeClass must not be nil")
+		assert.Equal(t, nodeClass.GetName(), consts.NodeClassName, "GCENodeClass must have the correct name")
+		assert.Equal(t, nodeClass.GetKind(), "GCENodeClass", "GCENodeClass must have the correct kind")
+		assert.Equal(t, nodeClass.GetAPIVersion(), "karpenter.k8s.gcp/v1alpha1", "GCENodeClass must have the correct apiVersion")
+		assert.Equal(t, nodeClass.GetAnnotations()["kubernetes.io/description"], "General purpose GCENodeClass for running Container-Optimized OS nodes", "GCENodeClass must have the correct description annotation")
+
+		serviceAccount, _, _ := unstructured.NestedString(nodeClass.Object, "spec", "serviceAccount")
+		assert.Equal(t, serviceAccount, "kaito@test-project.iam.gserviceaccount.com", "GCENodeClass must have the correct service account")
+		tags, _, _ := unstructured.NestedStringMap(nodeClass.Object, "spec", "tags")
+		assert.Equal(t, tags["karpenter.sh/discovery"], "test-cluster", "GCENodeClass must have the correct discovery tag")
+	})
+}
+
 func TestCreateKarpenterNodeClass(t *testing.T) {
 	t.Run("Should create AKSNodeClass when cloud provider is Azure", func(t *testing.T) {
 		t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
@@ -287,6 +430,31 @@ func TestCreateKarpenterNodeClass(t *testing.T) {
 		mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything)
 	})
 
+	t.Run("Should create GCENodeClass when cloud provider is GCP", func(t *testing.T) {
+		t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+		t.Setenv("CLUSTER_NAME", "test-cluster")
+		t.Setenv("GCP_SERVICE_ACCOUNT", "kaito@test-project.iam.gserviceaccount.com")
+
+		mockClient := test.NewClient()
+		mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+
+		err := CreateKarpenterNodeClass(context.Background(), mockClient)
+		assert.Check(t, err == nil, "Not expected to return error")
+		mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything)
+	})
+
+	t.Run("Should return error when cloud provider is GCP without service account", func(t *testing.T) {
+		t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+		t.Setenv("CLUSTER_NAME", "test-cluster")
+		t.Setenv("GCP_SERVICE_ACCOUNT", "")
+
+		mockClient := test.NewClient()
+
+		err := CreateKarpenterNodeClass(context.Background(), mockClient)
+		assert.ErrorContains(t, err, "GCP_SERVICE_ACCOUNT environment variable cannot be empty")
+		mockClient.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("Should return error when cloud provider is unsupported", func(t *testing.T) {
 		t.Setenv("CLOUD_PROVIDER", "unsupported")
 
@@ -0,0 +1,76 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeclaim
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+// GenerateSharedNodeClaimManifest generates a nodeClaim that can be shared by multiple workspaces.
+// Unlike dedicated nodeClaims, it is not labeled with the workspace name, the workspaces placed on
+// it are tracked by the AnnotationSharedWorkspaces annotation instead.
+func GenerateSharedNodeClaimManifest(storageRequirement string, wObj *kaitov1beta1.Workspace) *karpenterv1.NodeClaim {
+	nodeClaimObj := GenerateNodeClaimManifest(storageRequirement, wObj)
+	if nodeClaimObj == nil {
+		return nil
+	}
+	delete(nodeClaimObj.Labels, kaitov1beta1.LabelWorkspaceName)
+	delete(nodeClaimObj.Labels, kaitov1beta1.LabelWorkspaceNamespace)
+	nodeClaimObj.Labels[kaitov1beta1.LabelSharedNode] = "true"
+	nodeClaimObj.Namespace = ""
+	SetSharedWorkspaces(nodeClaimObj, []string{client.ObjectKeyFromObject(wObj).String()})
+	return nodeClaimObj
+}
+
+// SharedWorkspaces returns the namespace/name keys of the workspaces placed on the shared nodeClaim.
+func SharedWorkspaces(nodeClaimObj *karpenterv1.NodeClaim) []string {
+	value := nodeClaimObj.GetAnnotations()[kaitov1beta1.AnnotationSharedWorkspaces]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// SetSharedWorkspaces records the workspaces placed on the shared nodeClaim.
+func SetSharedWorkspaces(nodeClaimObj *karpenterv1.NodeClaim, workspaces []string) {
+	workspaces = lo.Uniq(workspaces)
+	sort.Strings(workspaces)
+	if nodeClaimObj.Annotations == nil {
+		nodeClaimObj.Annotations = map[string]string{}
+	}
+	nodeClaimObj.Annotations[kaitov1beta1.AnnotationSharedWorkspaces] = strings.Join(workspaces, ",")
+}
+
+// ListSharedNodeClaims lists all shared nodeClaim objects in the cluster.
+func ListSharedNodeClaims(ctx context.Context, kubeClient client.Client) (*karpenterv1.NodeClaimList, error) {
+	nodeClaimList := &karpenterv1.NodeClaimList{}
+	err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return true
+	}, func() error {
+		return kubeClient.List(ctx, nodeClaimList, client.MatchingLabels{kaitov1beta1.LabelSharedNode: "true"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodeClaimList, nil
+}
@@ -16,23 +16,62 @@ package resources
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/sku"
 )
 
 const (
 	LabelKeyNvidia    = "accelerator"
 	LabelValueNvidia  = "nvidia"
 	CapacityNvidiaGPU = "nvidia.com/gpu"
+	LabelValueAMD     = "amd"
+	CapacityAMDGPU    = "amd.com/gpu"
+	// CapacityMIGPrefix is the prefix of the MIG resources advertised by the nvidia device plugin
+	// with the mixed MIG strategy, e.g. nvidia.com/mig-1g.10gb.
+	CapacityMIGPrefix = "nvidia.com/mig-"
+	// LabelNvidiaGPUMemory is the label set by the NVIDIA GPU feature discovery with the memory of each GPU in MiB.
+	LabelNvidiaGPUMemory = "nvidia.com/gpu.memory"
 )
 
+// GPUResourceName returns the extended resource name advertised by the device plugin of the GPU vendor.
+func GPUResourceName(vendor sku.GPUVendor) corev1.ResourceName {
+	if vendor == sku.GPUVendorAMD {
+		return CapacityAMDGPU
+	}
+	return CapacityNvidiaGPU
+}
+
+// GPUConfigResourceName returns the extended resource name requested for a GPU of the GPU config,
+// which is the MIG resource of the MIG profile if one is set.
+func GPUConfigResourceName(gpuConfig *sku.GPUConfig) corev1.ResourceName {
+	if gpuConfig != nil && gpuConfig.MIGProfile != "" {
+		return corev1.ResourceName(CapacityMIGPrefix + gpuConfig.MIGProfile)
+	}
+	return GPUResourceName(gpuConfig.Vendor())
+}
+
+// GPULabelValue returns the value of the accelerator label that enables the device plugin of the GPU vendor.
+func GPULabelValue(vendor sku.GPUVendor) string {
+	if vendor == sku.GPUVendorAMD {
+		return LabelValueAMD
+	}
+	return LabelValueNvidia
+}
+
 // GetNode get kubernetes node object with a provided name
 func GetNode(ctx context.Context, nodeName string, kubeClient client.Client) (*corev1.Node, error) {
 	node := &corev1.Node{}
@@ -60,10 +99,8 @@ func ListNodes(ctx context.Context, kubeClient client.Client, labelSelector clie
 func UpdateNodeWithLabel(ctx context.Context, freshNode *corev1.Node, labelKey, labelValue string, kubeClient client.Client) error {
 	klog.InfoS("UpdateNodeWithLabel", "nodeName", freshNode.Name, "labelKey", labelKey, "labelValue", labelValue)
 
-	if nvidiaLabelVal, found := freshNode.Labels[LabelKeyNvidia]; found {
-		if nvidiaLabelVal == LabelValueNvidia {
-			return nil
-		}
+	if val, found := freshNode.Labels[labelKey]; found && val == labelValue {
+		return nil
 	}
 
 	freshNode.Labels = lo.Assign(freshNode.Labels, map[string]string{labelKey: labelValue})
@@ -77,32 +114,112 @@ func UpdateNodeWithLabel(ctx context.Context, freshNode *corev1.Node, labelKey,
 	return nil
 }
 
-func CheckNvidiaPlugin(ctx context.Context, nodeObj *corev1.Node) bool {
-	// check if label accelerator=nvidia exists in the node
-	var foundLabel, foundCapacity bool
-	if nvidiaLabelVal, found := nodeObj.Labels[LabelKeyNvidia]; found {
-		if nvidiaLabelVal == LabelValueNvidia {
-			foundLabel = true
+// CheckGPUPlugin checks if the device plugin of the GPU vendor is enabled and advertises the GPUs
+// of the GPU config on the node.
+func CheckGPUPlugin(ctx context.Context, nodeObj *corev1.Node, gpuConfig *sku.GPUConfig) bool {
+	// check if label accelerator=<vendor> exists in the node
+	foundLabel := nodeObj.Labels[LabelKeyNvidia] == GPULabelValue(gpuConfig.Vendor())
+
+	// check Status.Capacity.<vendor>.com/gpu, or the MIG resource, has value
+	foundCapacity := HasResourceCapacity(nodeObj, GPUConfigResourceName(gpuConfig))
+
+	return foundLabel && foundCapacity
+}
+
+// ResourceSliceNodeNameField is the field index of the ResourceSlices by the node publishing them.
+const ResourceSliceNodeNameField = "spec.nodeName"
+
+// IndexResourceSliceNodeName indexes the cached ResourceSlices by node, so that CheckDRADriver does not
+// go through the slices of every node in the cluster. It does nothing if the cluster does not serve DRA.
+func IndexResourceSliceNodeName(ctx context.Context, mgr manager.Manager) error {
+	gvk := resourcev1beta1.SchemeGroupVersion.WithKind("ResourceSlice")
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			klog.InfoS("DRA is not served by the cluster, skip indexing the resource slices")
+			return nil
 		}
+		return err
 	}
+	return mgr.GetFieldIndexer().IndexField(ctx, &resourcev1beta1.ResourceSlice{}, ResourceSliceNodeNameField, func(o client.Object) []string {
+		slice := o.(*resourcev1beta1.ResourceSlice)
+		if slice.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{slice.Spec.NodeName}
+	})
+}
 
-	// check Status.Capacity.nvidia.com/gpu has value
-	capacity := nodeObj.Status.Capacity
-	if capacity != nil && !capacity.Name(CapacityNvidiaGPU, "").IsZero() {
-		foundCapacity = true
+// CheckDRADriver checks if a DRA driver publishes devices of the node in a ResourceSlice.
+func CheckDRADriver(ctx context.Context, kubeClient client.Client, nodeName string) (bool, error) {
+	sliceList := &resourcev1beta1.ResourceSliceList{}
+	if err := kubeClient.List(ctx, sliceList, client.MatchingFields{ResourceSliceNodeNameField: nodeName}); err != nil {
+		return false, err
+	}
+	for i := range sliceList.Items {
+		slice := &sliceList.Items[i]
+		if slice.Spec.NodeName == nodeName && len(slice.Spec.Devices) > 0 {
+			return true, nil
+		}
 	}
+	return false, nil
+}
 
-	if foundLabel && foundCapacity {
+// HasVendorGPUCapacity checks if the node reports a non-zero GPU capacity of the GPU vendor. With the mixed MIG
+// strategy, the nvidia device plugin advertises the MIG instances of partitioned GPUs instead of nvidia.com/gpu.
+func HasVendorGPUCapacity(nodeObj *corev1.Node, vendor sku.GPUVendor) bool {
+	if HasResourceCapacity(nodeObj, GPUResourceName(vendor)) {
 		return true
 	}
+	if vendor != sku.GPUVendorNvidia {
+		return false
+	}
+	for name, quantity := range nodeObj.Status.Capacity {
+		if strings.HasPrefix(string(name), CapacityMIGPrefix) && !quantity.IsZero() {
+			return true
+		}
+	}
 	return false
 }
 
+// HasResourceCapacity checks if the node reports a non-zero capacity of the resource.
+func HasResourceCapacity(nodeObj *corev1.Node, resourceName corev1.ResourceName) bool {
+	capacity := nodeObj.Status.Capacity
+	return capacity != nil && !capacity.Name(resourceName, "").IsZero()
+}
+
+// HasGPUCapacity checks if the node reports a non-zero GPU capacity of any supported GPU vendor.
+func HasGPUCapacity(nodeObj *corev1.Node) bool {
+	return HasVendorGPUCapacity(nodeObj, sku.GPUVendorNvidia) || HasVendorGPUCapacity(nodeObj, sku.GPUVendorAMD)
+}
+
+// NodeGPUVendor returns the GPU vendor of the node based on its accelerator label.
+func NodeGPUVendor(nodeObj *corev1.Node) sku.GPUVendor {
+	if nodeObj.Labels[LabelKeyNvidia] == LabelValueAMD {
+		return sku.GPUVendorAMD
+	}
+	return sku.GPUVendorNvidia
+}
+
+// NodeGPUCount returns the number of GPUs advertised by the device plugin of the node.
+func NodeGPUCount(nodeObj *corev1.Node) int64 {
+	return max(nodeObj.Status.Capacity.Name(CapacityNvidiaGPU, resource.DecimalSI).Value(),
+		nodeObj.Status.Capacity.Name(CapacityAMDGPU, resource.DecimalSI).Value())
+}
+
+// NodeGPUMemory returns the memory of each GPU of the node, if the node is labeled with it.
+func NodeGPUMemory(nodeObj *corev1.Node) (*resource.Quantity, bool) {
+	memoryMiB, err := strconv.ParseInt(nodeObj.Labels[LabelNvidiaGPUMemory], 10, 64)
+	if err != nil || memoryMiB <= 0 {
+		return nil, false
+	}
+	return resource.NewQuantity(memoryMiB*1024*1024, resource.BinarySI), true
+}
+
 func ExtractObjFields(obj client.Object) (instanceType, namespace, name string, labelSelector *metav1.LabelSelector,
 	nameLabel, namespaceLabel string, err error) {
 	switch o := obj.(type) {
 	case *kaitov1beta1.Workspace:
-		instanceType = o.Resource.InstanceType
+		instanceType = o.ActiveInstanceType()
 		namespace = o.Namespace
 		name = o.Name
 		labelSelector = o.Resource.LabelSelector
@@ -21,9 +21,12 @@ import (
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 )
 
@@ -53,7 +56,7 @@ func TestUpdateNodeWithLabel(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "mockNode",
 					Labels: map[string]string{
-						LabelKeyNvidia: LabelValueNvidia,
+						"fakeKey": "fakeVal",
 					},
 				},
 			},
@@ -76,6 +79,72 @@ func TestUpdateNodeWithLabel(t *testing.T) {
 	}
 }
 
+func TestCheckDRADriver(t *testing.T) {
+	testcases := map[string]struct {
+		slices        []resourcev1beta1.ResourceSlice
+		listErr       error
+		expectedFound bool
+	}{
+		"Fails to list resource slices": {
+			listErr: errors.New("Cannot retrieve resource slice list"),
+		},
+		"No resource slice for the node": {
+			slices: []resourcev1beta1.ResourceSlice{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "other-node-gpu.nvidia.com"},
+					Spec: resourcev1beta1.ResourceSliceSpec{
+						NodeName: "other-node",
+						Devices:  []resourcev1beta1.Device{{Name: "gpu-0"}},
+					},
+				},
+			},
+			expectedFound: false,
+		},
+		"Resource slice without devices": {
+			slices: []resourcev1beta1.ResourceSlice{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-gpu.nvidia.com"},
+					Spec:       resourcev1beta1.ResourceSliceSpec{NodeName: "node"},
+				},
+			},
+			expectedFound: false,
+		},
+		"Resource slice publishes devices of the node": {
+			slices: []resourcev1beta1.ResourceSlice{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-gpu.nvidia.com"},
+					Spec: resourcev1beta1.ResourceSliceSpec{
+						NodeName: "node",
+						Devices:  []resourcev1beta1.Device{{Name: "gpu-0"}},
+					},
+				},
+			},
+			expectedFound: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			relevantMap := mockClient.CreateMapWithType(&resourcev1beta1.ResourceSliceList{})
+			for i := range tc.slices {
+				relevantMap[client.ObjectKeyFromObject(&tc.slices[i])] = &tc.slices[i]
+			}
+			// Only the slices of the node are listed.
+			expectedOpts := []client.ListOption{client.MatchingFields{ResourceSliceNodeNameField: "node"}}
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&resourcev1beta1.ResourceSliceList{}), expectedOpts).Return(tc.listErr)
+
+			found, err := CheckDRADriver(context.Background(), mockClient, "node")
+			if tc.listErr != nil {
+				assert.Equal(t, tc.listErr.Error(), err.Error())
+				return
+			}
+			assert.Check(t, err == nil, "Not expected to return error")
+			assert.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
+
 func TestListNodes(t *testing.T) {
 	testcases := map[string]struct {
 		callMocks     func(c *test.MockClient)
@@ -125,26 +194,77 @@ func TestListNodes(t *testing.T) {
 	}
 }
 
-func TestCheckNvidiaPlugin(t *testing.T) {
+func TestCheckGPUPlugin(t *testing.T) {
+	amdNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "amd-node",
+			Labels: map[string]string{
+				LabelKeyNvidia: LabelValueAMD,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				CapacityAMDGPU: resource.MustParse("1"),
+			},
+		},
+	}
+
+	migNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mig-node",
+			Labels: map[string]string{
+				LabelKeyNvidia: LabelValueNvidia,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				CapacityMIGPrefix + "1g.10gb": resource.MustParse("7"),
+			},
+		},
+	}
+
 	testcases := map[string]struct {
-		nodeObj        *corev1.Node
-		isNvidiaPlugin bool
+		nodeObj     *corev1.Node
+		gpuConfig   *sku.GPUConfig
+		isGPUPlugin bool
 	}{
 		"Is not nvidia plugin": {
-			nodeObj:        &test.MockNodeList.Items[1],
-			isNvidiaPlugin: false,
+			nodeObj:     &test.MockNodeList.Items[1],
+			gpuConfig:   nil,
+			isGPUPlugin: false,
 		},
 		"Is nvidia plugin": {
-			nodeObj:        &test.MockNodeList.Items[0],
-			isNvidiaPlugin: true,
+			nodeObj:     &test.MockNodeList.Items[0],
+			gpuConfig:   nil,
+			isGPUPlugin: true,
+		},
+		"Is amd plugin": {
+			nodeObj:     amdNode,
+			gpuConfig:   &sku.GPUConfig{GPUVendor: sku.GPUVendorAMD},
+			isGPUPlugin: true,
+		},
+		"Is nvidia plugin with MIG resources": {
+			nodeObj:     migNode,
+			gpuConfig:   (*sku.GPUConfig)(nil).WithPartition("1g.10gb", 0),
+			isGPUPlugin: true,
+		},
+		"Nvidia plugin without the MIG profile": {
+			nodeObj:     migNode,
+			gpuConfig:   (*sku.GPUConfig)(nil).WithPartition("3g.40gb", 0),
+			isGPUPlugin: false,
+		},
+		"Nvidia plugin is not amd plugin": {
+			nodeObj:     &test.MockNodeList.Items[0],
+			gpuConfig:   &sku.GPUConfig{GPUVendor: sku.GPUVendorAMD},
+			isGPUPlugin: false,
 		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
-			result := CheckNvidiaPlugin(context.Background(), tc.nodeObj)
+			result := CheckGPUPlugin(context.Background(), tc.nodeObj, tc.gpuConfig)
 
-			assert.Equal(t, result, tc.isNvidiaPlugin)
+			assert.Equal(t, result, tc.isGPUPlugin)
 		})
 	}
 }
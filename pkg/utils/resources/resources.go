@@ -21,6 +21,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -39,6 +40,8 @@ func CreateResource(ctx context.Context, resource client.Object, kubeClient clie
 		klog.InfoS("CreateService", "service", klog.KObj(r))
 	case *corev1.ConfigMap:
 		klog.InfoS("CreateConfigMap", "configmap", klog.KObj(r))
+	case *resourcev1beta1.ResourceClaimTemplate:
+		klog.InfoS("CreateResourceClaimTemplate", "resourceclaimtemplate", klog.KObj(r))
 	}
 
 	// Create the resource.
@@ -59,6 +62,19 @@ func GetResource(ctx context.Context, name, namespace string, kubeClient client.
 	return err
 }
 
+// EnsureResource creates the resource unless an object with the same name already exists.
+func EnsureResource(ctx context.Context, resource client.Object, kubeClient client.Client) error {
+	existing := resource.DeepCopyObject().(client.Object)
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(resource), existing)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	return CreateResource(ctx, resource, kubeClient)
+}
+
 func CheckResourceStatus(obj client.Object, kubeClient client.Client, timeoutDuration time.Duration) error {
 	// Use Context for timeout
 	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
@@ -20,12 +20,16 @@ import (
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
 )
 
 // MockClient Client is a mock for the controller-runtime dynamic client interface.
@@ -122,6 +126,14 @@ func (m *MockClient) getObjectListFromMap(list k8sClient.ObjectList) k8sClient.O
 			}
 		}
 		return nodeClaimList
+	case *v1beta1.WorkspaceList:
+		workspaceList := &v1beta1.WorkspaceList{}
+		for _, obj := range relevantMap {
+			if w, ok := obj.(*v1beta1.Workspace); ok {
+				workspaceList.Items = append(workspaceList.Items, *w)
+			}
+		}
+		return workspaceList
 	case *appsv1.ControllerRevisionList:
 		controllerRevisionList := &appsv1.ControllerRevisionList{}
 		for _, obj := range relevantMap {
@@ -130,6 +142,23 @@ func (m *MockClient) getObjectListFromMap(list k8sClient.ObjectList) k8sClient.O
 			}
 		}
 		return controllerRevisionList
+	case *resourcev1beta1.ResourceSliceList:
+		sliceList := &resourcev1beta1.ResourceSliceList{}
+		for _, obj := range relevantMap {
+			if s, ok := obj.(*resourcev1beta1.ResourceSlice); ok {
+				sliceList.Items = append(sliceList.Items, *s)
+			}
+		}
+		return sliceList
+	case *unstructured.UnstructuredList:
+		unstructuredList := &unstructured.UnstructuredList{}
+		unstructuredList.SetGroupVersionKind(list.GetObjectKind().GroupVersionKind())
+		for _, obj := range relevantMap {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				unstructuredList.Items = append(unstructuredList.Items, *u)
+			}
+		}
+		return unstructuredList
 	}
 	//add additional object lists as needed
 	return nil
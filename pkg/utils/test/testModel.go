@@ -44,6 +44,22 @@ func (*baseTestModel) GetInferenceParameters() *model.PresetParam {
 				InferenceMainFile: "/workspace/tfs/inference_api.py",
 				AccelerateParams:  emptyParams,
 			},
+			SGLang: model.SGLangParam{
+				BaseCommand: "python3 /workspace/sglang/inference_api.py",
+				ModelName:   "mymodel",
+			},
+			TensorRTLLM: model.TensorRTLLMParam{
+				BaseCommand:        "trtllm-serve --host 0.0.0.0 --port 5000",
+				EngineBuildCommand: "python3 /workspace/tensorrt-llm/build_engine.py",
+			},
+			LlamaCpp: model.LlamaCppParam{
+				BaseCommand:  "/app/llama-server --host 0.0.0.0 --port 5000",
+				ModelName:    "mymodel",
+				GGUFRepo:     "test/test-model-GGUF",
+				GGUFFile:     "test-model-Q4_K_M.gguf",
+				GGUFRevision: "test-revision",
+				GGUFFileSize: "4.5Gi",
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
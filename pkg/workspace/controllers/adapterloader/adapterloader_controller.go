@@ -0,0 +1,224 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapterloader
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/inferenceproxy"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// DefaultInterval is the default interval at which the adapters loaded by the inference servers are checked.
+const DefaultInterval = 30 * time.Second
+
+// adapterAPI reports the LoRA adapters loaded by the inference server of a pod.
+type adapterAPI interface {
+	// ListAdapters returns the names of the adapters loaded by the inference server.
+	ListAdapters(ctx context.Context, pod *corev1.Pod) ([]string, error)
+}
+
+// AdapterLoaderReconciler reports the adapters loaded by the running inference servers of a workspace.
+//
+// The adapter puller sidecar of each pod pulls the adapters listed for the pod in the adapter ConfigMap of the
+// workspace, and the inference proxy sidecar loads them into the inference server and unloads the removed ones,
+// so that changing the adapters does not restart the inference pods. The runtime LoRA endpoints of the inference
+// server are only reachable from the pod, the proxy serves the loaded adapters read-only on its system port. An
+// adapter is loaded once it is pulled, so the reconciler checks the pods every interval.
+type AdapterLoaderReconciler struct {
+	client.Client
+	Interval time.Duration
+
+	api adapterAPI
+}
+
+func NewAdapterLoaderReconciler(client client.Client, interval time.Duration) *AdapterLoaderReconciler {
+	return &AdapterLoaderReconciler{
+		Client:   client,
+		Interval: interval,
+		api:      &proxyAdapterAPI{client: http.DefaultClient, baseURL: podURL},
+	}
+}
+
+func (c *AdapterLoaderReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	workspaceObj := &kaitov1beta1.Workspace{}
+	if err := c.Client.Get(ctx, req.NamespacedName, workspaceObj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if workspaceObj.Inference == nil || workspaceObj.Inference.Preset == nil || len(workspaceObj.Inference.Adapters) == 0 ||
+		kaitov1beta1.GetWorkspaceRuntimeName(workspaceObj) != pkgmodel.RuntimeNameVLLM {
+		return reconcile.Result{}, c.updateStatusAdapterPlacementIfNotMatch(ctx, workspaceObj, nil)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Namespace: workspaceObj.Namespace, Name: inference.AdapterConfigMapName(workspaceObj)}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The workspace controller has not created the ConfigMap yet, or the adapters are pulled before
+			// the inference server starts, e.g. with multi-node inference. Check again later.
+			return reconcile.Result{RequeueAfter: c.Interval}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(workspaceObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// With the Spread placement, the adapters actually loaded by each ready replica are reported in the status,
+	// so that the router only sends the requests for an adapter to the replicas that can serve them.
+	var placement []kaitov1beta1.ReplicaAdapters
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !podReady(pod) || !lo.ContainsBy(pod.Spec.Containers, func(container corev1.Container) bool {
+			return container.Name == image.AdapterPullerContainerName
+		}) {
+			continue
+		}
+		loaded, err := c.loadedAdapters(ctx, pod, inference.ListedAdapters(configMap, pod))
+		if err != nil {
+			klog.ErrorS(err, "failed to list the adapters of the inference server", "pod", klog.KObj(pod))
+			continue
+		}
+		placement = append(placement, kaitov1beta1.ReplicaAdapters{Pod: pod.Name, Adapters: loaded})
+	}
+	if !workspaceObj.Inference.AdapterPlacement.IsSpread() {
+		placement = nil
+	}
+	slices.SortFunc(placement, func(a, b kaitov1beta1.ReplicaAdapters) int { return cmp.Compare(a.Pod, b.Pod) })
+	if err := c.updateStatusAdapterPlacementIfNotMatch(ctx, workspaceObj, placement); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: c.Interval}, nil
+}
+
+// loadedAdapters returns the listed adapters loaded by the inference server of the pod. The adapters removed
+// from the list are left out while the proxy unloads them.
+func (c *AdapterLoaderReconciler) loadedAdapters(ctx context.Context, pod *corev1.Pod, listed []string) ([]string, error) {
+	loaded, err := c.api.ListAdapters(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	loadedSet := sets.New(loaded...).Intersection(sets.New(listed...))
+	if loadedSet.Len() == 0 {
+		return nil, nil
+	}
+	return sets.List(loadedSet), nil
+}
+
+func (c *AdapterLoaderReconciler) updateStatusAdapterPlacementIfNotMatch(ctx context.Context, wObj *kaitov1beta1.Workspace, placement []kaitov1beta1.ReplicaAdapters) error {
+	if reflect.DeepEqual(wObj.Status.AdapterPlacement, placement) {
+		return nil
+	}
+	klog.InfoS("updateStatusAdapterPlacement", "workspace", klog.KObj(wObj))
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Read the latest version to avoid update conflict.
+		latest := &kaitov1beta1.Workspace{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Status.AdapterPlacement = placement
+		return c.Client.Status().Update(ctx, latest)
+	})
+	if err != nil {
+		return err
+	}
+	wObj.Status.AdapterPlacement = placement
+	return nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.PodIP == "" || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	return lo.ContainsBy(pod.Status.Conditions, func(condition corev1.PodCondition) bool {
+		return condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue
+	})
+}
+
+// podURL returns the URL of the system port of the inference proxy of the pod, which serves the adapters loaded
+// by the inference server.
+func podURL(pod *corev1.Pod) string {
+	return "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(manifests.PortInferenceProxyMetrics))
+}
+
+// proxyAdapterAPI reads the adapters loaded by vLLM from the inference proxy, which loads them through the runtime
+// LoRA endpoints enabled by VLLM_ALLOW_RUNTIME_LORA_UPDATING.
+type proxyAdapterAPI struct {
+	client  *http.Client
+	baseURL func(pod *corev1.Pod) string
+}
+
+func (a *proxyAdapterAPI) ListAdapters(ctx context.Context, pod *corev1.Pod) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL(pod)+inferenceproxy.AdaptersPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, lo.Substring(string(body), 0, 1024))
+	}
+	var adapters struct {
+		Adapters []string `json:"adapters"`
+	}
+	if err := json.Unmarshal(body, &adapters); err != nil {
+		return nil, err
+	}
+	return adapters.Adapters, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *AdapterLoaderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("adapterloader").
+		For(&kaitov1beta1.Workspace{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(c)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapterloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+)
+
+// fakeAdapterAPI returns the adapters loaded by the inference server of each pod.
+type fakeAdapterAPI struct {
+	loaded map[string]sets.Set[string]
+}
+
+func (a *fakeAdapterAPI) ListAdapters(_ context.Context, pod *corev1.Pod) ([]string, error) {
+	return sets.List(a.loaded[pod.Name]), nil
+}
+
+func newPod(name, index string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				kaitov1beta1.LabelWorkspaceName: "workspace-a",
+				appsv1.PodIndexLabel:            index,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "workspace-a"}, {Name: image.AdapterPullerContainerName}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestAdapterLoaderReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "workspace-a",
+			Namespace:   "default",
+			Annotations: map[string]string{kaitov1beta1.AnnotationWorkspaceRuntime: "vllm"},
+		},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+			Adapters: []kaitov1beta1.AdapterSpec{
+				{Source: &kaitov1beta1.DataSource{Name: "adapter-a", Image: "example.com/adapter-a"}},
+				{Source: &kaitov1beta1.DataSource{Name: "adapter-b", Image: "example.com/adapter-b"}},
+			},
+			AdapterPlacement: &kaitov1beta1.AdapterPlacementSpec{Policy: kaitov1beta1.AdapterPlacementPolicySpread},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-a-adapters", Namespace: "default"},
+		Data: map[string]string{
+			"replica-0": "adapter-a example.com/adapter-a:latest\n",
+			"replica-1": "adapter-b example.com/adapter-b:latest\n",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(workspace, configMap, newPod("workspace-a-0", "0", true), newPod("workspace-a-1", "1", true), newPod("workspace-a-2", "2", false)).
+		WithStatusSubresource(workspace).
+		Build()
+	statusPlacement := func() []kaitov1beta1.ReplicaAdapters {
+		latest := &kaitov1beta1.Workspace{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(workspace), latest))
+		return latest.Status.AdapterPlacement
+	}
+
+	api := &fakeAdapterAPI{
+		loaded: map[string]sets.Set[string]{
+			"workspace-a-0": sets.New("adapter-a", "adapter-old"),
+			"workspace-a-1": sets.New[string](),
+			"workspace-a-2": sets.New("adapter-b"),
+		},
+	}
+	reconciler := &AdapterLoaderReconciler{Client: c, Interval: time.Minute, api: api}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(workspace)}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	// The status reports the listed adapters the ready replicas actually loaded, the adapter being unloaded and
+	// the pods that are not ready are left out.
+	assert.Equal(t, []kaitov1beta1.ReplicaAdapters{
+		{Pod: "workspace-a-0", Adapters: []string{"adapter-a"}},
+		{Pod: "workspace-a-1"},
+	}, statusPlacement())
+
+	// The adapter is loaded once it is pulled.
+	api.loaded["workspace-a-1"].Insert("adapter-b")
+	_, err = reconciler.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []kaitov1beta1.ReplicaAdapters{
+		{Pod: "workspace-a-0", Adapters: []string{"adapter-a"}},
+		{Pod: "workspace-a-1", Adapters: []string{"adapter-b"}},
+	}, statusPlacement())
+
+	// Workspaces without adapters are not checked again.
+	assert.NoError(t, c.Get(context.Background(), req.NamespacedName, workspace))
+	workspace.Inference.Adapters = nil
+	assert.NoError(t, c.Update(context.Background(), workspace))
+	result, err = reconciler.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, statusPlacement())
+}
+
+func TestAdapterLoaderReconcileMissingConfigMap(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "workspace-a",
+			Namespace:   "default",
+			Annotations: map[string]string{kaitov1beta1.AnnotationWorkspaceRuntime: "vllm"},
+		},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+			Adapters: []kaitov1beta1.AdapterSpec{
+				{Source: &kaitov1beta1.DataSource{Name: "adapter-a", Image: "example.com/adapter-a"}},
+			},
+			AdapterPlacement: &kaitov1beta1.AdapterPlacementSpec{Policy: kaitov1beta1.AdapterPlacementPolicySpread},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(workspace, newPod("workspace-a-0", "0", true)).
+		WithStatusSubresource(workspace).
+		Build()
+	statusPlacement := func() []kaitov1beta1.ReplicaAdapters {
+		latest := &kaitov1beta1.Workspace{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(workspace), latest))
+		return latest.Status.AdapterPlacement
+	}
+
+	api := &fakeAdapterAPI{
+		loaded: map[string]sets.Set[string]{"workspace-a-0": sets.New("adapter-a")},
+	}
+	reconciler := &AdapterLoaderReconciler{Client: c, Interval: time.Minute, api: api}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(workspace)}
+
+	// The workspace is checked again until the ConfigMap is created.
+	result, err := reconciler.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Empty(t, statusPlacement())
+
+	assert.NoError(t, c.Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-a-adapters", Namespace: "default"},
+		Data:       map[string]string{"adapters": "adapter-a example.com/adapter-a:latest\n"},
+	}))
+	result, err = reconciler.Reconcile(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	assert.Equal(t, []kaitov1beta1.ReplicaAdapters{{Pod: "workspace-a-0", Adapters: []string{"adapter-a"}}}, statusPlacement())
+}
+
+func TestProxyAdapterAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/adapters":
+			_, _ = w.Write([]byte(`{"adapters":["adapter-a"]}`))
+		default:
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	api := &proxyAdapterAPI{client: server.Client(), baseURL: func(*corev1.Pod) string { return server.URL }}
+	adapters, err := api.ListAdapters(context.Background(), &corev1.Pod{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"adapter-a"}, adapters)
+
+	api.baseURL = func(*corev1.Pod) string { return server.URL + "/missing" }
+	_, err = api.ListAdapters(context.Background(), &corev1.Pod{})
+	assert.ErrorContains(t, err, "upstream unavailable")
+}
@@ -0,0 +1,251 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package garbagecollect
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+)
+
+const (
+	// DefaultNodeClaimGCGracePeriod is the default time a nodeClaim must stay orphaned before it is deleted.
+	DefaultNodeClaimGCGracePeriod = 10 * time.Minute
+
+	// DefaultNodeClaimGCInterval is the default interval at which the owners of kaito nodeClaims are re-checked.
+	DefaultNodeClaimGCInterval = 10 * time.Minute
+)
+
+var (
+	orphanedNodeClaimCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kaito_orphaned_nodeclaim_count",
+			Help: "Number of kaito nodeClaims whose owner no longer exists",
+		},
+		[]string{"kind"},
+	)
+	orphanedNodeClaimDeletedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kaito_orphaned_nodeclaim_deleted_total",
+			Help: "Number of orphaned kaito nodeClaims deleted by the garbage collector, dry_run deletions are only reported once per nodeClaim",
+		},
+		[]string{"kind", "dry_run"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedNodeClaimCount, orphanedNodeClaimDeletedTotal)
+}
+
+// NodeClaimGCReconciler deletes the nodeClaims created for workspaces or RAGEngines that no longer exist,
+// e.g. because the owner was force deleted with its finalizer removed or was deleted while the controller
+// was down. Only owners whose kind is registered in the client scheme are checked, so each controller
+// manager collects the nodeClaims of the resources it manages.
+type NodeClaimGCReconciler struct {
+	client.Client
+	Recorder    record.EventRecorder
+	GracePeriod time.Duration
+	Interval    time.Duration
+	DryRun      bool
+	clock       clock.Clock
+
+	// orphanedSince records when each nodeClaim was first found without an owner, keyed by nodeClaim name.
+	mu            sync.Mutex
+	orphanedSince map[string]orphanedNodeClaim
+}
+
+type orphanedNodeClaim struct {
+	kind  string
+	since time.Time
+	// reported is the UID of the nodeClaim once its deletion was reported in dry run mode, so that each
+	// nodeClaim is only counted once while it is re-checked.
+	reported types.UID
+}
+
+func NewNodeClaimGCReconciler(client client.Client, Recorder record.EventRecorder, gracePeriod, interval time.Duration, dryRun bool) *NodeClaimGCReconciler {
+	return &NodeClaimGCReconciler{
+		Client:        client,
+		Recorder:      Recorder,
+		GracePeriod:   gracePeriod,
+		Interval:      interval,
+		DryRun:        dryRun,
+		clock:         clock.RealClock{},
+		orphanedSince: map[string]orphanedNodeClaim{},
+	}
+}
+
+func (c *NodeClaimGCReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	nodeClaimObj := &karpenterv1.NodeClaim{}
+	if err := c.Client.Get(ctx, req.NamespacedName, nodeClaimObj); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			c.forgetOrphaned(req.Name)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if !nodeClaimObj.DeletionTimestamp.IsZero() {
+		c.forgetOrphaned(nodeClaimObj.Name)
+		return reconcile.Result{}, nil
+	}
+
+	owner, ownerKeys := nodeClaimOwners(nodeClaimObj)
+	if owner == nil {
+		return reconcile.Result{}, nil
+	}
+	gvk, err := apiutil.GVKForObject(owner, c.Client.Scheme())
+	if err != nil {
+		// The owner kind is managed by another controller manager.
+		return reconcile.Result{}, nil
+	}
+
+	for _, ownerKey := range ownerKeys {
+		if err := c.Client.Get(ctx, ownerKey, owner); err == nil {
+			c.forgetOrphaned(nodeClaimObj.Name)
+			return reconcile.Result{RequeueAfter: c.Interval}, nil
+		} else if client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "failed to get nodeClaim owner", "nodeClaim", klog.KObj(nodeClaimObj), "kind", gvk.Kind, "owner", ownerKey)
+			return reconcile.Result{}, err
+		}
+	}
+
+	now := c.clock.Now()
+	since := c.markOrphaned(nodeClaimObj.Name, gvk.Kind, now)
+	if remaining := since.Add(c.GracePeriod).Sub(now); remaining > 0 {
+		klog.InfoS("found orphaned nodeClaim", "nodeClaim", klog.KObj(nodeClaimObj), "kind", gvk.Kind, "owners", ownerKeys, "gracePeriod", c.GracePeriod)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	message := fmt.Sprintf("%s %v no longer exists", gvk.Kind, ownerKeys)
+	if c.DryRun {
+		klog.InfoS("dry run, skip deleting orphaned nodeClaim", "nodeClaim", klog.KObj(nodeClaimObj), "reason", message)
+		if c.markReported(nodeClaimObj) {
+			if c.Recorder != nil {
+				c.Recorder.Event(nodeClaimObj, corev1.EventTypeWarning, "OrphanedNodeClaim", message)
+			}
+			orphanedNodeClaimDeletedTotal.WithLabelValues(gvk.Kind, strconv.FormatBool(true)).Inc()
+		}
+		return reconcile.Result{RequeueAfter: c.Interval}, nil
+	}
+
+	klog.InfoS("deleting orphaned nodeClaim", "nodeClaim", klog.KObj(nodeClaimObj), "reason", message)
+	if err := c.Client.Delete(ctx, nodeClaimObj); client.IgnoreNotFound(err) != nil {
+		klog.ErrorS(err, "failed to delete orphaned nodeClaim", "nodeClaim", klog.KObj(nodeClaimObj))
+		return reconcile.Result{}, err
+	}
+	if c.Recorder != nil {
+		c.Recorder.Event(nodeClaimObj, corev1.EventTypeNormal, "OrphanedNodeClaimDeleted", message)
+	}
+	orphanedNodeClaimDeletedTotal.WithLabelValues(gvk.Kind, strconv.FormatBool(false)).Inc()
+	c.forgetOrphaned(nodeClaimObj.Name)
+	return reconcile.Result{}, nil
+}
+
+// nodeClaimOwners returns an empty object of the owner kind and the owner keys based on the nodeClaim labels.
+// Shared nodeClaims are owned by all workspaces placed on them. A nil object is returned if the nodeClaim
+// was not created by kaito.
+func nodeClaimOwners(nodeClaimObj *karpenterv1.NodeClaim) (client.Object, []types.NamespacedName) {
+	labels := nodeClaimObj.GetLabels()
+	if labels[kaitov1beta1.LabelSharedNode] == "true" {
+		return &kaitov1beta1.Workspace{}, lo.Map(nodeclaim.SharedWorkspaces(nodeClaimObj), func(key string, _ int) types.NamespacedName {
+			namespace, name, _ := strings.Cut(key, "/")
+			return types.NamespacedName{Name: name, Namespace: namespace}
+		})
+	}
+	if name, namespace := labels[kaitov1beta1.LabelWorkspaceName], labels[kaitov1beta1.LabelWorkspaceNamespace]; name != "" && namespace != "" {
+		return &kaitov1beta1.Workspace{}, []types.NamespacedName{{Name: name, Namespace: namespace}}
+	}
+	if name, namespace := labels[kaitov1alpha1.LabelRAGEngineName], labels[kaitov1alpha1.LabelRAGEngineNamespace]; name != "" && namespace != "" {
+		return &kaitov1alpha1.RAGEngine{}, []types.NamespacedName{{Name: name, Namespace: namespace}}
+	}
+	return nil, nil
+}
+
+// markOrphaned records the nodeClaim as orphaned and returns the time it was first found orphaned.
+func (c *NodeClaimGCReconciler) markOrphaned(name, kind string, now time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	orphaned, found := c.orphanedSince[name]
+	if !found {
+		orphaned = orphanedNodeClaim{kind: kind, since: now}
+		c.orphanedSince[name] = orphaned
+		orphanedNodeClaimCount.WithLabelValues(kind).Inc()
+	}
+	return orphaned.since
+}
+
+// markReported records that the deletion of the orphaned nodeClaim was reported in dry run mode and returns
+// false if it already was.
+func (c *NodeClaimGCReconciler) markReported(nodeClaimObj *karpenterv1.NodeClaim) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	orphaned, found := c.orphanedSince[nodeClaimObj.Name]
+	if !found || orphaned.reported == nodeClaimObj.UID {
+		return false
+	}
+	orphaned.reported = nodeClaimObj.UID
+	c.orphanedSince[nodeClaimObj.Name] = orphaned
+	return true
+}
+
+func (c *NodeClaimGCReconciler) forgetOrphaned(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	orphaned, found := c.orphanedSince[name]
+	if !found {
+		return
+	}
+	delete(c.orphanedSince, name)
+	orphanedNodeClaimCount.WithLabelValues(orphaned.kind).Dec()
+}
+
+func kaitoNodeClaimFilter() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		nodeClaimObj, ok := obj.(*karpenterv1.NodeClaim)
+		if !ok {
+			return false
+		}
+		owner, _ := nodeClaimOwners(nodeClaimObj)
+		return owner != nil
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *NodeClaimGCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodeclaimgc").
+		For(&karpenterv1.NodeClaim{}, builder.WithPredicates(kaitoNodeClaimFilter())).
+		Complete(c)
+}
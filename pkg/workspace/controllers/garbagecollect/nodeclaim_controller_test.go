@@ -0,0 +1,178 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package garbagecollect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+func newNodeClaim(labels, annotations map[string]string) *karpenterv1.NodeClaim {
+	return &karpenterv1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nodeclaim-1",
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestNodeClaimGCReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+	_ = utils.KarpenterSchemeBuilder.AddToScheme(s)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	gracePeriod := 10 * time.Minute
+	interval := time.Hour
+
+	workspaceLabels := map[string]string{
+		kaitov1beta1.LabelWorkspaceName:      "ws",
+		kaitov1beta1.LabelWorkspaceNamespace: "default",
+	}
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+	}
+
+	tests := []struct {
+		name                string
+		objects             []client.Object
+		dryRun              bool
+		orphanedSince       *time.Time
+		expectedRequeueTime time.Duration
+		deleted             bool
+	}{
+		{
+			name:                "NodeClaim with an existing workspace is re-checked periodically",
+			objects:             []client.Object{workspace, newNodeClaim(workspaceLabels, nil)},
+			expectedRequeueTime: interval,
+		},
+		{
+			name: "Shared nodeClaim with a remaining workspace is re-checked periodically",
+			objects: []client.Object{workspace, newNodeClaim(map[string]string{kaitov1beta1.LabelSharedNode: "true"}, map[string]string{
+				kaitov1beta1.AnnotationSharedWorkspaces: "default/gone,default/ws",
+			})},
+			expectedRequeueTime: interval,
+		},
+		{
+			name: "Shared nodeClaim without remaining workspaces is deleted",
+			objects: []client.Object{newNodeClaim(map[string]string{kaitov1beta1.LabelSharedNode: "true"}, map[string]string{
+				kaitov1beta1.AnnotationSharedWorkspaces: "default/gone",
+			})},
+			orphanedSince: ptr.To(now.Add(-gracePeriod)),
+			deleted:       true,
+		},
+		{
+			name:    "NodeClaim not created by kaito is ignored",
+			objects: []client.Object{newNodeClaim(nil, nil)},
+		},
+		{
+			name: "NodeClaim of a RAGEngine is ignored when the kind is not registered",
+			objects: []client.Object{newNodeClaim(map[string]string{
+				kaitov1alpha1.LabelRAGEngineName:      "rag",
+				kaitov1alpha1.LabelRAGEngineNamespace: "default",
+			}, nil)},
+		},
+		{
+			name:                "Orphaned nodeClaim is requeued for the grace period",
+			objects:             []client.Object{newNodeClaim(workspaceLabels, nil)},
+			expectedRequeueTime: gracePeriod,
+		},
+		{
+			name:          "Orphaned nodeClaim past the grace period is deleted",
+			objects:       []client.Object{newNodeClaim(workspaceLabels, nil)},
+			orphanedSince: ptr.To(now.Add(-gracePeriod)),
+			deleted:       true,
+		},
+		{
+			name:                "Orphaned nodeClaim is kept in dry run mode",
+			objects:             []client.Object{newNodeClaim(workspaceLabels, nil)},
+			dryRun:              true,
+			orphanedSince:       ptr.To(now.Add(-gracePeriod)),
+			expectedRequeueTime: interval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(tt.objects...).Build()
+			reconciler := NewNodeClaimGCReconciler(fakeClient, record.NewFakeRecorder(10), gracePeriod, interval, tt.dryRun)
+			reconciler.clock = clocktesting.NewFakeClock(now)
+			if tt.orphanedSince != nil {
+				reconciler.markOrphaned("nodeclaim-1", "Workspace", *tt.orphanedSince)
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "nodeclaim-1"}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRequeueTime, result.RequeueAfter)
+
+			err = fakeClient.Get(context.Background(), client.ObjectKey{Name: "nodeclaim-1"}, &karpenterv1.NodeClaim{})
+			if tt.deleted {
+				assert.True(t, errors.IsNotFound(err))
+				assert.Empty(t, reconciler.orphanedSince)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNodeClaimGCReconcileDryRunCountsOnce(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+	_ = utils.KarpenterSchemeBuilder.AddToScheme(s)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodeClaim := newNodeClaim(map[string]string{
+		kaitov1beta1.LabelWorkspaceName:      "ws-dry-run",
+		kaitov1beta1.LabelWorkspaceNamespace: "default",
+	}, nil)
+	nodeClaim.UID = "nodeclaim-uid"
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(nodeClaim).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewNodeClaimGCReconciler(fakeClient, recorder, time.Minute, time.Hour, true)
+	reconciler.clock = clocktesting.NewFakeClock(now)
+	reconciler.markOrphaned(nodeClaim.Name, "Workspace", now.Add(-time.Hour))
+
+	counter := orphanedNodeClaimDeletedTotal.WithLabelValues("Workspace", "true")
+	before := testutil.ToFloat64(counter)
+	for range 3 {
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: nodeClaim.Name}})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	assert.Len(t, recorder.Events, 1)
+}
@@ -0,0 +1,286 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+const (
+	// AnnotationUnhealthySince records when the node was first found unhealthy. The node is
+	// replaced once it stays unhealthy for longer than the grace period.
+	AnnotationUnhealthySince = kaitov1beta1.KAITOPrefix + "unhealthy-since"
+
+	// DefaultGracePeriod is the default time a worker node can stay unhealthy before it is replaced.
+	DefaultGracePeriod = 5 * time.Minute
+)
+
+// NodeHealthReconciler replaces the worker nodes of workspaces that become NotReady, report GPU Xid errors
+// or lose their GPU capacity. Unhealthy nodes are cordoned, removed from the workspace status and their
+// nodeClaims are deleted, so the workspace controller provisions replacement nodes.
+type NodeHealthReconciler struct {
+	client.Client
+	Recorder    record.EventRecorder
+	GracePeriod time.Duration
+	clock       clock.Clock
+}
+
+func NewNodeHealthReconciler(client client.Client, Recorder record.EventRecorder, gracePeriod time.Duration) *NodeHealthReconciler {
+	return &NodeHealthReconciler{
+		Client:      client,
+		Recorder:    Recorder,
+		GracePeriod: gracePeriod,
+		clock:       clock.RealClock{},
+	}
+}
+
+func (c *NodeHealthReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	workspaces, err := c.findWorkspacesUsingNode(ctx, req.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(workspaces) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	nodeObj := &corev1.Node{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: req.Name}, nodeObj); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, err
+		}
+		// The node is lost, there is nothing to wait for.
+		return reconcile.Result{}, c.replaceNode(ctx, workspaces, req.Name, "node no longer exists")
+	}
+
+	reason := unhealthyReason(nodeObj, workspaces)
+	unhealthySince, marked := nodeObj.Annotations[AnnotationUnhealthySince]
+	if reason == "" {
+		if marked {
+			klog.InfoS("node recovered", "node", klog.KObj(nodeObj))
+			return reconcile.Result{}, c.setUnhealthySince(ctx, nodeObj, "")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	now := c.clock.Now()
+	since, err := time.Parse(time.RFC3339, unhealthySince)
+	if !marked || err != nil {
+		klog.InfoS("node is unhealthy", "node", klog.KObj(nodeObj), "reason", reason, "gracePeriod", c.GracePeriod)
+		since = now
+		if err := c.setUnhealthySince(ctx, nodeObj, since.UTC().Format(time.RFC3339)); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if remaining := since.Add(c.GracePeriod).Sub(now); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err := c.cordonNode(ctx, nodeObj); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, c.replaceNode(ctx, workspaces, nodeObj.Name, reason)
+}
+
+// unhealthyReason returns why the node cannot run the workloads of the workspaces using it, or an empty string
+// if it is healthy.
+func unhealthyReason(nodeObj *corev1.Node, workspaces []*kaitov1beta1.Workspace) string {
+	readyCondition, found := lo.Find(nodeObj.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == corev1.NodeReady
+	})
+	if !found || readyCondition.Status != corev1.ConditionTrue {
+		return "node is not ready"
+	}
+
+	// GPU health checkers such as node problem detector report Xid errors as node conditions.
+	if xidCondition, found := lo.Find(nodeObj.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return strings.Contains(strings.ToLower(string(condition.Type)), "xid") && condition.Status == corev1.ConditionTrue
+	}); found {
+		return fmt.Sprintf("node reports GPU error %s: %s", xidCondition.Type, xidCondition.Message)
+	}
+
+	// A GPU node that lost its GPU capacity has a broken driver or device plugin. The GPUs of DRA workspaces are
+	// published in ResourceSlices instead, and the MIG instances of partitioned GPUs depend on the MIG strategy
+	// of the device plugin, so the capacity of the nodes serving them is not checked.
+	if lo.ContainsBy(workspaces, func(wObj *kaitov1beta1.Workspace) bool {
+		return wObj.UsesDynamicResourceAllocation() ||
+			(wObj.Resource.GPUPartition != nil && wObj.Resource.GPUPartition.MIGProfile != "")
+	}) {
+		return ""
+	}
+	vendor := resources.NodeGPUVendor(nodeObj)
+	if nodeObj.Labels[resources.LabelKeyNvidia] == resources.GPULabelValue(vendor) && !resources.HasVendorGPUCapacity(nodeObj, vendor) {
+		return fmt.Sprintf("node has no %s capacity", resources.GPUResourceName(vendor))
+	}
+	return ""
+}
+
+// findWorkspacesUsingNode returns the workspaces that have the node in their worker node list.
+func (c *NodeHealthReconciler) findWorkspacesUsingNode(ctx context.Context, nodeName string) ([]*kaitov1beta1.Workspace, error) {
+	workspaceList := &kaitov1beta1.WorkspaceList{}
+	if err := c.List(ctx, workspaceList); err != nil {
+		return nil, err
+	}
+	var workspaces []*kaitov1beta1.Workspace
+	for i := range workspaceList.Items {
+		wObj := &workspaceList.Items[i]
+		if wObj.DeletionTimestamp.IsZero() && lo.Contains(wObj.Status.WorkerNodes, nodeName) {
+			workspaces = append(workspaces, wObj)
+		}
+	}
+	return workspaces, nil
+}
+
+func (c *NodeHealthReconciler) setUnhealthySince(ctx context.Context, nodeObj *corev1.Node, since string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &corev1.Node{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(nodeObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if since == "" {
+			delete(latest.Annotations, AnnotationUnhealthySince)
+		} else {
+			if latest.Annotations == nil {
+				latest.Annotations = map[string]string{}
+			}
+			latest.Annotations[AnnotationUnhealthySince] = since
+		}
+		return c.Client.Update(ctx, latest)
+	})
+}
+
+func (c *NodeHealthReconciler) cordonNode(ctx context.Context, nodeObj *corev1.Node) error {
+	if nodeObj.Spec.Unschedulable {
+		return nil
+	}
+	klog.InfoS("cordoning unhealthy node", "node", klog.KObj(nodeObj))
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &corev1.Node{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(nodeObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Spec.Unschedulable = true
+		return c.Client.Update(ctx, latest)
+	})
+}
+
+// replaceNode deletes the nodeClaim that owns the node and removes the node from the worker node list of
+// the workspaces. The workspace controller then provisions a replacement node for each workspace.
+func (c *NodeHealthReconciler) replaceNode(ctx context.Context, workspaces []*kaitov1beta1.Workspace, nodeName, reason string) error {
+	for _, wObj := range workspaces {
+		if !kaitov1beta1.IsNodeAutoProvisioningDisabled(wObj) {
+			if err := c.deleteNodeClaimOfNode(ctx, wObj, nodeName); err != nil {
+				return err
+			}
+		}
+
+		message := fmt.Sprintf("worker node %s was replaced: %s", nodeName, reason)
+		klog.InfoS("replacing worker node", "workspace", klog.KObj(wObj), "node", nodeName, "reason", reason)
+		if err := c.removeWorkerNode(ctx, wObj, nodeName, message); err != nil {
+			return err
+		}
+		if c.Recorder != nil {
+			c.Recorder.Event(wObj, corev1.EventTypeWarning, "NodeReplaced", message)
+		}
+	}
+	return nil
+}
+
+func (c *NodeHealthReconciler) deleteNodeClaimOfNode(ctx context.Context, wObj *kaitov1beta1.Workspace, nodeName string) error {
+	var nodeClaims *karpenterv1.NodeClaimList
+	var err error
+	if wObj.IsSharedPlacement() {
+		nodeClaims, err = nodeclaim.ListSharedNodeClaims(ctx, c.Client)
+	} else {
+		nodeClaims, err = nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+	}
+	if err != nil {
+		return err
+	}
+	for i := range nodeClaims.Items {
+		if nodeClaims.Items[i].Status.NodeName != nodeName || !nodeClaims.Items[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		klog.InfoS("deleting nodeClaim of unhealthy node", "nodeClaim", klog.KObj(&nodeClaims.Items[i]), "node", nodeName)
+		if err := c.Delete(ctx, &nodeClaims.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete nodeClaim %s: %w", nodeClaims.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *NodeHealthReconciler) removeWorkerNode(ctx context.Context, wObj *kaitov1beta1.Workspace, nodeName, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Read the latest version to avoid update conflict.
+		latest := &kaitov1beta1.Workspace{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		latest.Status.WorkerNodes = lo.Without(latest.Status.WorkerNodes, nodeName)
+		meta.SetStatusCondition(&latest.Status.Conditions, metav1.Condition{
+			Type:               string(kaitov1beta1.ConditionTypeNodeReplaced),
+			Status:             metav1.ConditionTrue,
+			Reason:             "UnhealthyNodeReplaced",
+			ObservedGeneration: latest.GetGeneration(),
+			Message:            message,
+		})
+		return c.Client.Status().Update(ctx, latest)
+	})
+}
+
+// nodeHealthPredicate filters out node updates that do not change the health of the node, e.g. heartbeats.
+var nodeHealthPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		// The workspaces are checked by the reconciler, any change of the health of the node is relevant.
+		return unhealthyReason(oldNode, nil) != unhealthyReason(newNode, nil)
+	},
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *NodeHealthReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodehealth").
+		For(&corev1.Node{}, builder.WithPredicates(nodeHealthPredicate)).
+		Complete(c)
+}
@@ -0,0 +1,241 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+func newNode(name string, ready corev1.ConditionStatus, annotations map[string]string, extraConditions ...corev1.NodeCondition) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+			Labels: map[string]string{
+				resources.LabelKeyNvidia: resources.LabelValueNvidia,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				resources.CapacityNvidiaGPU: resource.MustParse("1"),
+			},
+			Conditions: append([]corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}, extraConditions...),
+		},
+	}
+}
+
+func TestUnhealthyReason(t *testing.T) {
+	noCapacityNode := newNode("node", corev1.ConditionTrue, nil)
+	noCapacityNode.Status.Capacity = corev1.ResourceList{}
+
+	migNode := newNode("node", corev1.ConditionTrue, nil)
+	migNode.Status.Capacity = corev1.ResourceList{
+		resources.CapacityNvidiaGPU:             resource.MustParse("0"),
+		resources.CapacityMIGPrefix + "1g.10gb": resource.MustParse("7"),
+	}
+	draWorkspace := &kaitov1beta1.Workspace{
+		Resource: kaitov1beta1.ResourceSpec{
+			DynamicResourceAllocation: &kaitov1beta1.DynamicResourceAllocation{DeviceClassName: "gpu.nvidia.com"},
+		},
+	}
+	migWorkspace := &kaitov1beta1.Workspace{
+		Resource: kaitov1beta1.ResourceSpec{
+			GPUPartition: &kaitov1beta1.GPUPartition{MIGProfile: "1g.10gb"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		node       *corev1.Node
+		workspaces []*kaitov1beta1.Workspace
+		expected   string
+	}{
+		{
+			name:     "Healthy node",
+			node:     newNode("node", corev1.ConditionTrue, nil),
+			expected: "",
+		},
+		{
+			name:     "NotReady node",
+			node:     newNode("node", corev1.ConditionFalse, nil),
+			expected: "node is not ready",
+		},
+		{
+			name: "Node with GPU Xid error",
+			node: newNode("node", corev1.ConditionTrue, nil, corev1.NodeCondition{
+				Type: "GPUXidError", Status: corev1.ConditionTrue, Message: "Xid 79",
+			}),
+			expected: "node reports GPU error GPUXidError: Xid 79",
+		},
+		{
+			name:     "Node lost GPU capacity",
+			node:     noCapacityNode,
+			expected: "node has no nvidia.com/gpu capacity",
+		},
+		{
+			name:     "Node with MIG capacity",
+			node:     migNode,
+			expected: "",
+		},
+		{
+			name:       "Node serving a DRA workspace",
+			node:       noCapacityNode,
+			workspaces: []*kaitov1beta1.Workspace{draWorkspace},
+			expected:   "",
+		},
+		{
+			name:       "Node serving a MIG workspace",
+			node:       noCapacityNode,
+			workspaces: []*kaitov1beta1.Workspace{migWorkspace},
+			expected:   "",
+		},
+		{
+			name:       "NotReady node serving a DRA workspace",
+			node:       newNode("node", corev1.ConditionFalse, nil),
+			workspaces: []*kaitov1beta1.Workspace{draWorkspace},
+			expected:   "node is not ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, unhealthyReason(tt.node, tt.workspaces))
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+	_ = utils.KarpenterSchemeBuilder.AddToScheme(s)
+
+	now := time.Date(2025, 1, 1, 0, 10, 0, 0, time.UTC)
+	gracePeriod := 5 * time.Minute
+
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Status:     kaitov1beta1.WorkspaceStatus{WorkerNodes: []string{"node-1", "node-2"}},
+	}
+	nodeClaim := &karpenterv1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nodeclaim-1",
+			Labels: map[string]string{
+				kaitov1beta1.LabelWorkspaceName:      "ws",
+				kaitov1beta1.LabelWorkspaceNamespace: "default",
+			},
+		},
+		Status: karpenterv1.NodeClaimStatus{NodeName: "node-1"},
+	}
+
+	tests := []struct {
+		name                string
+		objects             []client.Object
+		expectedRequeueTime time.Duration
+		expectedSince       string
+		replaced            bool
+	}{
+		{
+			name:    "Healthy node is left alone",
+			objects: []client.Object{newNode("node-1", corev1.ConditionTrue, nil)},
+		},
+		{
+			name:                "Unhealthy node is marked and requeued after the grace period",
+			objects:             []client.Object{newNode("node-1", corev1.ConditionFalse, nil)},
+			expectedRequeueTime: gracePeriod,
+			expectedSince:       now.Format(time.RFC3339),
+		},
+		{
+			name: "Unhealthy node within the grace period is requeued",
+			objects: []client.Object{newNode("node-1", corev1.ConditionFalse, map[string]string{
+				AnnotationUnhealthySince: now.Add(-2 * time.Minute).Format(time.RFC3339),
+			})},
+			expectedRequeueTime: 3 * time.Minute,
+			expectedSince:       now.Add(-2 * time.Minute).Format(time.RFC3339),
+		},
+		{
+			name: "Recovered node is unmarked",
+			objects: []client.Object{newNode("node-1", corev1.ConditionTrue, map[string]string{
+				AnnotationUnhealthySince: now.Add(-2 * time.Minute).Format(time.RFC3339),
+			})},
+		},
+		{
+			name: "Unhealthy node past the grace period is replaced",
+			objects: []client.Object{newNode("node-1", corev1.ConditionFalse, map[string]string{
+				AnnotationUnhealthySince: now.Add(-6 * time.Minute).Format(time.RFC3339),
+			})},
+			expectedSince: now.Add(-6 * time.Minute).Format(time.RFC3339),
+			replaced:      true,
+		},
+		{
+			name:     "Lost node is replaced",
+			objects:  []client.Object{},
+			replaced: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append([]client.Object{workspace.DeepCopy(), nodeClaim.DeepCopy()}, tt.objects...)
+			fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).
+				WithStatusSubresource(&kaitov1beta1.Workspace{}).Build()
+			reconciler := NewNodeHealthReconciler(fakeClient, record.NewFakeRecorder(10), gracePeriod)
+			reconciler.clock = clocktesting.NewFakeClock(now)
+
+			result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "node-1"}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRequeueTime, result.RequeueAfter)
+
+			node := &corev1.Node{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKey{Name: "node-1"}, node); err == nil {
+				assert.Equal(t, tt.expectedSince, node.Annotations[AnnotationUnhealthySince])
+				assert.Equal(t, tt.replaced, node.Spec.Unschedulable)
+			}
+
+			ws := &kaitov1beta1.Workspace{}
+			assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), ws))
+			err = fakeClient.Get(context.Background(), client.ObjectKeyFromObject(nodeClaim), &karpenterv1.NodeClaim{})
+			if tt.replaced {
+				assert.Equal(t, []string{"node-2"}, ws.Status.WorkerNodes)
+				assert.True(t, meta.IsStatusConditionTrue(ws.Status.Conditions, string(kaitov1beta1.ConditionTypeNodeReplaced)))
+				assert.True(t, errors.IsNotFound(err))
+			} else {
+				assert.Equal(t, []string{"node-1", "node-2"}, ws.Status.WorkerNodes)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
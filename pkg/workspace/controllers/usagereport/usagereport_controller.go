@@ -0,0 +1,321 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usagereport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/inferenceproxy"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+const (
+	// DefaultInterval is the default interval at which the token usage of a workspace is collected.
+	DefaultInterval = 5 * time.Minute
+
+	// DefaultPeriod is the default period covered by a UsageReport.
+	DefaultPeriod = 24 * time.Hour
+)
+
+// series identifies the token usage of a client of a model served by a workspace.
+type series struct {
+	workspace, model, adapter, client string
+}
+
+type tokens struct {
+	prompt, completion int64
+}
+
+// podCounters are the token counters last collected from the inference proxy of a pod.
+type podCounters struct {
+	workspace types.NamespacedName
+	counters  map[series]tokens
+}
+
+// UsageReportReconciler collects the token usage metered by the inference proxy of the pods of each
+// workspace and adds it to the UsageReport of the namespace covering the current period.
+//
+// The counters of the proxies are cumulative, so the reconciler records the counters it last collected
+// from each pod and reports the increase. Pods created before the reconciler started are only counted
+// from their first collection, since their earlier usage may already have been reported.
+//
+// The reports are therefore a lower bound of the usage: the tokens served by a pod since its last
+// collection are lost when the pod is deleted, and the tokens served by the pods of a workspace between
+// the last collection before a restart of the reconciler and the first one after it are never reported.
+type UsageReportReconciler struct {
+	client.Client
+	Interval time.Duration
+	Period   time.Duration
+	clock    clock.Clock
+	started  time.Time
+
+	// scrape returns the metrics of the inference proxy of a pod.
+	scrape func(ctx context.Context, pod *corev1.Pod) (map[string]*dto.MetricFamily, error)
+
+	mu   sync.Mutex
+	pods map[types.UID]podCounters
+}
+
+func NewUsageReportReconciler(client client.Client, interval, period time.Duration) *UsageReportReconciler {
+	c := clock.RealClock{}
+	return &UsageReportReconciler{
+		Client:   client,
+		Interval: interval,
+		Period:   period,
+		clock:    c,
+		started:  c.Now(),
+		scrape:   scrapeInferenceProxy,
+		pods:     map[types.UID]podCounters{},
+	}
+}
+
+func (c *UsageReportReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	workspaceObj := &kaitov1beta1.Workspace{}
+	if err := c.Client.Get(ctx, req.NamespacedName, workspaceObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.forgetPods(req.NamespacedName, nil)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if !manifests.InferenceProxyEnabled(workspaceObj) {
+		c.forgetPods(req.NamespacedName, nil)
+		return reconcile.Result{}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(workspaceObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: workspaceObj.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	collected := map[types.UID]podCounters{}
+	increase := map[series]tokens{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.PodIP == "" || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		families, err := c.scrape(ctx, pod)
+		if err != nil {
+			klog.ErrorS(err, "failed to collect token usage", "pod", klog.KObj(pod))
+			continue
+		}
+		counters := parseTokenCounters(families)
+		collected[pod.UID] = podCounters{workspace: req.NamespacedName, counters: counters}
+		for s, current := range counters {
+			last, found := c.lastCounters(pod, s)
+			if !found {
+				continue
+			}
+			increase[s] = addTokens(increase[s], counterIncrease(last, current))
+		}
+	}
+
+	if err := c.addUsage(ctx, workspaceObj.Namespace, increase); err != nil {
+		klog.ErrorS(err, "failed to update usage report", "workspace", klog.KObj(workspaceObj))
+		return reconcile.Result{}, err
+	}
+	c.forgetPods(req.NamespacedName, collected)
+	return reconcile.Result{RequeueAfter: c.Interval}, nil
+}
+
+// lastCounters returns the counters of the series last collected from the pod. Pods collected for the
+// first time count from zero if they were created after the reconciler started.
+func (c *UsageReportReconciler) lastCounters(pod *corev1.Pod, s series) (tokens, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if last, found := c.pods[pod.UID]; found {
+		return last.counters[s], true
+	}
+	return tokens{}, pod.CreationTimestamp.Time.After(c.started)
+}
+
+// forgetPods replaces the counters of the pods of the workspace with the collected ones.
+func (c *UsageReportReconciler) forgetPods(workspace types.NamespacedName, collected map[types.UID]podCounters) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uid, pod := range c.pods {
+		if pod.workspace == workspace {
+			delete(c.pods, uid)
+		}
+	}
+	for uid, pod := range collected {
+		c.pods[uid] = pod
+	}
+}
+
+// counterIncrease returns the increase of the counters since they were last collected. Counters lower
+// than last time were reset by a restart of the proxy.
+func counterIncrease(last, current tokens) tokens {
+	if current.prompt < last.prompt || current.completion < last.completion {
+		return current
+	}
+	return tokens{prompt: current.prompt - last.prompt, completion: current.completion - last.completion}
+}
+
+func addTokens(a, b tokens) tokens {
+	return tokens{prompt: a.prompt + b.prompt, completion: a.completion + b.completion}
+}
+
+// parseTokenCounters returns the token counters of each series in the metrics of an inference proxy.
+func parseTokenCounters(families map[string]*dto.MetricFamily) map[series]tokens {
+	counters := map[series]tokens{}
+	for name, set := range map[string]func(*tokens, int64){
+		inferenceproxy.PromptTokensMetric:     func(t *tokens, v int64) { t.prompt = v },
+		inferenceproxy.CompletionTokensMetric: func(t *tokens, v int64) { t.completion = v },
+	} {
+		family, found := families[name]
+		if !found {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			s := series{workspace: labels["workspace"], model: labels["model"], adapter: labels["adapter"], client: labels["client"]}
+			t := counters[s]
+			set(&t, int64(metric.GetCounter().GetValue()))
+			counters[s] = t
+		}
+	}
+	return counters
+}
+
+// addUsage adds the token usage to the UsageReport of the namespace covering the current period.
+func (c *UsageReportReconciler) addUsage(ctx context.Context, namespace string, increase map[series]tokens) error {
+	for s, t := range increase {
+		if t.prompt == 0 && t.completion == 0 {
+			delete(increase, s)
+		}
+	}
+	if len(increase) == 0 {
+		return nil
+	}
+
+	now := c.clock.Now()
+	start := now.UTC().Truncate(c.Period)
+	key := client.ObjectKey{Namespace: namespace, Name: ReportName(start)}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		report := &kaitov1beta1.UsageReport{}
+		if err := c.Client.Get(ctx, key, report); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			report = &kaitov1beta1.UsageReport{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: kaitov1beta1.UsageReportSpec{
+					Start: metav1.NewTime(start),
+					End:   metav1.NewTime(start.Add(c.Period)),
+				},
+			}
+			if err := c.Client.Create(ctx, report); err != nil {
+				return err
+			}
+		}
+
+		usage := map[series]tokens{}
+		for _, u := range report.Status.Usage {
+			usage[series{workspace: u.Workspace, model: u.Model, adapter: u.Adapter, client: u.Client}] = tokens{prompt: u.PromptTokens, completion: u.CompletionTokens}
+		}
+		for s, t := range increase {
+			usage[s] = addTokens(usage[s], t)
+		}
+		report.Status.Usage = make([]kaitov1beta1.TokenUsage, 0, len(usage))
+		for s, t := range usage {
+			report.Status.Usage = append(report.Status.Usage, kaitov1beta1.TokenUsage{
+				Workspace:        s.workspace,
+				Model:            s.model,
+				Adapter:          s.adapter,
+				Client:           s.client,
+				PromptTokens:     t.prompt,
+				CompletionTokens: t.completion,
+			})
+		}
+		sort.Slice(report.Status.Usage, func(i, j int) bool {
+			a, b := report.Status.Usage[i], report.Status.Usage[j]
+			if a.Workspace != b.Workspace {
+				return a.Workspace < b.Workspace
+			}
+			if a.Model != b.Model {
+				return a.Model < b.Model
+			}
+			if a.Adapter != b.Adapter {
+				return a.Adapter < b.Adapter
+			}
+			return a.Client < b.Client
+		})
+		report.Status.LastUpdateTime = &metav1.Time{Time: now}
+		return c.Client.Status().Update(ctx, report)
+	})
+}
+
+// ReportName returns the name of the UsageReport of the period starting at start.
+func ReportName(start time.Time) string {
+	return "usage-" + start.UTC().Format("20060102-1504")
+}
+
+// scrapeInferenceProxy returns the metrics of the inference proxy of the pod.
+func scrapeInferenceProxy(ctx context.Context, pod *corev1.Pod) (map[string]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(manifests.PortInferenceProxyMetrics)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (c *UsageReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("usagereport").
+		For(&kaitov1beta1.Workspace{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(c)
+}
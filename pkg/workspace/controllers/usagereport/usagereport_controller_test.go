@@ -0,0 +1,140 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usagereport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+func newPod(name string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{kaitov1beta1.LabelWorkspaceName: "workspace-a"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+}
+
+func proxyMetrics(prompt, completion int) string {
+	labels := `adapter="",client="team-a",model="phi-4",workspace="workspace-a"`
+	return fmt.Sprintf(`# TYPE kaito_inference_proxy_prompt_tokens_total counter
+kaito_inference_proxy_prompt_tokens_total{%s} %d
+# TYPE kaito_inference_proxy_completion_tokens_total counter
+kaito_inference_proxy_completion_tokens_total{%s} %d
+`, labels, prompt, labels, completion)
+}
+
+func TestUsageReportReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = kaitov1beta1.AddToScheme(s)
+
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	workspace := &kaitov1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-a", Namespace: "default"},
+		Inference: &kaitov1beta1.InferenceSpec{
+			Preset: &kaitov1beta1.PresetSpec{PresetMeta: kaitov1beta1.PresetMeta{Name: "phi-4"}},
+			Auth:   &kaitov1beta1.AuthSpec{SecretName: "api-keys"},
+		},
+	}
+	// The usage of the old pod before its first collection may have been reported before a restart.
+	oldPod := newPod("pod-old", started.Add(-time.Hour))
+	newPod := newPod("pod-new", started.Add(time.Minute))
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(workspace, oldPod, newPod).
+		WithStatusSubresource(&kaitov1beta1.UsageReport{}).
+		Build()
+
+	clock := clocktesting.NewFakeClock(started.Add(5 * time.Minute))
+	metrics := map[string]string{}
+	reconciler := NewUsageReportReconciler(c, DefaultInterval, DefaultPeriod)
+	reconciler.clock = clock
+	reconciler.started = started
+	reconciler.scrape = func(_ context.Context, pod *corev1.Pod) (map[string]*dto.MetricFamily, error) {
+		var parser expfmt.TextParser
+		return parser.TextToMetricFamilies(strings.NewReader(metrics[pod.Name]))
+	}
+
+	steps := []struct {
+		name               string
+		oldPodMetrics      string
+		newPodMetrics      string
+		expectedPrompt     int64
+		expectedCompletion int64
+	}{
+		{
+			name:               "first collection counts pods created after the start",
+			oldPodMetrics:      proxyMetrics(100, 50),
+			newPodMetrics:      proxyMetrics(10, 5),
+			expectedPrompt:     10,
+			expectedCompletion: 5,
+		},
+		{
+			name:               "increase of all pods",
+			oldPodMetrics:      proxyMetrics(120, 60),
+			newPodMetrics:      proxyMetrics(15, 8),
+			expectedPrompt:     35,
+			expectedCompletion: 18,
+		},
+		{
+			name:               "counter reset by a proxy restart",
+			oldPodMetrics:      proxyMetrics(120, 60),
+			newPodMetrics:      proxyMetrics(2, 1),
+			expectedPrompt:     37,
+			expectedCompletion: 19,
+		},
+	}
+	for _, step := range steps {
+		metrics[oldPod.Name] = step.oldPodMetrics
+		metrics[newPod.Name] = step.newPodMetrics
+		result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(workspace)})
+		assert.NoError(t, err, step.name)
+		assert.Equal(t, DefaultInterval, result.RequeueAfter, step.name)
+
+		report := &kaitov1beta1.UsageReport{}
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "usage-20250101-0000"}, report), step.name)
+		assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), report.Spec.End.UTC(), step.name)
+		assert.Equal(t, []kaitov1beta1.TokenUsage{{
+			Workspace:        "workspace-a",
+			Model:            "phi-4",
+			Client:           "team-a",
+			PromptTokens:     step.expectedPrompt,
+			CompletionTokens: step.expectedCompletion,
+		}}, report.Status.Usage, step.name)
+		clock.Step(DefaultInterval)
+	}
+}
@@ -18,9 +18,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,10 +32,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -42,13 +51,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/exposure"
+	"github.com/kaito-project/kaito/pkg/utils/networkpolicy"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
@@ -61,8 +76,16 @@ const (
 	WorkspaceHashAnnotation = "workspace.kaito.io/hash"
 	WorkspaceNameLabel      = "workspace.kaito.io/name"
 	revisionHashSuffix      = 5
+
+	// waitingForNodesRequeueInterval is the interval to re-check qualified nodes when
+	// node auto provisioning is disabled, in addition to the node watch.
+	waitingForNodesRequeueInterval = 1 * time.Minute
 )
 
+// errWaitingForNodes is returned when node auto provisioning is disabled and there are
+// not enough qualified nodes to run the workload.
+var errWaitingForNodes = errors.New("waiting for qualified nodes")
+
 type WorkspaceReconciler struct {
 	client.Client
 	Log      logr.Logger
@@ -126,6 +149,15 @@ func (c *WorkspaceReconciler) ensureFinalizer(ctx context.Context, workspaceObj
 func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *kaitov1beta1.Workspace) (reconcile.Result, error) {
 	// Read ResourceSpec
 	err := c.applyWorkspaceResource(ctx, wObj)
+	if errors.Is(err, errWaitingForNodes) {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+			"workspacePending", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, updateErr
+		}
+		klog.InfoS("waiting for qualified nodes", "workspace", klog.KObj(wObj), "message", err.Error())
+		return reconcile.Result{RequeueAfter: waitingForNodesRequeueInterval}, nil
+	}
 	if err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
 			"workspaceFailed", err.Error()); updateErr != nil {
@@ -169,7 +201,11 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 			return reconcile.Result{}, err
 		}
 	} else if wObj.Inference != nil {
-		if err := c.ensureService(ctx, wObj); err != nil {
+		err := c.ensureService(ctx, wObj)
+		if err == nil {
+			err = c.ensureNetworkPolicy(ctx, wObj)
+		}
+		if err != nil {
 			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
 				"workspaceFailed", err.Error()); updateErr != nil {
 				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -177,7 +213,18 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 			}
 			return reconcile.Result{}, err
 		}
-		if err = c.applyInference(ctx, wObj); err != nil {
+		err = c.applyInference(ctx, wObj)
+		if errors.Is(err, errWaitingForEngineBuild) {
+			// The engine build job is owned by the workspace, its completion triggers another reconcile.
+			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+				"workspacePending", err.Error()); updateErr != nil {
+				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return reconcile.Result{}, updateErr
+			}
+			klog.InfoS("waiting for the TensorRT-LLM engine", "workspace", klog.KObj(wObj))
+			return reconcile.Result{RequeueAfter: waitingForNodesRequeueInterval}, nil
+		}
+		if err != nil {
 			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
 				"workspaceFailed", err.Error()); updateErr != nil {
 				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -186,6 +233,17 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 			return reconcile.Result{}, err
 		}
 
+		if featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] {
+			if err = c.ensureInferenceExtension(ctx, wObj); err != nil {
+				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+					"workspaceFailed", err.Error()); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+					return reconcile.Result{}, updateErr
+				}
+				return reconcile.Result{}, err
+			}
+		}
+
 		if err = c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue,
 			"workspaceSucceeded", "workspace succeeds"); err != nil {
 			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -317,9 +375,24 @@ func computeHash(w *kaitov1beta1.Workspace) string {
 
 // applyWorkspaceResource applies workspace resource spec.
 func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	autoProvisioningDisabled := kaitov1beta1.IsNodeAutoProvisioningDisabled(wObj)
+
+	if wObj.IsSharedPlacement() && !autoProvisioningDisabled {
+		sharedNode, err := c.ensureSharedNode(ctx, wObj)
+		if err != nil {
+			return fmt.Errorf("failed to place workspace on a shared node: %w", err)
+		}
+		return c.ensureWorkerNodes(ctx, wObj, []*corev1.Node{sharedNode})
+	}
+
 	// Wait for pending nodeClaims if any before we decide whether to create new node or not.
-	if err := nodeclaim.WaitForPendingNodeClaims(ctx, wObj, c.Client); err != nil {
-		return err
+	if !autoProvisioningDisabled {
+		if err := nodeclaim.WaitForPendingNodeClaims(ctx, wObj, c.Client); err != nil {
+			if isInstanceTypeUnavailable(err) {
+				return c.fallbackInstanceType(ctx, wObj, err)
+			}
+			return err
+		}
 	}
 
 	// Find all nodes that meet the requirements, they are not necessarily created by machines/nodeClaims.
@@ -332,6 +405,10 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 
 	newNodesCount := lo.FromPtr(wObj.Resource.Count) - len(selectedNodes)
 
+	if autoProvisioningDisabled {
+		return c.checkExistingNodes(ctx, wObj, selectedNodes, newNodesCount)
+	}
+
 	if newNodesCount > 0 {
 		klog.InfoS("need to create more nodes", "NodeCount", newNodesCount)
 		if err := c.updateStatusConditionIfNotMatch(ctx, wObj,
@@ -341,19 +418,27 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 			return err
 		}
 
-		newNodes, err := c.createNewNodes(ctx, wObj, newNodesCount)
+		newNodes, err := c.createNewNodes(ctx, wObj, newNodesCount, onDemandNodesNeeded(wObj, selectedNodes))
 		if err != nil {
+			if isInstanceTypeUnavailable(err) {
+				return c.fallbackInstanceType(ctx, wObj, fmt.Errorf("failed to create new nodes: %w", err))
+			}
 			return fmt.Errorf("failed to create new nodes: %w", err)
 		}
 		selectedNodes = append(selectedNodes, newNodes...)
 	}
 
-	// Ensure all gpu plugins are running successfully.
-	knownGPUConfig, _ := utils.GetGPUConfigBySKU(wObj.Resource.InstanceType)
-	if len(wObj.Resource.PreferredNodes) == 0 && knownGPUConfig != nil {
+	return c.ensureWorkerNodes(ctx, wObj, selectedNodes)
+}
+
+// ensureWorkerNodes makes sure the node plugins are running on the selected nodes and records them as
+// the worker nodes of the workspace.
+func (c *WorkspaceReconciler) ensureWorkerNodes(ctx context.Context, wObj *kaitov1beta1.Workspace, selectedNodes []*corev1.Node) error {
+	// Ensure all gpu plugins are running successfully, CPU nodes have no plugins to wait for.
+	knownGPUConfig, _ := utils.GetGPUConfigBySKU(wObj.ActiveInstanceType())
+	if len(wObj.Resource.PreferredNodes) == 0 && knownGPUConfig != nil && !kaitov1beta1.IsCPUInference(wObj) {
 		for i := range selectedNodes {
-			err = c.ensureNodePlugins(ctx, wObj, selectedNodes[i])
-			if err != nil {
+			if err := c.ensureNodePlugins(ctx, wObj, selectedNodes[i]); err != nil {
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 					"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -364,7 +449,7 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		}
 	}
 
-	if err = c.updateStatusConditionIfNotMatch(ctx, wObj,
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj,
 		kaitov1beta1.ConditionTypeNodeClaimStatus, metav1.ConditionTrue,
 		"installNodePluginsSuccess", "nodeClaim plugins have been installed successfully"); err != nil {
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -372,8 +457,50 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 	}
 
 	// Add the valid nodes names to the WorkspaceStatus.WorkerNodes.
-	err = c.updateStatusNodeListIfNotMatch(ctx, wObj, selectedNodes)
-	if err != nil {
+	if err := c.updateStatusNodeListIfNotMatch(ctx, wObj, selectedNodes); err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionFalse,
+			"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return updateErr
+		}
+		return err
+	}
+
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionTrue,
+		"workspaceResourceStatusSuccess", "workspace resource is ready"); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+
+	return nil
+}
+
+// checkExistingNodes finishes applying the workspace resource spec when node auto provisioning is disabled.
+// Only existing nodes are used. If there are not enough of them, errWaitingForNodes is returned.
+func (c *WorkspaceReconciler) checkExistingNodes(ctx context.Context, wObj *kaitov1beta1.Workspace, selectedNodes []*corev1.Node, newNodesCount int) error {
+	if newNodesCount > 0 {
+		message := fmt.Sprintf("node auto provisioning is disabled, %d of %d qualified nodes found, waiting for %d more",
+			len(selectedNodes), lo.FromPtr(wObj.Resource.Count), newNodesCount)
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeWaitingForNodes, metav1.ConditionTrue,
+			"NotEnoughQualifiedNodes", message); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return err
+		}
+		if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionFalse,
+			"WaitingForNodes", message); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return err
+		}
+		return fmt.Errorf("%w: %s", errWaitingForNodes, message)
+	}
+
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeWaitingForNodes, metav1.ConditionFalse,
+		"QualifiedNodesFound", "all required nodes are available"); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+
+	if err := c.updateStatusNodeListIfNotMatch(ctx, wObj, selectedNodes); err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 			"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -382,7 +509,7 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		return err
 	}
 
-	if err = c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionTrue,
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionTrue,
 		"workspaceResourceStatusSuccess", "workspace resource is ready"); err != nil {
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 		return err
@@ -405,6 +532,7 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 	}
 
 	preferredNodeSet := sets.New(wObj.Resource.PreferredNodes...)
+	autoProvisioningDisabled := kaitov1beta1.IsNodeAutoProvisioningDisabled(wObj)
 
 	for index := range nodeList.Items {
 		nodeObj := nodeList.Items[index]
@@ -413,6 +541,11 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 			continue
 		}
 
+		// skip cordoned nodes, e.g. unhealthy nodes that are being replaced
+		if nodeObj.Spec.Unschedulable {
+			continue
+		}
+
 		// skip nodes that are not ready
 		_, statusRunning := lo.Find(nodeObj.Status.Conditions, func(condition corev1.NodeCondition) bool {
 			return condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue
@@ -429,7 +562,10 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 
 		// match the instanceType
 		if len(wObj.Resource.PreferredNodes) == 0 { // don't match in perferred nodes mode
-			if nodeObj.Labels[corev1.LabelInstanceTypeStable] == wObj.Resource.InstanceType {
+			if nodeObj.Labels[corev1.LabelInstanceTypeStable] == wObj.ActiveInstanceType() {
+				qualifiedNodes = append(qualifiedNodes, lo.ToPtr(nodeObj))
+			} else if autoProvisioningDisabled && resources.HasGPUCapacity(&nodeObj) && nodeMeetsGPURequirement(wObj, &nodeObj) {
+				// existing GPU nodes are not necessarily labeled with a known instance type
 				qualifiedNodes = append(qualifiedNodes, lo.ToPtr(nodeObj))
 			}
 		}
@@ -438,6 +574,44 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 	return qualifiedNodes, nil
 }
 
+// nodeMeetsGPURequirement checks if an existing GPU node that is not labeled with the instance type of the workspace
+// provides enough GPUs for it: the GPUs of the instance type if it is a known SKU, otherwise the GPUs required by the
+// preset. The GPU memory is only checked if the node is labeled with it by the GPU feature discovery.
+func nodeMeetsGPURequirement(wObj *kaitov1beta1.Workspace, nodeObj *corev1.Node) bool {
+	if _, bypass := wObj.GetAnnotations()[kaitov1beta1.AnnotationBypassResourceChecks]; bypass {
+		return true
+	}
+	// The GPUs of DRA and MIG workspaces are not advertised as whole GPUs by the device plugin.
+	if wObj.UsesDynamicResourceAllocation() || (wObj.Resource.GPUPartition != nil && wObj.Resource.GPUPartition.MIGProfile != "") {
+		return true
+	}
+	gpuCount := resources.NodeGPUCount(nodeObj)
+	perGPUMemory, memoryKnown := resources.NodeGPUMemory(nodeObj)
+
+	if gpuConfig, _ := utils.GetGPUConfigBySKU(wObj.ActiveInstanceType()); gpuConfig != nil && gpuConfig.GPUCount > 0 {
+		if gpuCount < int64(gpuConfig.GPUCount) {
+			return false
+		}
+		skuPerGPUMemory := resource.NewQuantity(int64(gpuConfig.GPUMemGB/gpuConfig.GPUCount)*consts.GiBToBytes, resource.BinarySI)
+		return !memoryKnown || perGPUMemory.Cmp(*skuPerGPUMemory) >= 0
+	}
+
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return true
+	}
+	params := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name)).GetInferenceParameters()
+	nodeCount := int64(lo.FromPtr(wObj.Resource.Count))
+	if !meetsRequirement(resource.NewQuantity(nodeCount*gpuCount, resource.DecimalSI), params.GPUCountRequirement) {
+		return false
+	}
+	if !memoryKnown {
+		return true
+	}
+	totalGPUMemory := resource.NewQuantity(nodeCount*gpuCount*perGPUMemory.Value(), resource.BinarySI)
+	return meetsRequirement(perGPUMemory, params.PerGPUMemoryRequirement) &&
+		meetsRequirement(totalGPUMemory, params.TotalGPUMemoryRequirement)
+}
+
 // determineNodeOSDiskSize returns the appropriate OS disk size for the workspace
 func (c *WorkspaceReconciler) determineNodeOSDiskSize(wObj *kaitov1beta1.Workspace) string {
 	var nodeOSDiskSize string
@@ -452,8 +626,21 @@ func (c *WorkspaceReconciler) determineNodeOSDiskSize(wObj *kaitov1beta1.Workspa
 	return nodeOSDiskSize
 }
 
-// createAllNodeClaims creates multiple NodeClaims in parallel and returns the created NodeClaim objects
-func (c *WorkspaceReconciler) createAllNodeClaims(ctx context.Context, wObj *kaitov1beta1.Workspace, count int, nodeOSDiskSize string) ([]*karpenterv1.NodeClaim, error) {
+// onDemandNodesNeeded returns how many of the new nodes must be on-demand to keep the MinOnDemandCount
+// floor of a spot workspace, given the nodes that have already been selected.
+func onDemandNodesNeeded(wObj *kaitov1beta1.Workspace, selectedNodes []*corev1.Node) int {
+	if !wObj.IsSpot() {
+		return 0
+	}
+	onDemandNodes := lo.CountBy(selectedNodes, func(node *corev1.Node) bool {
+		return node.Labels[karpenterv1.CapacityTypeLabelKey] != karpenterv1.CapacityTypeSpot
+	})
+	return max(lo.FromPtr(wObj.Resource.MinOnDemandCount)-onDemandNodes, 0)
+}
+
+// createAllNodeClaims creates multiple NodeClaims in parallel and returns the created NodeClaim objects.
+// The first onDemandCount NodeClaims are created with the on-demand capacity type.
+func (c *WorkspaceReconciler) createAllNodeClaims(ctx context.Context, wObj *kaitov1beta1.Workspace, count, onDemandCount int, nodeOSDiskSize string) ([]*karpenterv1.NodeClaim, error) {
 	klog.InfoS("Creating multiple NodeClaims", "count", count, "workspace", klog.KObj(wObj))
 
 	nodeClaims := make([]*karpenterv1.NodeClaim, 0, count)
@@ -465,6 +652,9 @@ func (c *WorkspaceReconciler) createAllNodeClaims(ctx context.Context, wObj *kai
 			return apierrors.IsAlreadyExists(err)
 		}, func() error {
 			newNodeClaim = nodeclaim.GenerateNodeClaimManifest(nodeOSDiskSize, wObj)
+			if i < onDemandCount {
+				nodeclaim.SetCapacityType(newNodeClaim, karpenterv1.CapacityTypeOnDemand)
+			}
 			return nodeclaim.CreateNodeClaim(ctx, newNodeClaim, c.Client)
 		})
 
@@ -479,10 +669,10 @@ func (c *WorkspaceReconciler) createAllNodeClaims(ctx context.Context, wObj *kai
 	return nodeClaims, nil
 }
 
-func (c *WorkspaceReconciler) createNewNodes(ctx context.Context, wObj *kaitov1beta1.Workspace, newNodesCount int) ([]*corev1.Node, error) {
+func (c *WorkspaceReconciler) createNewNodes(ctx context.Context, wObj *kaitov1beta1.Workspace, newNodesCount, onDemandCount int) ([]*corev1.Node, error) {
 	// Create all node claims at once
 	nodeOSDiskSize := c.determineNodeOSDiskSize(wObj)
-	newNodeClaims, err := c.createAllNodeClaims(ctx, wObj, newNodesCount, nodeOSDiskSize)
+	newNodeClaims, err := c.createAllNodeClaims(ctx, wObj, newNodesCount, onDemandCount, nodeOSDiskSize)
 	if err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 			"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
@@ -536,6 +726,13 @@ func (c *WorkspaceReconciler) ensureNodePlugins(ctx context.Context, wObj *kaito
 	tick := timeClock.NewTicker(consts.NodePluginInstallTimeout)
 	defer tick.Stop()
 
+	// the device plugin to wait for is determined by the GPU vendor of the instance type and the requested GPU partition
+	gpuConfig, _ := utils.GetGPUConfigBySKU(wObj.ActiveInstanceType())
+	if partition := wObj.Resource.GPUPartition; partition != nil {
+		gpuConfig = gpuConfig.WithPartition(partition.MIGProfile, partition.TimeSlicingReplicas)
+	}
+	vendor := gpuConfig.Vendor()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -550,14 +747,28 @@ func (c *WorkspaceReconciler) ensureNodePlugins(ctx context.Context, wObj *kaito
 				return err
 			}
 
-			//Nvidia Plugin
-			if found := resources.CheckNvidiaPlugin(ctx, freshNode); found {
+			// GPUs claimed through DRA are published by the DRA driver instead of the device plugin
+			if wObj.UsesDynamicResourceAllocation() {
+				found, err := resources.CheckDRADriver(ctx, c.Client, freshNode.Name)
+				if err != nil {
+					klog.ErrorS(err, "cannot list resource slices", "node", freshNode.Name)
+					return err
+				}
+				if found {
+					return nil
+				}
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			// GPU device plugin
+			if found := resources.CheckGPUPlugin(ctx, freshNode, gpuConfig); found {
 				return nil
 			}
 
-			err = resources.UpdateNodeWithLabel(ctx, freshNode, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client)
+			err = resources.UpdateNodeWithLabel(ctx, freshNode, resources.LabelKeyNvidia, resources.GPULabelValue(vendor), c.Client)
 			if apierrors.IsNotFound(err) {
-				klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", freshNode.Name)
+				klog.ErrorS(err, "gpu plugin cannot be installed, node not found", "node", freshNode.Name, "vendor", vendor)
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
 					"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -583,35 +794,41 @@ func getPresetName(wObj *kaitov1beta1.Workspace) string {
 }
 
 func (c *WorkspaceReconciler) ensureService(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
-	serviceType := corev1.ServiceTypeClusterIP
-	wAnnotation := wObj.GetAnnotations()
-
-	if len(wAnnotation) != 0 {
-		val, found := wAnnotation[kaitov1beta1.AnnotationEnableLB]
-		if found && val == "True" {
-			serviceType = corev1.ServiceTypeLoadBalancer
-		}
+	var exposureSpec *kaitov1beta1.ExposureSpec
+	if wObj.Inference != nil {
+		exposureSpec = wObj.Inference.Exposure
+	}
+	serviceType := exposure.ServiceType(exposureSpec, wObj.GetAnnotations()[kaitov1beta1.AnnotationEnableLB] == "True")
+	serviceAnnotations, err := exposure.ServiceAnnotations(exposureSpec)
+	if err != nil {
+		return err
 	}
 
 	existingSVC := &corev1.Service{}
-	err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingSVC)
+	err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingSVC)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
 	} else {
-		return nil
+		if err := c.updateService(ctx, wObj, existingSVC, serviceType, serviceAnnotations); err != nil {
+			return err
+		}
+		return c.ensureExposure(ctx, wObj, exposureSpec)
 	}
 
-	isStatefulSet := false
+	isStatefulSet, leaderOnly := false, false
 	if presetName := getPresetName(wObj); presetName != "" {
 		model := plugin.KaitoModelRegister.MustGet(presetName)
 		// Dry-run the inference workload generation to determine if it will be a StatefulSet or not.
 		workloadObj, _ := inference.GeneratePresetInference(ctx, wObj, "", model, c.Client)
 		_, isStatefulSet = workloadObj.(*appsv1.StatefulSet)
+		// Multi-node inference is served by the leader pod, while every replica spreading the adapters serves.
+		leaderOnly = isStatefulSet && inference.GetAdapterPlacement(ctx, wObj, model, c.Client) == nil
 	}
 
-	serviceObj := manifests.GenerateServiceManifest(wObj, serviceType, isStatefulSet)
+	serviceObj := manifests.GenerateServiceManifest(wObj, serviceType, leaderOnly)
+	exposure.UpdateService(serviceObj, serviceType, serviceAnnotations)
 	if err := resources.CreateResource(ctx, serviceObj, c.Client); err != nil {
 		return err
 	}
@@ -623,7 +840,76 @@ func (c *WorkspaceReconciler) ensureService(ctx context.Context, wObj *kaitov1be
 		}
 	}
 
-	return nil
+	return c.ensureExposure(ctx, wObj, exposureSpec)
+}
+
+// updateService syncs the existing service with the workspace: the http port targets the inference proxy
+// when the proxy is enabled and the inference server otherwise, and the type and annotations follow the
+// exposure of the workspace.
+func (c *WorkspaceReconciler) updateService(ctx context.Context, wObj *kaitov1beta1.Workspace, serviceObj *corev1.Service,
+	serviceType corev1.ServiceType, serviceAnnotations map[string]string) error {
+	changed := exposure.UpdateService(serviceObj, serviceType, serviceAnnotations)
+	targetPort := intstr.FromInt32(manifests.GetInferenceServiceTargetPort(wObj))
+	for i := range serviceObj.Spec.Ports {
+		port := &serviceObj.Spec.Ports[i]
+		if port.Name != "http" || port.TargetPort == targetPort {
+			continue
+		}
+		klog.InfoS("Updating the target port of the inference service", "workspace", klog.KObj(wObj), "targetPort", targetPort.IntVal)
+		port.TargetPort = targetPort
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return c.Update(ctx, serviceObj)
+}
+
+// ensureExposure creates the Ingress or HTTPRoute exposing the inference service of the workspace.
+func (c *WorkspaceReconciler) ensureExposure(ctx context.Context, wObj *kaitov1beta1.Workspace, exposureSpec *kaitov1beta1.ExposureSpec) error {
+	return exposure.Ensure(ctx, c.Client, exposure.Target{
+		Owner:       *metav1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+		Namespace:   wObj.Namespace,
+		ServiceName: wObj.Name,
+		ServicePort: 80,
+	}, exposureSpec)
+}
+
+// ensureNetworkPolicy creates or updates the NetworkPolicy of the inference pods, and deletes it once the
+// network policy of the workspace is removed.
+func (c *WorkspaceReconciler) ensureNetworkPolicy(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	var desired *networkingv1.NetworkPolicy
+	if wObj.Inference.NetworkPolicy != nil {
+		serviceType := exposure.ServiceType(wObj.Inference.Exposure, wObj.GetAnnotations()[kaitov1beta1.AnnotationEnableLB] == "True")
+		inferenceExtension := featuregates.FeatureGates[consts.FeatureFlagGatewayAPIInferenceExtension] &&
+			wObj.Inference.Preset != nil && kaitov1beta1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameVLLM
+		desired = manifests.GenerateNetworkPolicyManifest(wObj, serviceType, os.Getenv("SYSTEM_NAMESPACE"), inferenceExtension)
+	}
+	return networkpolicy.Ensure(ctx, c.Client, client.ObjectKeyFromObject(wObj),
+		*metav1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")), desired)
+}
+
+// ensureAdapterConfigMap creates or updates the ConfigMap listing the adapters pulled by the inference pods when
+// the adapters are loaded at runtime. Updating the list makes the running pods pull the new adapters and remove
+// the others, without restarting them.
+func (c *WorkspaceReconciler) ensureAdapterConfigMap(ctx context.Context, wObj *kaitov1beta1.Workspace, model pkgmodel.Model) error {
+	desired := inference.GenerateAdapterConfigMap(ctx, wObj, model, c.Client)
+	if desired == nil {
+		return nil
+	}
+	existing := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+	if reflect.DeepEqual(existing.Data, desired.Data) {
+		return nil
+	}
+	klog.InfoS("Updating the adapters of workspace", "workspace", klog.KObj(wObj), "configmap", klog.KObj(existing))
+	existing.Data = desired.Data
+	return c.Update(ctx, existing)
 }
 
 func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
@@ -710,6 +996,18 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 			inferenceParam := model.GetInferenceParameters()
 			revisionStr := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
 
+			// The TensorRT-LLM engine is compiled before the inference workload that pulls it is created.
+			if kaitov1beta1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameTensorRTLLM {
+				if err = c.ensureTensorRTLLMEngine(ctx, wObj, model); err != nil {
+					return
+				}
+			}
+
+			// The adapters loaded at runtime are listed in a ConfigMap read by the adapter puller sidecar.
+			if err = c.ensureAdapterConfigMap(ctx, wObj, model); err != nil {
+				return
+			}
+
 			// Generate the inference workload (including adapters and their associated
 			// volumes) ahead of time. This is important to ensure we are modifying the
 			// correct type of workload (Deployment or StatefulSet) based on the model's
@@ -755,8 +1053,11 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 
 				// Selectively update the pod spec fields that are relevant to inference,
 				// and leave the rest unchanged in case user has customized them.
+				spec.Containers[0].Command = desiredPodSpec.Containers[0].Command
 				spec.Containers[0].Env = desiredPodSpec.Containers[0].Env
 				spec.Containers[0].VolumeMounts = desiredPodSpec.Containers[0].VolumeMounts
+				// Containers after the inference server, such as the inference proxy, are fully managed by KAITO.
+				spec.Containers = append(spec.Containers[:1], desiredPodSpec.Containers[1:]...)
 				spec.InitContainers = desiredPodSpec.InitContainers
 				spec.Volumes = desiredPodSpec.Volumes
 
@@ -780,6 +1081,14 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 		}
 	}()
 
+	if errors.Is(err, errWaitingForEngineBuild) {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
+			"WorkspaceInferenceStatusPending", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return updateErr
+		}
+		return err
+	}
 	if err != nil {
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
 			"WorkspaceInferenceStatusFailed", err.Error()); updateErr != nil {
@@ -795,6 +1104,17 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 		return err
 	}
+
+	// Report the dimension of the embedding vectors so that clients such as a RAGEngine can size their index.
+	if wObj.Inference.Preset != nil {
+		inferenceParam := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name)).GetInferenceParameters()
+		if inferenceParam.ModelType == pkgmodel.ModelTypeTextEmbedding {
+			if err := c.updateStatusEmbeddingDimensionIfNotMatch(ctx, wObj, int32(inferenceParam.EmbeddingDimension)); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -802,26 +1122,41 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1b
 func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	c.Recorder = mgr.GetEventRecorderFor("Workspace")
 
-	builder := ctrl.NewControllerManagedBy(mgr).
+	if err := resources.IndexResourceSliceNodeName(context.Background(), mgr); err != nil {
+		return err
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&kaitov1beta1.Workspace{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.ControllerRevision{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
-		Watches(&karpenterv1.NodeClaim{}, c.watchNodeClaims(), builder.WithPredicates(nodeclaim.NodeClaimPredicate)).
+		Watches(&corev1.Node{}, c.watchNodes(), builder.WithPredicates(nodePredicate)).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 5})
 
+	// Karpenter may not be installed at all if node auto provisioning is disabled.
+	if !featuregates.FeatureGates[consts.FeatureFlagDisableNodeAutoProvisioning] {
+		controllerBuilder = controllerBuilder.Watches(&karpenterv1.NodeClaim{}, c.watchNodeClaims(), builder.WithPredicates(nodeclaim.NodeClaimPredicate))
+	}
+
 	go monitorWorkspaces(context.Background(), c.Client)
 
-	return builder.Complete(c)
+	return controllerBuilder.Complete(c)
 }
 
-// watches for nodeClaim with labels indicating workspace name.
+// watches for nodeClaim with labels indicating workspace name, or shared nodeClaims with the workspaces placed on them.
 func (c *WorkspaceReconciler) watchNodeClaims() handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(
 		func(ctx context.Context, o client.Object) []reconcile.Request {
 			nodeClaimObj := o.(*karpenterv1.NodeClaim)
+			if nodeClaimObj.Labels[kaitov1beta1.LabelSharedNode] == "true" {
+				return lo.Map(nodeclaim.SharedWorkspaces(nodeClaimObj), func(key string, _ int) reconcile.Request {
+					namespace, name, _ := strings.Cut(key, "/")
+					return reconcile.Request{NamespacedName: client.ObjectKey{Name: name, Namespace: namespace}}
+				})
+			}
 			name, ok := nodeClaimObj.Labels[kaitov1beta1.LabelWorkspaceName]
 			if !ok {
 				return nil
@@ -840,3 +1175,74 @@ func (c *WorkspaceReconciler) watchNodeClaims() handler.EventHandler {
 			}
 		})
 }
+
+// nodePredicate filters node events that may turn a node into a qualified node for a workspace.
+var nodePredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+			isNodeReady(oldNode) != isNodeReady(newNode) ||
+			resources.HasGPUCapacity(oldNode) != resources.HasGPUCapacity(newNode)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func isNodeReady(nodeObj *corev1.Node) bool {
+	_, ready := lo.Find(nodeObj.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue
+	})
+	return ready
+}
+
+// watches for nodes and enqueues the workspaces that are waiting for nodes matching their label selector.
+func (c *WorkspaceReconciler) watchNodes() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(c.findWorkspacesWaitingForNode)
+}
+
+// findWorkspacesWaitingForNode returns the workspaces that are waiting for nodes and select the given node.
+func (c *WorkspaceReconciler) findWorkspacesWaitingForNode(ctx context.Context, o client.Object) []reconcile.Request {
+	nodeObj, ok := o.(*corev1.Node)
+	if !ok {
+		return nil
+	}
+
+	workspaceList := &kaitov1beta1.WorkspaceList{}
+	if err := c.Client.List(ctx, workspaceList); err != nil {
+		klog.ErrorS(err, "failed to list workspaces", "node", nodeObj.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range workspaceList.Items {
+		wObj := &workspaceList.Items[i]
+		if !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1beta1.ConditionTypeWaitingForNodes)) {
+			continue
+		}
+		if wObj.Resource.LabelSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(wObj.Resource.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(nodeObj.Labels)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(wObj),
+		})
+	}
+	return requests
+}
@@ -25,12 +25,16 @@ import (
 
 	azurev1alpha2 "github.com/Azure/karpenter-provider-azure/pkg/apis/v1alpha2"
 	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
@@ -41,7 +45,10 @@ import (
 	"github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 )
 
 func TestSelectWorkspaceNodes(t *testing.T) {
@@ -433,7 +440,7 @@ func TestCreateAndValidateNodeClaimNode(t *testing.T) {
 			}
 			ctx := context.Background()
 
-			node, err := reconciler.createNewNodes(ctx, &tc.workspace, 1)
+			node, err := reconciler.createNewNodes(ctx, &tc.workspace, 1, 0)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
 				assert.Check(t, node != nil, "Response node should not be nil")
@@ -447,12 +454,14 @@ func TestCreateAndValidateNodeClaimNode(t *testing.T) {
 func TestEnsureService(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
-		callMocks     func(c *test.MockClient)
-		expectedError error
-		workspace     *v1beta1.Workspace
+		callMocks       func(c *test.MockClient)
+		expectedError   error
+		expectedUpdates int
+		workspace       *v1beta1.Workspace
 	}{
 		"Existing service is found for workspace": {
 			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateServiceManifest(test.MockWorkspaceDistributedModel, corev1.ServiceTypeClusterIP, false))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
 			},
@@ -486,12 +495,67 @@ func TestEnsureService(t *testing.T) {
 			expectedError: nil,
 			workspace:     test.MockWorkspaceCustomModel,
 		},
+		"Existing service is retargeted at the inference proxy": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateServiceManifest(test.MockWorkspaceWithPresetVLLM, corev1.ServiceTypeClusterIP, false))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(svc *corev1.Service) bool {
+					return svc.Spec.Ports[0].TargetPort.IntVal == manifests.PortInferenceProxy
+				}), mock.Anything).Return(nil)
+			},
+			expectedError:   nil,
+			expectedUpdates: 1,
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Inference.Auth = &v1beta1.AuthSpec{SecretName: "api-keys"}
+				return w
+			}(),
+		},
+		"Existing service is exposed through an internal load balancer": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateServiceManifest(test.MockWorkspaceWithPresetVLLM, corev1.ServiceTypeClusterIP, false))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(svc *corev1.Service) bool {
+					return svc.Spec.Type == corev1.ServiceTypeLoadBalancer &&
+						svc.Annotations["service.beta.kubernetes.io/azure-load-balancer-internal"] == "true"
+				}), mock.Anything).Return(nil)
+			},
+			expectedError:   nil,
+			expectedUpdates: 1,
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Inference.Exposure = &v1beta1.ExposureSpec{Type: v1beta1.ExposureTypeLoadBalancer, Internal: true}
+				return w
+			}(),
+		},
+		"Ingress is created for the exposure": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(manifests.GenerateServiceManifest(test.MockWorkspaceWithPresetVLLM, corev1.ServiceTypeClusterIP, false))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.MatchedBy(func(ingress *networkingv1.Ingress) bool {
+					return ingress.Spec.Rules[0].Host == "phi.example.com" && ingress.Spec.TLS[0].SecretName == "testWorkspace-tls"
+				}), mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Inference.Exposure = &v1beta1.ExposureSpec{
+					Type:     v1beta1.ExposureTypeIngress,
+					Hostname: "phi.example.com",
+					TLS:      &v1beta1.ExposureTLSSpec{Issuer: &v1beta1.IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"}},
+				}
+				return w
+			}(),
+		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
+			t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 			mockClient := test.NewClient()
 			tc.callMocks(mockClient)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.Ingress{}), mock.Anything).Return(test.NotFoundError())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(test.NotFoundError())
 
 			reconciler := &WorkspaceReconciler{
 				Client: mockClient,
@@ -500,6 +564,7 @@ func TestEnsureService(t *testing.T) {
 			ctx := context.Background()
 
 			err := reconciler.ensureService(ctx, tc.workspace)
+			mockClient.AssertNumberOfCalls(t, "Update", tc.expectedUpdates)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
 			} else {
@@ -510,6 +575,166 @@ func TestEnsureService(t *testing.T) {
 
 }
 
+func TestEnsureNetworkPolicy(t *testing.T) {
+	test.RegisterTestModel()
+	withNetworkPolicy := func() *v1beta1.Workspace {
+		w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		w.Inference.Auth = &v1beta1.AuthSpec{SecretName: "api-keys"}
+		w.Inference.NetworkPolicy = &v1beta1.NetworkPolicySpec{AllowedNamespaces: []string{"apps"}}
+		return w
+	}
+	withRouter := func() *v1beta1.Workspace {
+		w := withNetworkPolicy()
+		w.Inference.NetworkPolicy.AllowRouter = true
+		return w
+	}
+	testcases := map[string]struct {
+		callMocks func(c *test.MockClient)
+		workspace *v1beta1.Workspace
+		verify    func(t *testing.T, c *test.MockClient)
+	}{
+		"Creates the network policy": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			},
+			workspace: withNetworkPolicy(),
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(policy *networkingv1.NetworkPolicy) bool {
+					rules := policy.Spec.Ingress
+					// The inference proxy, the pods of the workspace and the controller, the router is not admitted.
+					return len(rules) == 3 &&
+						rules[0].Ports[0].Port.IntVal == manifests.PortInferenceProxy && len(rules[0].From) == 2 &&
+						rules[1].Ports == nil &&
+						len(rules[2].Ports) == 1 && rules[2].Ports[0].Port.IntVal == manifests.PortInferenceProxyMetrics &&
+						rules[2].From[0].PodSelector.MatchLabels[manifests.LabelKaitoComponent] == manifests.KaitoComponentController
+				}), mock.Anything)
+			},
+		},
+		"Admits the router when the workspace allows it": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			},
+			workspace: withRouter(),
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(policy *networkingv1.NetworkPolicy) bool {
+					rules := policy.Spec.Ingress
+					// The inference proxy, the pods of the workspace, the router and the controller.
+					return len(rules) == 4 &&
+						rules[0].Ports[0].Port.IntVal == manifests.PortInferenceProxy && len(rules[0].From) == 2 &&
+						rules[1].Ports == nil &&
+						len(rules[2].Ports) == 1 && rules[2].Ports[0].Port.IntVal == manifests.PortInferenceProxy &&
+						rules[2].From[0].PodSelector.MatchLabels[manifests.LabelKaitoComponent] == manifests.KaitoComponentRouter &&
+						len(rules[3].Ports) == 1 && rules[3].Ports[0].Port.IntVal == manifests.PortInferenceProxyMetrics &&
+						rules[3].From[0].PodSelector.MatchLabels[manifests.LabelKaitoComponent] == manifests.KaitoComponentController
+				}), mock.Anything)
+			},
+		},
+		"Deletes the network policy once removed from the workspace": {
+			callMocks: func(c *test.MockClient) {
+				w := withNetworkPolicy()
+				c.CreateOrUpdateObjectInMap(manifests.GenerateNetworkPolicyManifest(w, corev1.ServiceTypeClusterIP, "kaito-workspace", false))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			},
+			workspace: test.MockWorkspaceWithPresetVLLM,
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertNumberOfCalls(t, "Delete", 1)
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv("SYSTEM_NAMESPACE", "kaito-workspace")
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+
+			err := reconciler.ensureNetworkPolicy(context.Background(), tc.workspace)
+			assert.Check(t, err == nil, "Not expected to return error")
+			tc.verify(t, mockClient)
+		})
+	}
+}
+
+func TestEnsureAdapterConfigMap(t *testing.T) {
+	test.RegisterTestModel()
+	withAdapters := func(names ...string) *v1beta1.Workspace {
+		w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		for _, name := range names {
+			w.Inference.Adapters = append(w.Inference.Adapters, v1beta1.AdapterSpec{
+				Source: &v1beta1.DataSource{Name: name, Image: "fake.kaito.com/" + name + ":0.0.1"},
+			})
+		}
+		return w
+	}
+	testcases := map[string]struct {
+		callMocks func(c *test.MockClient)
+		workspace *v1beta1.Workspace
+		verify    func(t *testing.T, c *test.MockClient)
+	}{
+		"Creates the adapter list": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(test.NotFoundError())
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workspace: withAdapters("adapter-a"),
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(cm *corev1.ConfigMap) bool {
+					return cm.Name == "testWorkspace-adapters" && cm.Data["adapters"] == "adapter-a fake.kaito.com/adapter-a:0.0.1\n"
+				}), mock.Anything)
+			},
+		},
+		"Updates the adapter list once the adapters change": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(&corev1.ConfigMap{
+					ObjectMeta: v1.ObjectMeta{Name: "testWorkspace-adapters", Namespace: "kaito"},
+					Data:       map[string]string{"adapters": "adapter-a fake.kaito.com/adapter-a:0.0.1\n"},
+				})
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workspace: withAdapters("adapter-a", "adapter-b"),
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(cm *corev1.ConfigMap) bool {
+					return cm.Data["adapters"] == "adapter-a fake.kaito.com/adapter-a:0.0.1\nadapter-b fake.kaito.com/adapter-b:0.0.1\n"
+				}), mock.Anything)
+			},
+		},
+		"Skips workspaces without adapters": {
+			callMocks: func(c *test.MockClient) {},
+			workspace: withAdapters(),
+			verify: func(t *testing.T, c *test.MockClient) {
+				c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything)
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+
+			model := plugin.KaitoModelRegister.MustGet(string(tc.workspace.Inference.Preset.Name))
+			err := reconciler.ensureAdapterConfigMap(context.Background(), tc.workspace, model)
+			assert.Check(t, err == nil, fmt.Sprintf("Not expected to return error: %v", err))
+			tc.verify(t, mockClient)
+		})
+	}
+}
+
 func TestApplyInferenceWithPreset(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
@@ -949,6 +1174,36 @@ func TestApplyWorkspaceResource(t *testing.T) {
 			workspace:     *test.MockWorkspaceBaseModel,
 			expectedError: apierrors.NewNotFound(corev1.Resource("Node"), "node1"),
 		},
+		"Wait for nodes without creating nodeClaims when node auto provisioning is disabled": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:     *byoWorkspace(test.MockWorkspaceBaseModel),
+			expectedError: fmt.Errorf("%w: node auto provisioning is disabled, 0 of 1 qualified nodes found, waiting for 1 more", errWaitingForNodes),
+		},
+		"Successfully apply workspace resource with existing nodes when node auto provisioning is disabled": {
+			callMocks: func(c *test.MockClient) {
+				nodeList := test.MockNodeList
+				relevantMap := c.CreateMapWithType(nodeList)
+				//insert node objects into the map
+				for _, obj := range nodeList.Items {
+					n := obj
+					objKey := client.ObjectKeyFromObject(&n)
+
+					relevantMap[objKey] = &n
+				}
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:     *byoWorkspace(test.MockWorkspaceBaseModel),
+			expectedError: nil,
+		},
 	}
 
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
@@ -1168,3 +1423,182 @@ func TestUpdateControllerRevision1(t *testing.T) {
 		})
 	}
 }
+
+func byoWorkspace(wObj *v1beta1.Workspace) *v1beta1.Workspace {
+	w := wObj.DeepCopy()
+	w.Annotations = map[string]string{
+		v1beta1.AnnotationNodeProvisioningMode: v1beta1.NodeProvisioningModeBYO,
+	}
+	return w
+}
+
+func TestFindWorkspacesWaitingForNode(t *testing.T) {
+	waitingCondition := v1.Condition{
+		Type:   string(v1beta1.ConditionTypeWaitingForNodes),
+		Status: v1.ConditionTrue,
+	}
+	testcases := map[string]struct {
+		workspaces       []v1beta1.Workspace
+		nodeLabels       map[string]string
+		expectedRequests int
+	}{
+		"Enqueue waiting workspace with matching label selector": {
+			workspaces: []v1beta1.Workspace{
+				func() v1beta1.Workspace {
+					w := byoWorkspace(test.MockWorkspaceBaseModel)
+					w.Status.Conditions = []v1.Condition{waitingCondition}
+					return *w
+				}(),
+			},
+			nodeLabels:       map[string]string{"apps": "test"},
+			expectedRequests: 1,
+		},
+		"Skip waiting workspace whose label selector does not match": {
+			workspaces: []v1beta1.Workspace{
+				func() v1beta1.Workspace {
+					w := byoWorkspace(test.MockWorkspaceBaseModel)
+					w.Status.Conditions = []v1.Condition{waitingCondition}
+					return *w
+				}(),
+			},
+			nodeLabels:       map[string]string{"apps": "other"},
+			expectedRequests: 0,
+		},
+		"Skip workspace that is not waiting for nodes": {
+			workspaces:       []v1beta1.Workspace{*test.MockWorkspaceBaseModel},
+			nodeLabels:       map[string]string{"apps": "test"},
+			expectedRequests: 0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			relevantMap := mockClient.CreateMapWithType(&v1beta1.WorkspaceList{})
+			for i := range tc.workspaces {
+				w := tc.workspaces[i]
+				relevantMap[client.ObjectKeyFromObject(&w)] = &w
+			}
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.WorkspaceList{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			node := &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   "node1",
+					Labels: tc.nodeLabels,
+				},
+			}
+
+			requests := reconciler.findWorkspacesWaitingForNode(context.Background(), node)
+			assert.Equal(t, len(requests), tc.expectedRequests)
+		})
+	}
+}
+
+func TestOnDemandNodesNeeded(t *testing.T) {
+	spotWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+	spotWorkspace.Resource.CapacityType = v1beta1.CapacityTypeSpot
+	spotWorkspace.Resource.MinOnDemandCount = lo.ToPtr(2)
+
+	spotNode := &corev1.Node{ObjectMeta: v1.ObjectMeta{Labels: map[string]string{karpenterv1.CapacityTypeLabelKey: karpenterv1.CapacityTypeSpot}}}
+	onDemandNode := &corev1.Node{ObjectMeta: v1.ObjectMeta{Labels: map[string]string{karpenterv1.CapacityTypeLabelKey: karpenterv1.CapacityTypeOnDemand}}}
+
+	testcases := map[string]struct {
+		workspace     *v1beta1.Workspace
+		selectedNodes []*corev1.Node
+		expected      int
+	}{
+		"On-demand workspace": {
+			workspace: test.MockWorkspaceWithPreset,
+			expected:  0,
+		},
+		"Spot workspace without nodes": {
+			workspace: spotWorkspace,
+			expected:  2,
+		},
+		"Spot workspace with spot and on-demand nodes": {
+			workspace:     spotWorkspace,
+			selectedNodes: []*corev1.Node{spotNode, onDemandNode},
+			expected:      1,
+		},
+		"Spot workspace with enough on-demand nodes": {
+			workspace:     spotWorkspace,
+			selectedNodes: []*corev1.Node{onDemandNode, onDemandNode, onDemandNode},
+			expected:      0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, onDemandNodesNeeded(tc.workspace, tc.selectedNodes), tc.expected)
+		})
+	}
+}
+
+func TestNodeMeetsGPURequirement(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	gpuNode := func(gpuCount string, gpuMemoryMiB string) *corev1.Node {
+		nodeObj := &corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{}},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{resources.CapacityNvidiaGPU: resource.MustParse(gpuCount)},
+			},
+		}
+		if gpuMemoryMiB != "" {
+			nodeObj.Labels[resources.LabelNvidiaGPUMemory] = gpuMemoryMiB
+		}
+		return nodeObj
+	}
+	unknownSKUWorkspace := byoWorkspace(test.MockWorkspaceWithPresetVLLM)
+	unknownSKUWorkspace.Resource.InstanceType = "unknown-instance-type"
+	bypassWorkspace := byoWorkspace(test.MockWorkspaceWithPresetVLLM)
+	bypassWorkspace.Annotations[v1beta1.AnnotationBypassResourceChecks] = "true"
+
+	testcases := map[string]struct {
+		workspace *v1beta1.Workspace
+		node      *corev1.Node
+		expected  bool
+	}{
+		"Node has fewer GPUs than the instance type": {
+			workspace: byoWorkspace(test.MockWorkspaceWithPresetVLLM),
+			node:      gpuNode("1", ""),
+			expected:  false,
+		},
+		"Node has the GPUs of the instance type": {
+			workspace: byoWorkspace(test.MockWorkspaceWithPresetVLLM),
+			node:      gpuNode("2", ""),
+			expected:  true,
+		},
+		"Node has less GPU memory than the instance type": {
+			workspace: byoWorkspace(test.MockWorkspaceWithPresetVLLM),
+			node:      gpuNode("2", "8192"),
+			expected:  false,
+		},
+		"Node meets the preset requirement of an unknown instance type": {
+			workspace: unknownSKUWorkspace,
+			node:      gpuNode("1", "16384"),
+			expected:  true,
+		},
+		"Node has less GPU memory than the preset requires": {
+			workspace: unknownSKUWorkspace,
+			node:      gpuNode("1", "4096"),
+			expected:  false,
+		},
+		"Resource checks are bypassed": {
+			workspace: bypassWorkspace,
+			node:      gpuNode("1", ""),
+			expected:  true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nodeMeetsGPURequirement(tc.workspace, tc.node))
+		})
+	}
+}
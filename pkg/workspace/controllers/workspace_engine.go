@@ -0,0 +1,83 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+)
+
+// errWaitingForEngineBuild is returned while the TensorRT-LLM engine of a workspace is being compiled.
+var errWaitingForEngineBuild = errors.New("waiting for the TensorRT-LLM engine to be built")
+
+// ensureTensorRTLLMEngine makes sure the TensorRT-LLM engine of the workspace is in the engine cache before the
+// inference workload pulls it. The engine build job is created if it does not exist and recreated if the engine
+// of the workspace changed, e.g. because a fallback instance type with another GPU model is used.
+// errWaitingForEngineBuild is returned until the job succeeds.
+func (c *WorkspaceReconciler) ensureTensorRTLLMEngine(ctx context.Context, wObj *kaitov1beta1.Workspace, model pkgmodel.Model) error {
+	revisionNum := wObj.Annotations[kaitov1beta1.WorkspaceRevisionAnnotation]
+	desiredJob, err := inference.GenerateTensorRTLLMEngineBuildJob(ctx, wObj, revisionNum, model, c.Client)
+	if err != nil {
+		return err
+	}
+
+	existingJob := &batchv1.Job{}
+	if err := resources.GetResource(ctx, desiredJob.Name, desiredJob.Namespace, c.Client, existingJob); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.InfoS("Building TensorRT-LLM engine", "workspace", klog.KObj(wObj), "engine", desiredJob.Annotations[kaitov1beta1.AnnotationTensorRTLLMEngine])
+		if err := resources.CreateResource(ctx, desiredJob, c.Client); client.IgnoreAlreadyExists(err) != nil {
+			return err
+		}
+		return errWaitingForEngineBuild
+	}
+
+	if existingJob.Annotations[kaitov1beta1.AnnotationTensorRTLLMEngine] != desiredJob.Annotations[kaitov1beta1.AnnotationTensorRTLLMEngine] {
+		// The job is created again with the new engine once the deletion is observed.
+		deletePolicy := metav1.DeletePropagationForeground
+		if err := c.Delete(ctx, existingJob, &client.DeleteOptions{
+			PropagationPolicy: &deletePolicy,
+		}); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		return errWaitingForEngineBuild
+	}
+
+	for _, condition := range existingJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			return fmt.Errorf("engine build job %s failed: %s", existingJob.Name, condition.Message)
+		}
+	}
+	return errWaitingForEngineBuild
+}
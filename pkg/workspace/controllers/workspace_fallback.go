@@ -0,0 +1,162 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+)
+
+// isInstanceTypeUnavailable checks if the error is caused by the cloud provider being out of capacity
+// for the requested instance type.
+func isInstanceTypeUnavailable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), consts.ErrorInstanceTypesUnavailable)
+}
+
+// fallbackInstanceTypeCandidates returns the instance types of the workspace in the order they should be tried.
+// The requested instance type always comes first, followed by the fallback instance types sorted by the policy.
+func fallbackInstanceTypeCandidates(wObj *kaitov1beta1.Workspace) []string {
+	fallbacks := append([]string{}, wObj.Resource.InstanceTypes...)
+	if wObj.Resource.InstanceTypePolicy == kaitov1beta1.InstanceTypePolicyFewestGPUs {
+		gpuCount := func(instanceType string) int {
+			// Instance types that are not in the SKU list are tried last.
+			gpuConfig, _ := utils.GetGPUConfigBySKU(instanceType)
+			if gpuConfig == nil {
+				return math.MaxInt
+			}
+			return gpuConfig.GPUCount
+		}
+		sort.SliceStable(fallbacks, func(i, j int) bool {
+			return gpuCount(fallbacks[i]) < gpuCount(fallbacks[j])
+		})
+	}
+	return append([]string{wObj.Resource.InstanceType}, fallbacks...)
+}
+
+// nextFallbackInstanceType returns the first instance type after the active one that satisfies the preset
+// requirements of the workspace. An empty string is returned if all candidates have been tried.
+func nextFallbackInstanceType(wObj *kaitov1beta1.Workspace) string {
+	candidates := fallbackInstanceTypeCandidates(wObj)
+	activeIndex := lo.IndexOf(candidates, wObj.ActiveInstanceType())
+	for _, instanceType := range candidates[activeIndex+1:] {
+		if instanceTypeSatisfiesPreset(wObj, instanceType) {
+			return instanceType
+		}
+	}
+	return ""
+}
+
+// instanceTypeSatisfiesPreset checks if Count nodes of the given instance type provide enough GPUs and
+// GPU memory to run the inference preset of the workspace. Instance types without a known GPU config
+// and workspaces without an inference preset are not checked.
+func instanceTypeSatisfiesPreset(wObj *kaitov1beta1.Workspace, instanceType string) bool {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return true
+	}
+	if _, bypass := wObj.GetAnnotations()[kaitov1beta1.AnnotationBypassResourceChecks]; bypass {
+		return true
+	}
+	gpuConfig, _ := utils.GetGPUConfigBySKU(instanceType)
+	if gpuConfig == nil || gpuConfig.GPUCount == 0 {
+		return true
+	}
+
+	params := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name)).GetInferenceParameters()
+	nodeCount := lo.FromPtr(wObj.Resource.Count)
+	totalNumGPUs := resource.NewQuantity(int64(nodeCount*gpuConfig.GPUCount), resource.DecimalSI)
+	perGPUMemory := resource.NewQuantity(int64(gpuConfig.GPUMemGB/gpuConfig.GPUCount)*consts.GiBToBytes, resource.BinarySI)
+	totalGPUMemory := resource.NewQuantity(int64(nodeCount*gpuConfig.GPUMemGB)*consts.GiBToBytes, resource.BinarySI)
+
+	return meetsRequirement(totalNumGPUs, params.GPUCountRequirement) &&
+		meetsRequirement(perGPUMemory, params.PerGPUMemoryRequirement) &&
+		meetsRequirement(totalGPUMemory, params.TotalGPUMemoryRequirement)
+}
+
+// meetsRequirement checks if the provided quantity is no less than the required one. An empty requirement is always met.
+func meetsRequirement(provided *resource.Quantity, required string) bool {
+	if required == "" {
+		return true
+	}
+	return provided.Cmp(resource.MustParse(required)) >= 0
+}
+
+// fallbackInstanceType switches the workspace to the next fallback instance type after the capacity of the
+// active instance type turned out to be unavailable. The nodeClaims of other instance types are deleted so that
+// the workload is not split across instance types. The original error is returned if there is nothing to fall back to.
+func (c *WorkspaceReconciler) fallbackInstanceType(ctx context.Context, wObj *kaitov1beta1.Workspace, cause error) error {
+	current := wObj.ActiveInstanceType()
+	next := nextFallbackInstanceType(wObj)
+	if next == "" {
+		return cause
+	}
+	klog.InfoS("instance type is unavailable, falling back", "workspace", klog.KObj(wObj), "instanceType", current, "fallback", next)
+
+	nodeClaims, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+	if err != nil {
+		return err
+	}
+	for i := range nodeClaims.Items {
+		if nodeClaimHasInstanceType(&nodeClaims.Items[i], next) {
+			continue
+		}
+		if err := c.Delete(ctx, &nodeClaims.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete nodeClaim %s: %w", nodeClaims.Items[i].Name, err)
+		}
+	}
+
+	if err := c.updateStatusInstanceTypeIfNotMatch(ctx, wObj, next); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+
+	message := fmt.Sprintf("instance type %s is unavailable, falling back to %s", current, next)
+	if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
+		"InstanceTypeFallback", message); err != nil {
+		klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return err
+	}
+	if c.Recorder != nil {
+		c.Recorder.Event(wObj, corev1.EventTypeWarning, "InstanceTypeFallback", message)
+	}
+
+	// Requeue so that the nodes are provisioned with the fallback instance type.
+	return errors.New(message)
+}
+
+// nodeClaimHasInstanceType checks if the nodeClaim requests the given instance type.
+func nodeClaimHasInstanceType(nodeClaimObj *karpenterv1.NodeClaim, instanceType string) bool {
+	return lo.ContainsBy(nodeClaimObj.Spec.Requirements, func(requirement karpenterv1.NodeSelectorRequirementWithMinValues) bool {
+		return requirement.Key == corev1.LabelInstanceTypeStable &&
+			requirement.Operator == corev1.NodeSelectorOpIn &&
+			lo.Contains(requirement.Values, instanceType)
+	})
+}
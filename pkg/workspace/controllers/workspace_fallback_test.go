@@ -0,0 +1,131 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+)
+
+func fallbackWorkspace(policy v1beta1.InstanceTypePolicy, active string) *v1beta1.Workspace {
+	wObj := test.MockWorkspaceDistributedModel.DeepCopy()
+	wObj.Resource.InstanceType = "Standard_NC24s_v3"
+	wObj.Resource.InstanceTypes = []string{"Standard_NC6s_v3", "Standard_NC96ads_A100_v4", "Standard_NC48ads_A100_v4"}
+	wObj.Resource.InstanceTypePolicy = policy
+	wObj.Status.InstanceType = active
+	return wObj
+}
+
+func TestNextFallbackInstanceType(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	testcases := map[string]struct {
+		workspace *v1beta1.Workspace
+		expected  string
+	}{
+		"Ordered policy skips instance types that cannot fit the preset": {
+			workspace: fallbackWorkspace("", ""),
+			expected:  "Standard_NC96ads_A100_v4",
+		},
+		"Ordered policy continues after the active fallback": {
+			workspace: fallbackWorkspace(v1beta1.InstanceTypePolicyOrdered, "Standard_NC96ads_A100_v4"),
+			expected:  "Standard_NC48ads_A100_v4",
+		},
+		"Ordered policy has nothing left to try": {
+			workspace: fallbackWorkspace(v1beta1.InstanceTypePolicyOrdered, "Standard_NC48ads_A100_v4"),
+			expected:  "",
+		},
+		"FewestGPUs policy prefers fewer GPUs per node": {
+			workspace: fallbackWorkspace(v1beta1.InstanceTypePolicyFewestGPUs, ""),
+			expected:  "Standard_NC48ads_A100_v4",
+		},
+		"FewestGPUs policy continues after the active fallback": {
+			workspace: fallbackWorkspace(v1beta1.InstanceTypePolicyFewestGPUs, "Standard_NC48ads_A100_v4"),
+			expected:  "Standard_NC96ads_A100_v4",
+		},
+		"No fallback instance types": {
+			workspace: test.MockWorkspaceDistributedModel.DeepCopy(),
+			expected:  "",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, nextFallbackInstanceType(tc.workspace), tc.expected)
+		})
+	}
+}
+
+func TestFallbackInstanceType(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	cause := errors.New(consts.ErrorInstanceTypesUnavailable)
+
+	testcases := map[string]struct {
+		callMocks            func(c *test.MockClient)
+		workspace            *v1beta1.Workspace
+		expectedError        string
+		expectedInstanceType string
+	}{
+		"Return the original error when there is no fallback": {
+			callMocks:            func(c *test.MockClient) {},
+			workspace:            fallbackWorkspace(v1beta1.InstanceTypePolicyOrdered, "Standard_NC48ads_A100_v4"),
+			expectedError:        consts.ErrorInstanceTypesUnavailable,
+			expectedInstanceType: "Standard_NC48ads_A100_v4",
+		},
+		"Delete unavailable nodeClaims and record the fallback instance type": {
+			callMocks: func(c *test.MockClient) {
+				nodeClaim := test.MockNodeClaim.DeepCopy()
+				nodeClaim.Spec.Requirements[0].Values = []string{"Standard_NC24s_v3"}
+				relevantMap := c.CreateMapWithType(&karpenterv1.NodeClaimList{})
+				relevantMap[client.ObjectKeyFromObject(nodeClaim)] = nodeClaim
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaim{}), mock.Anything).Return(nil).Once()
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:            fallbackWorkspace(v1beta1.InstanceTypePolicyOrdered, ""),
+			expectedError:        "instance type Standard_NC24s_v3 is unavailable, falling back to Standard_NC96ads_A100_v4",
+			expectedInstanceType: "Standard_NC96ads_A100_v4",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(tc.workspace)
+			tc.callMocks(mockClient)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			err := reconciler.fallbackInstanceType(context.Background(), tc.workspace, cause)
+			assert.Error(t, err, tc.expectedError)
+			assert.Equal(t, tc.workspace.ActiveInstanceType(), tc.expectedInstanceType)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
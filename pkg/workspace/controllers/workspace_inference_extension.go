@@ -0,0 +1,168 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+// ensureInferenceExtension creates the Gateway API Inference Extension resources of the workspace: an InferencePool
+// selecting the inference pods along with its endpoint picker, and an InferenceModel for the base model and for
+// each adapter, unless the adapters are spread across the replicas. InferenceModels of adapters removed from the
+// workspace are deleted.
+func (c *WorkspaceReconciler) ensureInferenceExtension(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	// The endpoint picker scrapes the vLLM metrics to pick an endpoint, other runtimes are not supported.
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		klog.InfoS("Skip inference extension resources for workspace without preset", "workspace", klog.KObj(wObj))
+		return nil
+	}
+	if kaitov1beta1.GetWorkspaceRuntimeName(wObj) != pkgmodel.RuntimeNameVLLM {
+		klog.InfoS("Skip inference extension resources for workspace not using the vLLM runtime", "workspace", klog.KObj(wObj))
+		return nil
+	}
+
+	model := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name))
+	isStatefulSet := true
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, &appsv1.StatefulSet{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		isStatefulSet = false
+	}
+	// Multi-node inference is served by the leader pod, while every replica spreading the adapters serves.
+	spread := inference.GetAdapterPlacement(ctx, wObj, model, c.Client) != nil
+	leaderOnly := isStatefulSet && !spread
+
+	serviceAccount, role, roleBinding, deployment, service := manifests.GenerateEndpointPickerManifests(wObj, manifests.DefaultEndpointPickerImage)
+	desired := []client.Object{serviceAccount, role, roleBinding, deployment, service, manifests.GenerateInferencePoolManifest(wObj, leaderOnly)}
+
+	inferenceParam := model.GetInferenceParameters()
+	baseModelName := inferenceParam.VLLM.ModelName
+	if baseModelName == "" {
+		baseModelName = string(wObj.Inference.Preset.Name)
+	}
+	inferenceModelNames := sets.New(manifests.GetInferenceModelName(wObj, ""))
+	desired = append(desired, manifests.GenerateInferenceModelManifest(wObj, manifests.GetInferenceModelName(wObj, ""), baseModelName))
+	for _, adapter := range wObj.Inference.Adapters {
+		// The endpoint picker does not know which replicas loaded a spread adapter, and would send its requests
+		// to replicas without it. Spread adapters are only served through the KAITO router.
+		if spread || adapter.Source == nil || adapter.Source.Name == "" {
+			continue
+		}
+		// vLLM serves each adapter under the name of its directory, which is the name of the adapter source.
+		name := manifests.GetInferenceModelName(wObj, adapter.Source.Name)
+		inferenceModelNames.Insert(name)
+		desired = append(desired, manifests.GenerateInferenceModelManifest(wObj, name, adapter.Source.Name))
+	}
+
+	for _, obj := range desired {
+		if err := c.ensureInferenceExtensionObject(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	existingModels := &unstructured.UnstructuredList{}
+	existingModels.SetGroupVersionKind(manifests.InferenceModelGVK.GroupVersion().WithKind(manifests.InferenceModelGVK.Kind + "List"))
+	if err := c.List(ctx, existingModels, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1beta1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return err
+	}
+	for i := range existingModels.Items {
+		inferenceModel := &existingModels.Items[i]
+		if inferenceModelNames.Has(inferenceModel.GetName()) {
+			continue
+		}
+		klog.InfoS("Deleting InferenceModel of removed adapter", "workspace", klog.KObj(wObj), "inferencemodel", klog.KObj(inferenceModel))
+		if err := c.Delete(ctx, inferenceModel); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureInferenceExtensionObject creates the object, or updates it when the fields generated by the controller
+// changed, e.g. the target port of the pool once the inference proxy is enabled, or the served model name.
+func (c *WorkspaceReconciler) ensureInferenceExtensionObject(ctx context.Context, desired client.Object) error {
+	existing := desired.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+	if !copyInferenceExtensionSpec(existing, desired) {
+		return nil
+	}
+	klog.InfoS("Updating inference extension resource", "kind", desired.GetObjectKind().GroupVersionKind().Kind, "object", klog.KObj(existing))
+	return c.Update(ctx, existing)
+}
+
+// copyInferenceExtensionSpec copies the fields generated by the controller from desired into existing, and
+// returns false if they already match. Fields defaulted by the API server are ignored.
+func copyInferenceExtensionSpec(existing, desired client.Object) bool {
+	switch desired := desired.(type) {
+	case *appsv1.Deployment:
+		existing := existing.(*appsv1.Deployment)
+		if equality.Semantic.DeepDerivative(desired.Spec, existing.Spec) {
+			return false
+		}
+		existing.Spec = desired.Spec
+	case *corev1.Service:
+		existing := existing.(*corev1.Service)
+		if equality.Semantic.DeepDerivative(desired.Spec.Ports, existing.Spec.Ports) &&
+			equality.Semantic.DeepEqual(desired.Spec.Selector, existing.Spec.Selector) {
+			return false
+		}
+		existing.Spec.Ports = desired.Spec.Ports
+		existing.Spec.Selector = desired.Spec.Selector
+	case *rbacv1.Role:
+		existing := existing.(*rbacv1.Role)
+		if equality.Semantic.DeepEqual(desired.Rules, existing.Rules) {
+			return false
+		}
+		existing.Rules = desired.Rules
+	case *rbacv1.RoleBinding:
+		existing := existing.(*rbacv1.RoleBinding)
+		if equality.Semantic.DeepEqual(desired.Subjects, existing.Subjects) {
+			return false
+		}
+		existing.Subjects = desired.Subjects
+	case *unstructured.Unstructured:
+		existing := existing.(*unstructured.Unstructured)
+		if equality.Semantic.DeepEqual(desired.Object["spec"], existing.Object["spec"]) {
+			return false
+		}
+		existing.Object["spec"] = desired.Object["spec"]
+	default:
+		return false
+	}
+	return true
+}
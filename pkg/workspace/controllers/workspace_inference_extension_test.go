@@ -0,0 +1,182 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestEnsureInferenceExtension(t *testing.T) {
+	test.RegisterTestModel()
+	notFound := apierrors.NewNotFound(schema.GroupResource{}, "")
+
+	t.Run("Should create the pool and a model for the base model and each adapter", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{Client: mockClient}
+		wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		wObj.Name = "workspace"
+		wObj.Inference.Adapters = []kaitov1beta1.AdapterSpec{
+			{Source: &kaitov1beta1.DataSource{Name: "adapter-a", Image: "adapter-a:0.0.1"}},
+		}
+
+		// An InferenceModel of an adapter that was removed from the workspace.
+		staleModel := manifests.GenerateInferenceModelManifest(wObj, "workspace-adapter-b", "adapter-b")
+		mockClient.CreateMapWithType(&unstructured.UnstructuredList{})[client.ObjectKeyFromObject(staleModel)] = staleModel
+
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(notFound)
+		mockClient.On("Create", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+		mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).Return(nil)
+		mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+
+		err := reconciler.ensureInferenceExtension(context.Background(), wObj)
+		assert.NoError(t, err)
+
+		// Service account, role, role binding, deployment, service, pool and two models.
+		mockClient.AssertNumberOfCalls(t, "Create", 8)
+		mockClient.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || u.GetKind() != "InferenceModel" || u.GetName() != "workspace-adapter-a" {
+				return false
+			}
+			modelName, _, _ := unstructured.NestedString(u.Object, "spec", "modelName")
+			return modelName == "adapter-a"
+		}), mock.Anything)
+		mockClient.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || u.GetKind() != "InferenceModel" || u.GetName() != "workspace" {
+				return false
+			}
+			modelName, _, _ := unstructured.NestedString(u.Object, "spec", "modelName")
+			return modelName == "mymodel"
+		}), mock.Anything)
+		mockClient.AssertCalled(t, "Delete", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			return obj.GetName() == "workspace-adapter-b"
+		}), mock.Anything)
+	})
+
+	t.Run("Should select the leader pod of a StatefulSet", func(t *testing.T) {
+		wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		pool := manifests.GenerateInferencePoolManifest(wObj, true)
+		selector, _, _ := unstructured.NestedStringMap(pool.Object, "spec", "selector")
+		assert.Equal(t, map[string]string{
+			kaitov1beta1.LabelWorkspaceName:      wObj.Name,
+			"statefulset.kubernetes.io/pod-name": wObj.Name + "-0",
+		}, selector)
+
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{Client: mockClient}
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(notFound)
+		mockClient.On("Create", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+		mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).Return(nil)
+
+		assert.NoError(t, reconciler.ensureInferenceExtension(context.Background(), wObj))
+		mockClient.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || u.GetKind() != "InferencePool" {
+				return false
+			}
+			selector, _, _ := unstructured.NestedStringMap(u.Object, "spec", "selector")
+			return selector["statefulset.kubernetes.io/pod-name"] == wObj.Name+"-0"
+		}), mock.Anything)
+	})
+
+	t.Run("Should select every replica spreading the adapters", func(t *testing.T) {
+		wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		wObj.Resource.Count = lo.ToPtr(2)
+		wObj.Inference.AdapterPlacement = &kaitov1beta1.AdapterPlacementSpec{Policy: kaitov1beta1.AdapterPlacementPolicySpread}
+		wObj.Inference.Adapters = []kaitov1beta1.AdapterSpec{
+			{Source: &kaitov1beta1.DataSource{Name: "adapter-a", Image: "adapter-a:0.0.1"}},
+		}
+
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{Client: mockClient}
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(notFound)
+		mockClient.On("Create", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+		mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).Return(nil)
+
+		assert.NoError(t, reconciler.ensureInferenceExtension(context.Background(), wObj))
+		mockClient.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || u.GetKind() != "InferencePool" {
+				return false
+			}
+			selector, _, _ := unstructured.NestedStringMap(u.Object, "spec", "selector")
+			_, leaderOnly := selector["statefulset.kubernetes.io/pod-name"]
+			// The adapters are loaded at runtime, the gateway goes through the inference proxy.
+			port, _, _ := unstructured.NestedInt64(u.Object, "spec", "targetPortNumber")
+			return !leaderOnly && port == manifests.PortInferenceProxy
+		}), mock.Anything)
+		// The gateway cannot route a spread adapter to its replicas, only the router serves it.
+		mockClient.AssertNotCalled(t, "Create", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			u, ok := obj.(*unstructured.Unstructured)
+			return ok && u.GetKind() == "InferenceModel" && u.GetName() == manifests.GetInferenceModelName(wObj, "adapter-a")
+		}), mock.Anything)
+	})
+
+	t.Run("Should update the pool once the inference proxy is enabled", func(t *testing.T) {
+		wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		wObj.Name = "workspace"
+		existingPool := manifests.GenerateInferencePoolManifest(wObj, false)
+		wObj.Inference.Auth = &kaitov1beta1.AuthSpec{SecretName: "api-keys"}
+
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{Client: mockClient}
+		mockClient.CreateOrUpdateObjectInMap(existingPool)
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(notFound)
+		mockClient.On("Create", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+		// The mock client returns the same object for the pool and the InferenceModel of the base model, which
+		// share the name of the workspace, so the pool is recorded when it is updated first.
+		var updatedPorts []int64
+		mockClient.On("Update", mock.IsType(context.Background()), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			if u, ok := args.Get(1).(*unstructured.Unstructured); ok && u.GetKind() == "InferencePool" {
+				port, _, _ := unstructured.NestedInt64(u.Object, "spec", "targetPortNumber")
+				updatedPorts = append(updatedPorts, port)
+			}
+		}).Return(nil)
+		mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).Return(nil)
+
+		assert.NoError(t, reconciler.ensureInferenceExtension(context.Background(), wObj))
+		if assert.NotEmpty(t, updatedPorts) {
+			assert.Equal(t, int64(manifests.PortInferenceProxy), updatedPorts[0])
+		}
+	})
+
+	t.Run("Should skip workspaces not using vLLM", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{Client: mockClient}
+		wObj := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+		wObj.Annotations = map[string]string{kaitov1beta1.AnnotationWorkspaceRuntime: string(pkgmodel.RuntimeNameHuggingfaceTransformers)}
+
+		assert.NoError(t, reconciler.ensureInferenceExtension(context.Background(), wObj))
+		mockClient.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
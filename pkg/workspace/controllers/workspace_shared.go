@@ -0,0 +1,182 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+)
+
+// sharedGPUCount returns the number of GPUs a workspace with the shared placement policy requests on a node.
+func sharedGPUCount(wObj *kaitov1beta1.Workspace) int {
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return 0
+	}
+	model := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name))
+	gpuCount := resource.MustParse(model.GetInferenceParameters().GPUCountRequirement)
+	return int(gpuCount.Value())
+}
+
+// ensureSharedNode places the workspace on a shared nodeClaim and returns its node. The nodeClaim the workspace
+// was already placed on is reused. Otherwise the workspace is bin-packed onto the first shared nodeClaim of the
+// same instance type and labels that has enough free GPUs, and a new shared nodeClaim is created if none has.
+func (c *WorkspaceReconciler) ensureSharedNode(ctx context.Context, wObj *kaitov1beta1.Workspace) (*corev1.Node, error) {
+	nodeClaims, err := nodeclaim.ListSharedNodeClaims(ctx, c.Client)
+	if err != nil {
+		return nil, err
+	}
+	workspaceKey := client.ObjectKeyFromObject(wObj).String()
+
+	nodeClaimObj, found := lo.Find(nodeClaims.Items, func(nc karpenterv1.NodeClaim) bool {
+		return nc.DeletionTimestamp.IsZero() && lo.Contains(nodeclaim.SharedWorkspaces(&nc), workspaceKey)
+	})
+	if !found {
+		candidate, err := c.findSharedNodeClaimWithCapacity(ctx, wObj, nodeClaims.Items)
+		if err != nil {
+			return nil, err
+		}
+		if candidate != nil {
+			klog.InfoS("placing workspace on shared nodeClaim", "workspace", klog.KObj(wObj), "nodeClaim", klog.KObj(candidate))
+			nodeclaim.SetSharedWorkspaces(candidate, append(nodeclaim.SharedWorkspaces(candidate), workspaceKey))
+			if err := c.Update(ctx, candidate); err != nil {
+				return nil, fmt.Errorf("failed to add workspace to shared nodeClaim %s: %w", candidate.Name, err)
+			}
+			nodeClaimObj = *candidate
+		} else {
+			if err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1beta1.ConditionTypeNodeClaimStatus, metav1.ConditionUnknown,
+				"CreateNodeClaimPending", "creating a shared nodeClaim"); err != nil {
+				klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
+				return nil, err
+			}
+			newNodeClaim := nodeclaim.GenerateSharedNodeClaimManifest(c.determineNodeOSDiskSize(wObj), wObj)
+			klog.InfoS("creating shared nodeClaim", "workspace", klog.KObj(wObj), "nodeClaim", klog.KObj(newNodeClaim))
+			if err := nodeclaim.CreateNodeClaim(ctx, newNodeClaim, c.Client); err != nil {
+				return nil, fmt.Errorf("failed to create shared nodeClaim %s: %w", newNodeClaim.Name, err)
+			}
+			nodeClaimObj = *newNodeClaim
+		}
+	}
+
+	_, initialized := lo.Find(nodeClaimObj.GetConditions(), func(condition status.Condition) bool {
+		return condition.Type == karpenterv1.ConditionTypeInitialized && condition.Status == metav1.ConditionTrue
+	})
+	if !initialized {
+		if err := nodeclaim.CheckNodeClaimStatus(ctx, &nodeClaimObj, c.Client); err != nil {
+			return nil, err
+		}
+	}
+	return resources.GetNode(ctx, nodeClaimObj.Status.NodeName, c.Client)
+}
+
+// findSharedNodeClaimWithCapacity returns the first shared nodeClaim that matches the instance type and label
+// selector of the workspace and has enough GPUs left for it, or nil if there is none.
+func (c *WorkspaceReconciler) findSharedNodeClaimWithCapacity(ctx context.Context, wObj *kaitov1beta1.Workspace, nodeClaims []karpenterv1.NodeClaim) (*karpenterv1.NodeClaim, error) {
+	gpuConfig, _ := utils.GetGPUConfigBySKU(wObj.ActiveInstanceType())
+	if gpuConfig == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(wObj.Resource.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	required := sharedGPUCount(wObj)
+
+	for i := range nodeClaims {
+		nodeClaimObj := &nodeClaims[i]
+		if !nodeClaimObj.DeletionTimestamp.IsZero() ||
+			!nodeClaimHasInstanceType(nodeClaimObj, wObj.ActiveInstanceType()) ||
+			!selector.Matches(labels.Set(nodeClaimObj.Labels)) {
+			continue
+		}
+		used, err := c.sharedNodeClaimUsedGPUs(ctx, nodeClaimObj)
+		if err != nil {
+			return nil, err
+		}
+		if gpuConfig.GPUCount-used >= required {
+			return nodeClaimObj, nil
+		}
+	}
+	return nil, nil
+}
+
+// sharedNodeClaimUsedGPUs returns the number of GPUs requested by the workspaces placed on the shared nodeClaim.
+// Workspaces that no longer exist are not counted.
+func (c *WorkspaceReconciler) sharedNodeClaimUsedGPUs(ctx context.Context, nodeClaimObj *karpenterv1.NodeClaim) (int, error) {
+	used := 0
+	for _, key := range nodeclaim.SharedWorkspaces(nodeClaimObj) {
+		namespace, name, _ := strings.Cut(key, "/")
+		wObj := &kaitov1beta1.Workspace{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, wObj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		used += sharedGPUCount(wObj)
+	}
+	return used, nil
+}
+
+// releaseSharedNodeClaims removes the workspace from the shared nodeClaims it is placed on. A shared nodeClaim
+// is deleted once no workspace is placed on it anymore.
+func (c *WorkspaceReconciler) releaseSharedNodeClaims(ctx context.Context, wObj *kaitov1beta1.Workspace) error {
+	nodeClaims, err := nodeclaim.ListSharedNodeClaims(ctx, c.Client)
+	if err != nil {
+		return err
+	}
+	workspaceKey := client.ObjectKeyFromObject(wObj).String()
+
+	for i := range nodeClaims.Items {
+		nodeClaimObj := &nodeClaims.Items[i]
+		workspaces := nodeclaim.SharedWorkspaces(nodeClaimObj)
+		if !lo.Contains(workspaces, workspaceKey) {
+			continue
+		}
+		remaining := lo.Without(workspaces, workspaceKey)
+		if len(remaining) == 0 {
+			if !nodeClaimObj.DeletionTimestamp.IsZero() {
+				continue
+			}
+			klog.InfoS("Deleting shared NodeClaim released by its last workspace", "nodeClaim", nodeClaimObj.Name)
+			if err := c.Delete(ctx, nodeClaimObj); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete shared nodeClaim %s: %w", nodeClaimObj.Name, err)
+			}
+			continue
+		}
+		klog.InfoS("Releasing shared NodeClaim", "nodeClaim", nodeClaimObj.Name, "workspace", klog.KObj(wObj), "remaining", len(remaining))
+		nodeclaim.SetSharedWorkspaces(nodeClaimObj, remaining)
+		if err := c.Update(ctx, nodeClaimObj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to release shared nodeClaim %s: %w", nodeClaimObj.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpenterv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+)
+
+func sharedNodeClaim(name, instanceType, workspaces string) *karpenterv1.NodeClaim {
+	return &karpenterv1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				v1beta1.LabelSharedNode: "true",
+				"apps":                  "test",
+			},
+			Annotations: map[string]string{
+				v1beta1.AnnotationSharedWorkspaces: workspaces,
+			},
+		},
+		Spec: karpenterv1.NodeClaimSpec{
+			Requirements: []karpenterv1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key:      corev1.LabelInstanceTypeStable,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{instanceType},
+					},
+				},
+			},
+		},
+	}
+}
+
+func sharedWorkspace(name string) *v1beta1.Workspace {
+	wObj := test.MockWorkspaceWithPreset.DeepCopy()
+	wObj.Name = name
+	wObj.Resource.PlacementPolicy = v1beta1.PlacementPolicyShared
+	return wObj
+}
+
+func TestFindSharedNodeClaimWithCapacity(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	testcases := map[string]struct {
+		nodeClaims []karpenterv1.NodeClaim
+		expected   string
+	}{
+		"Full shared nodeClaim is skipped": {
+			nodeClaims: []karpenterv1.NodeClaim{
+				*sharedNodeClaim("full", "Standard_NC12s_v3", "kaito/ws-a,kaito/ws-b"),
+				*sharedNodeClaim("free", "Standard_NC12s_v3", "kaito/ws-a"),
+			},
+			expected: "free",
+		},
+		"Workspaces that no longer exist are not counted": {
+			nodeClaims: []karpenterv1.NodeClaim{
+				*sharedNodeClaim("released", "Standard_NC12s_v3", "kaito/ws-a,kaito/gone"),
+			},
+			expected: "released",
+		},
+		"Shared nodeClaim of another instance type is skipped": {
+			nodeClaims: []karpenterv1.NodeClaim{
+				*sharedNodeClaim("other", "Standard_NC24s_v3", ""),
+			},
+			expected: "",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(sharedWorkspace("ws-a"))
+			mockClient.CreateOrUpdateObjectInMap(sharedWorkspace("ws-b"))
+			mockClient.On("Get", mock.IsType(context.Background()), client.ObjectKey{Namespace: "kaito", Name: "gone"}, mock.IsType(&v1beta1.Workspace{}), mock.Anything).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, "gone"))
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			nodeClaimObj, err := reconciler.findSharedNodeClaimWithCapacity(context.Background(), sharedWorkspace("ws-c"), tc.nodeClaims)
+			assert.NilError(t, err)
+			if tc.expected == "" {
+				assert.Assert(t, nodeClaimObj == nil)
+			} else {
+				assert.Equal(t, nodeClaimObj.Name, tc.expected)
+			}
+		})
+	}
+}
+
+func TestReleaseSharedNodeClaims(t *testing.T) {
+	mockClient := test.NewClient()
+	relevantMap := mockClient.CreateMapWithType(&karpenterv1.NodeClaimList{})
+	for _, nodeClaimObj := range []*karpenterv1.NodeClaim{
+		sharedNodeClaim("last", "Standard_NC12s_v3", "kaito/ws-a"),
+		sharedNodeClaim("shared", "Standard_NC12s_v3", "kaito/ws-a,kaito/ws-b"),
+		sharedNodeClaim("unrelated", "Standard_NC12s_v3", "kaito/ws-b"),
+	} {
+		relevantMap[client.ObjectKeyFromObject(nodeClaimObj)] = nodeClaimObj
+	}
+	mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&karpenterv1.NodeClaimList{}), mock.Anything).Return(nil)
+	mockClient.On("Delete", mock.IsType(context.Background()), mock.MatchedBy(func(nodeClaimObj *karpenterv1.NodeClaim) bool {
+		return nodeClaimObj.Name == "last"
+	}), mock.Anything).Return(nil).Once()
+	mockClient.On("Update", mock.IsType(context.Background()), mock.MatchedBy(func(nodeClaimObj *karpenterv1.NodeClaim) bool {
+		return nodeClaimObj.Name == "shared" && nodeClaimObj.Annotations[v1beta1.AnnotationSharedWorkspaces] == "kaito/ws-b"
+	}), mock.Anything).Return(nil).Once()
+
+	reconciler := &WorkspaceReconciler{
+		Client: mockClient,
+		Scheme: test.NewTestScheme(),
+	}
+	assert.NilError(t, reconciler.releaseSharedNodeClaims(context.Background(), sharedWorkspace("ws-a")))
+	mockClient.AssertExpectations(t)
+}
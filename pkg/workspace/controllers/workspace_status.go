@@ -85,3 +85,59 @@ func (c *WorkspaceReconciler) updateStatusNodeListIfNotMatch(ctx context.Context
 	klog.InfoS("updateStatusNodeList", "workspace", klog.KObj(wObj))
 	return c.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, nil, nodeNameList)
 }
+
+func (c *WorkspaceReconciler) updateStatusInstanceTypeIfNotMatch(ctx context.Context, wObj *kaitov1beta1.Workspace, instanceType string) error {
+	if wObj.Status.InstanceType == instanceType {
+		return nil
+	}
+	klog.InfoS("updateStatusInstanceType", "workspace", klog.KObj(wObj), "instanceType", instanceType)
+	err := retry.OnError(retry.DefaultRetry,
+		func(err error) bool {
+			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+		},
+		func() error {
+			// Read the latest version to avoid update conflict.
+			latest := &kaitov1beta1.Workspace{}
+			if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				return nil
+			}
+			latest.Status.InstanceType = instanceType
+			return c.Client.Status().Update(ctx, latest)
+		})
+	if err != nil {
+		return err
+	}
+	wObj.Status.InstanceType = instanceType
+	return nil
+}
+
+func (c *WorkspaceReconciler) updateStatusEmbeddingDimensionIfNotMatch(ctx context.Context, wObj *kaitov1beta1.Workspace, embeddingDimension int32) error {
+	if wObj.Status.EmbeddingDimension == embeddingDimension {
+		return nil
+	}
+	klog.InfoS("updateStatusEmbeddingDimension", "workspace", klog.KObj(wObj), "embeddingDimension", embeddingDimension)
+	err := retry.OnError(retry.DefaultRetry,
+		func(err error) bool {
+			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+		},
+		func() error {
+			// Read the latest version to avoid update conflict.
+			latest := &kaitov1beta1.Workspace{}
+			if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				return nil
+			}
+			latest.Status.EmbeddingDimension = embeddingDimension
+			return c.Client.Status().Update(ctx, latest)
+		})
+	if err != nil {
+		return err
+	}
+	wObj.Status.EmbeddingDimension = embeddingDimension
+	return nil
+}
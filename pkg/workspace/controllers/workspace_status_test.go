@@ -181,3 +181,36 @@ func TestUpdateStatusConditionIfNotMatch(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestUpdateStatusEmbeddingDimensionIfNotMatch(t *testing.T) {
+	t.Run("Should not update when embedding dimension matches", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
+		workspace.Status.EmbeddingDimension = 384
+
+		err := reconciler.updateStatusEmbeddingDimensionIfNotMatch(context.Background(), workspace, 384)
+		assert.Nil(t, err)
+		mockClient.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should update when embedding dimension does not match", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
+		}
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
+
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1beta1.Workspace{}), mock.Anything).Return(nil)
+
+		err := reconciler.updateStatusEmbeddingDimensionIfNotMatch(context.Background(), workspace, 1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1024), workspace.Status.EmbeddingDimension)
+		mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	})
+}
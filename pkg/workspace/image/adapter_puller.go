@@ -0,0 +1,77 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"bytes"
+	_ "embed"
+	"strconv"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AdapterPullerContainerName is the name of the sidecar keeping the adapters of an inference pod pulled.
+const AdapterPullerContainerName = "adapter-puller"
+
+var (
+	//go:embed adapter_puller.sh
+	adapterPullerSHTextData string
+
+	adapterPullerSHTemplate *template.Template
+)
+
+func init() {
+	t, err := template.New("adapter_puller.sh").Option("missingkey=zero").Parse(adapterPullerSHTextData)
+	if err != nil {
+		panic(err)
+	}
+
+	adapterPullerSHTemplate = t
+}
+
+func renderAdapterPullerSH(listDir string, volDir string, interval time.Duration) string {
+	data := map[string]string{
+		"listDir":  listDir,
+		"volDir":   volDir,
+		"interval": strconv.Itoa(int(interval.Seconds())),
+		// The puller script is valid shell as is, its arguments override the defaults of the template.
+		"pullerSH": pullerSHTextData,
+	}
+
+	var buf bytes.Buffer
+	if err := adapterPullerSHTemplate.Execute(&buf, data); err != nil {
+		panic(err)
+	}
+
+	return buf.String()
+}
+
+// NewAdapterPullerContainer returns a sidecar that keeps the adapters listed in listDir pulled into
+// outputDirectory, checking the list every interval. Each line of the list is the name of an adapter followed
+// by its image.
+func NewAdapterPullerContainer(listDir string, outputDirectory string, interval time.Duration) *corev1.Container {
+	return &corev1.Container{
+		Name:  AdapterPullerContainerName,
+		Image: "quay.io/skopeo/stable:v1.18.0-immutable",
+		Command: []string{
+			"/bin/sh",
+			"-c",
+		},
+		Args: []string{
+			renderAdapterPullerSH(listDir, outputDirectory, interval),
+		},
+	}
+}
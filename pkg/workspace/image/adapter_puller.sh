@@ -0,0 +1,83 @@
+#!/bin/sh
+set -e
+
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Keeps the adapters listed in the adapter list, one `<name> <image>` per line, pulled into the volume
+# directory. Adapters are pulled into a hidden directory and renamed once complete, so that the inference
+# server only sees complete adapters. The image of each adapter is recorded in the hidden file
+# `.<name>.image` next to it, and the adapter is pulled again when its image changes. Adapters no longer
+# listed are removed.
+
+LIST_DIR="${1}"
+[ ! -z "${LIST_DIR}" ] || LIST_DIR='{{ .listDir }}'
+
+VOL_DIR="${2}"
+[ ! -z "${VOL_DIR}" ] || VOL_DIR='{{ .volDir }}'
+
+INTERVAL="${3}"
+[ ! -z "${INTERVAL}" ] || INTERVAL='{{ .interval }}'
+
+# Replicas spreading the adapters only keep the adapters of their own list.
+LIST="${LIST_DIR}/adapters"
+[ -z "${POD_INDEX}" ] || LIST="${LIST_DIR}/replica-${POD_INDEX}"
+
+PULLER="$(mktemp)"
+cat > "${PULLER}" <<'PULLER_EOF'
+{{ .pullerSH }}
+PULLER_EOF
+
+reconcile() {
+    # The list is missing until the ConfigMap is created, keep the pulled adapters meanwhile.
+    [ -e "${LIST}" ] || return 0
+
+    local PULLING_DIR="${VOL_DIR}/.pulling"
+    local WORK_DIR="${VOL_DIR}/.work"
+
+    while read -r NAME IMG_REF
+    do
+        [ ! -z "${NAME}" ] || continue
+        local MARKER="${VOL_DIR}/.${NAME}.image"
+        [ ! -e "${VOL_DIR}/${NAME}" ] || [ "$(cat "${MARKER}" 2> /dev/null)" != "${IMG_REF}" ] || continue
+
+        rm -rf "${PULLING_DIR}" "${WORK_DIR}"
+        mkdir -p "${PULLING_DIR}" "${WORK_DIR}"
+        if TMPDIR="${WORK_DIR}" sh "${PULLER}" "${IMG_REF}" "${PULLING_DIR}/${NAME}" < /dev/null
+        then
+            rm -rf "${VOL_DIR}/${NAME}"
+            mv "${PULLING_DIR}/${NAME}" "${VOL_DIR}/${NAME}"
+            echo "${IMG_REF}" > "${MARKER}.tmp"
+            mv "${MARKER}.tmp" "${MARKER}"
+            echo "pulled adapter ${NAME} from ${IMG_REF}"
+        else
+            echo "failed to pull adapter ${NAME} from ${IMG_REF}" >&2
+        fi
+    done < "${LIST}"
+    rm -rf "${PULLING_DIR}" "${WORK_DIR}"
+
+    for ADAPTER_DIR in "${VOL_DIR}"/*
+    do
+        [ -d "${ADAPTER_DIR}" ] || continue
+        NAME="$(basename "${ADAPTER_DIR}")"
+        cut -d ' ' -f 1 "${LIST}" | grep -qxF "${NAME}" && continue
+        rm -rf "${ADAPTER_DIR}" "${VOL_DIR}/.${NAME}.image"
+        echo "removed adapter ${NAME}"
+    done
+}
+
+while true
+do
+    reconcile || echo "failed to reconcile adapters" >&2
+    sleep "${INTERVAL}"
+done
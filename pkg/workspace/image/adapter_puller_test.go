@@ -0,0 +1,77 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+var _ = Describe("AdapterPuller", func() {
+	Context("embed", func() {
+		It("initializes the adapter puller text data", func() {
+			Expect(adapterPullerSHTextData).NotTo(BeEmpty())
+		})
+
+		It("uses sh as the interpreter", func() {
+			Expect(adapterPullerSHTextData).To(HavePrefix("#!/bin/sh\n"))
+		})
+	})
+
+	Context("init", func() {
+		It("instantiates the adapter puller template", func() {
+			Expect(adapterPullerSHTemplate).NotTo(BeNil())
+		})
+	})
+
+	Context("renderAdapterPullerSH", func() {
+		It("renders the adapter puller script", func() {
+			var (
+				listDir = "/tmp/" + rand.String(8)
+				volDir  = "/tmp/" + rand.String(8)
+			)
+
+			ret := renderAdapterPullerSH(listDir, volDir, time.Minute)
+			Expect(ret).To(ContainSubstring("\n" + `[ ! -z "${LIST_DIR}" ] || LIST_DIR='` + listDir + `'` + "\n"))
+			Expect(ret).To(ContainSubstring("\n" + `[ ! -z "${VOL_DIR}" ] || VOL_DIR='` + volDir + `'` + "\n"))
+			Expect(ret).To(ContainSubstring("\n" + `[ ! -z "${INTERVAL}" ] || INTERVAL='60'` + "\n"))
+		})
+
+		It("embeds the puller script untouched", func() {
+			ret := renderAdapterPullerSH("/tmp/list", "/tmp/vol", time.Minute)
+			Expect(ret).To(ContainSubstring("<<'PULLER_EOF'\n" + pullerSHTextData + "\nPULLER_EOF\n"))
+		})
+	})
+
+	Context("NewAdapterPullerContainer", func() {
+		It("returns the expected container", func() {
+			var (
+				listDir = "/tmp/" + rand.String(8)
+				volDir  = "/tmp/" + rand.String(8)
+			)
+
+			adapterPullerSH := renderAdapterPullerSH(listDir, volDir, 10*time.Second)
+
+			ret := NewAdapterPullerContainer(listDir, volDir, 10*time.Second)
+			Expect(ret).NotTo(BeNil())
+			Expect(ret.Name).To(Equal(AdapterPullerContainerName))
+			Expect(ret.Image).To(Equal("quay.io/skopeo/stable:v1.18.0-immutable"))
+			Expect(ret.Command).To(Equal([]string{"/bin/sh", "-c"}))
+			Expect(ret.Args).To(Equal([]string{adapterPullerSH}))
+		})
+	})
+})
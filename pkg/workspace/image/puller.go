@@ -39,7 +39,9 @@ func init() {
 	pullerSHTemplate = t
 }
 
-func renderPullerSH(imgRef string, volDir string) string {
+// NormalizeImageRef returns the fully qualified form of the image reference, or the image reference itself
+// when it cannot be normalized.
+func NormalizeImageRef(imgRef string) string {
 	normalizedImgRef, err := reference.ParseDockerRef(imgRef)
 	if err != nil {
 		log.Printf("failed to normalize image reference `%s`: %v", imgRef, err)
@@ -47,9 +49,12 @@ func renderPullerSH(imgRef string, volDir string) string {
 	if normalizedImgRef != nil {
 		imgRef = normalizedImgRef.String()
 	}
+	return imgRef
+}
 
+func renderPullerSH(imgRef string, volDir string) string {
 	data := map[string]string{
-		"imgRef": imgRef,
+		"imgRef": NormalizeImageRef(imgRef),
 		"volDir": volDir,
 	}
 
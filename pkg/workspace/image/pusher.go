@@ -42,6 +42,12 @@ func init() {
 }
 
 func renderPusherSH(volDir string, imgRef string, annotationsData map[string]map[string]string, sentinelPath *string) string {
+	return renderDirectoryPusherSH(volDir, imgRef, annotationsData, sentinelPath, "", "")
+}
+
+// renderDirectoryPusherSH renders the pusher script for the given paths of volDir, all of it when
+// layerPaths is ".". Nothing is pushed if skipPath exists when the sentinel appears.
+func renderDirectoryPusherSH(volDir string, imgRef string, annotationsData map[string]map[string]string, sentinelPath *string, layerPaths string, skipPath string) string {
 	normalizedImgRef, err := reference.ParseDockerRef(imgRef)
 	if err != nil {
 		log.Printf("failed to normalize image reference `%s`: %v", imgRef, err)
@@ -55,6 +61,8 @@ func renderPusherSH(volDir string, imgRef string, annotationsData map[string]map
 		"imgRef":          imgRef,
 		"annotationsData": "{}",
 		"sentinelPath":    path.Join(volDir, "fine_tuning_completed.txt"),
+		"layerPaths":      layerPaths,
+		"skipPath":        skipPath,
 	}
 
 	if annotationsData != nil {
@@ -79,6 +87,16 @@ func renderPusherSH(volDir string, imgRef string, annotationsData map[string]map
 }
 
 func NewPusherContainer(inputDirectory string, outputImage string, annotationsData map[string]map[string]string, sentinelPath *string) *corev1.Container {
+	return newPusherContainer(renderPusherSH(inputDirectory, outputImage, annotationsData, sentinelPath))
+}
+
+// NewDirectoryPusherContainer returns a pusher that pushes the whole content of inputDirectory once sentinelPath
+// exists. It skips the push if skipPath exists, e.g. because the image has been found in the registry.
+func NewDirectoryPusherContainer(inputDirectory string, outputImage string, annotationsData map[string]map[string]string, sentinelPath string, skipPath string) *corev1.Container {
+	return newPusherContainer(renderDirectoryPusherSH(inputDirectory, outputImage, annotationsData, &sentinelPath, ".", skipPath))
+}
+
+func newPusherContainer(pusherSH string) *corev1.Container {
 	return &corev1.Container{
 		Name:  "pusher",
 		Image: "ghcr.io/oras-project/oras:v1.2.2",
@@ -87,7 +105,7 @@ func NewPusherContainer(inputDirectory string, outputImage string, annotationsDa
 			"-c",
 		},
 		Args: []string{
-			renderPusherSH(inputDirectory, outputImage, annotationsData, sentinelPath),
+			pusherSH,
 		},
 	}
 }
@@ -28,6 +28,11 @@ ANNOTATIONS_DATA="${ANNOTATIONS_DATA:-{}}"
 [ ! -z '{{ "" }}' ] || SENTINEL_PATH='{{ .sentinelPath }}'
 SENTINEL_PATH="${SENTINEL_PATH:-.}"
 
+[ ! -z '{{ "" }}' ] || LAYER_PATHS='{{ .layerPaths }}'
+LAYER_PATHS="${LAYER_PATHS:-adapter_config.json adapter_model.safetensors}"
+
+[ ! -z '{{ "" }}' ] || SKIP_PATH='{{ .skipPath }}'
+
 #{{`
 
 wait() {
@@ -41,7 +46,10 @@ mklayer() {
     local DATA_DIR="${TMPDIR}/data"
     mkdir -p "${DATA_DIR}"
 
-    cp -R "${VOL_DIR}/adapter_config.json" "${VOL_DIR}/adapter_model.safetensors" "${DATA_DIR}"
+    for LAYER_PATH in ${LAYER_PATHS}
+    do
+        cp -R "${VOL_DIR}/${LAYER_PATH}" "${DATA_DIR}"
+    done
 
     local TAR_LAYER_PATH="${TMPDIR}/layer.tar"
 
@@ -87,9 +95,12 @@ resume() {
 #`}}
 
 wait
-mklayer
-mkconfig
-mkannotations
-mklayout
-push
+if [ ! -e "${SKIP_PATH}" ]
+then
+    mklayer
+    mkconfig
+    mkannotations
+    mklayout
+    push
+fi
 resume
@@ -116,4 +116,23 @@ var _ = Describe("Pusher", func() {
 			Expect(ret.Args).To(Equal([]string{pusherSH}))
 		})
 	})
+
+	Context("NewDirectoryPusherContainer", func() {
+		It("pushes the whole directory unless the skip path exists", func() {
+			var (
+				volDir       = "/tmp/" + rand.String(8)
+				imgRef       = "docker.io/library/scratch:" + rand.String(8)
+				sentinelPath = "/tmp/" + rand.String(8)
+				skipPath     = "/tmp/" + rand.String(8)
+			)
+
+			ret := NewDirectoryPusherContainer(volDir, imgRef, nil, sentinelPath, skipPath)
+			Expect(ret).NotTo(BeNil())
+			Expect(ret.Name).To(Equal("pusher"))
+			Expect(ret.Args).To(HaveLen(1))
+			Expect(ret.Args[0]).To(ContainSubstring("\n" + `[ ! -z '' ] || SENTINEL_PATH='` + sentinelPath + `'` + "\n"))
+			Expect(ret.Args[0]).To(ContainSubstring("\n" + `[ ! -z '' ] || LAYER_PATHS='.'` + "\n"))
+			Expect(ret.Args[0]).To(ContainSubstring("\n" + `[ ! -z '' ] || SKIP_PATH='` + skipPath + `'` + "\n"))
+		})
+	})
 })
@@ -0,0 +1,170 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+)
+
+const (
+	// AdapterListKey is the key of the adapter list shared by every replica in the adapter ConfigMap. Replicas
+	// spreading the adapters use the list under the key "replica-<POD_INDEX>" instead.
+	AdapterListKey = "adapters"
+
+	// AdapterListMountPath is where the adapter ConfigMap is mounted in the adapter puller sidecar.
+	AdapterListMountPath = "/mnt/adapter-list"
+
+	// AdapterPullInterval is the interval at which the adapter puller sidecar checks the adapter list.
+	AdapterPullInterval = 10 * time.Second
+
+	// EnvAllowRuntimeLoRAUpdating enables the vLLM endpoints loading and unloading LoRA adapters at runtime.
+	EnvAllowRuntimeLoRAUpdating = "VLLM_ALLOW_RUNTIME_LORA_UPDATING"
+)
+
+// AdapterConfigMapName returns the name of the ConfigMap listing the adapters pulled by the inference pods.
+func AdapterConfigMapName(workspaceObj *v1beta1.Workspace) string {
+	return workspaceObj.Name + "-adapters"
+}
+
+// runtimeAdapterLoading reports whether the adapters are pulled by a sidecar and loaded into the running inference
+// server, so that changing the adapters does not restart the pods. vLLM loads LoRA adapters at runtime, except
+// with multi-node inference whose workers would not see the adapters pulled on the leader.
+func runtimeAdapterLoading(ctx *generator.WorkspaceGeneratorContext, numNodes int) bool {
+	return len(ctx.Workspace.Inference.Adapters) > 0 &&
+		v1beta1.GetWorkspaceRuntimeName(ctx.Workspace) == pkgmodel.RuntimeNameVLLM &&
+		!shouldUseDistributedInference(ctx, numNodes)
+}
+
+// GenerateAdapterConfigMap returns the ConfigMap listing the adapters pulled by the adapter puller sidecar of the
+// inference pods, nil if the adapters of the workspace are not loaded at runtime. Each line of a list is the name
+// of an adapter followed by its image.
+func GenerateAdapterConfigMap(ctx context.Context, workspaceObj *v1beta1.Workspace, model pkgmodel.Model, kubeClient client.Client) *corev1.ConfigMap {
+	if workspaceObj.Inference == nil || workspaceObj.Inference.Preset == nil || len(workspaceObj.Inference.Adapters) == 0 {
+		return nil
+	}
+	gctx := &generator.WorkspaceGeneratorContext{
+		Ctx:        ctx,
+		KubeClient: kubeClient,
+		Workspace:  workspaceObj,
+		Model:      model,
+	}
+	_, _, numNodes := getInferenceGPUConfig(ctx, workspaceObj, model, kubeClient)
+	if !runtimeAdapterLoading(gctx, numNodes) {
+		return nil
+	}
+
+	images := map[string]string{}
+	names := make([]string, 0, len(workspaceObj.Inference.Adapters))
+	for _, adapter := range workspaceObj.Inference.Adapters {
+		images[adapter.Source.Name] = image.NormalizeImageRef(adapter.Source.Image)
+		names = append(names, adapter.Source.Name)
+	}
+	list := func(adapters []string) string {
+		var b strings.Builder
+		for _, name := range adapters {
+			fmt.Fprintf(&b, "%s %s\n", name, images[name])
+		}
+		return b.String()
+	}
+
+	data := map[string]string{}
+	if placement := adapterPlacement(gctx, numNodes); placement != nil {
+		for i, adapters := range placement {
+			data[fmt.Sprintf("replica-%d", i)] = list(adapters)
+		}
+	} else {
+		data[AdapterListKey] = list(names)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AdapterConfigMapName(workspaceObj),
+			Namespace: workspaceObj.Namespace,
+			Labels: map[string]string{
+				v1beta1.LabelWorkspaceName: workspaceObj.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(workspaceObj, v1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Data: data,
+	}
+}
+
+// ListedAdapters returns the names of the adapters the adapter ConfigMap lists for the pod.
+func ListedAdapters(configMap *corev1.ConfigMap, pod *corev1.Pod) []string {
+	list, found := configMap.Data[AdapterListKey]
+	if index, ok := pod.Labels[appsv1.PodIndexLabel]; !found && ok {
+		list = configMap.Data["replica-"+index]
+	}
+	var adapters []string
+	for _, line := range strings.Split(list, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			adapters = append(adapters, fields[0])
+		}
+	}
+	return adapters
+}
+
+// SetAdapterPullerSidecar adds a sidecar keeping the adapters listed in the adapter ConfigMap pulled, and lets the
+// inference server load and unload adapters at runtime. The pod template does not depend on the adapters besides
+// their pull secrets, so that changing the adapters does not restart the pods.
+func SetAdapterPullerSidecar(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	adapterVolume, adapterVolumeMount := utils.ConfigAdapterVolume()
+	spec.Volumes = append(spec.Volumes, adapterVolume)
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, adapterVolumeMount)
+		if spec.Containers[i].Name == ctx.Workspace.Name {
+			spec.Containers[i].Env = append(spec.Containers[i].Env, corev1.EnvVar{Name: EnvAllowRuntimeLoRAUpdating, Value: "True"})
+		}
+	}
+
+	listVolume := corev1.Volume{
+		Name: "adapter-list",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: AdapterConfigMapName(ctx.Workspace)},
+				Optional:             lo.ToPtr(true),
+			},
+		},
+	}
+	listVolumeMount := corev1.VolumeMount{Name: listVolume.Name, MountPath: AdapterListMountPath, ReadOnly: true}
+
+	var imagePullSecrets []string
+	for _, adapter := range ctx.Workspace.Inference.Adapters {
+		imagePullSecrets = append(imagePullSecrets, adapter.Source.ImagePullSecrets...)
+	}
+	secretVolume, secretVolumeMount := utils.ConfigImagePullSecretVolume("inference-adapters", lo.Uniq(imagePullSecrets))
+
+	puller := image.NewAdapterPullerContainer(AdapterListMountPath, utils.DefaultAdapterVolumePath, AdapterPullInterval)
+	puller.VolumeMounts = []corev1.VolumeMount{adapterVolumeMount, listVolumeMount, secretVolumeMount}
+	spec.Containers = append(spec.Containers, *puller)
+	spec.Volumes = append(spec.Volumes, listVolume, secretVolume)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestGeneratePresetInferenceWithRuntimeAdapterLoading(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	workspace.Inference.Adapters = []v1beta1.AdapterSpec{
+		{Source: &v1beta1.DataSource{Name: "adapter-a", Image: "fake.kaito.com/adapter-a:0.0.1", ImagePullSecrets: []string{"secret-a"}}},
+		{Source: &v1beta1.DataSource{Name: "adapter-b", Image: "fake.kaito.com/adapter-b:0.0.1", ImagePullSecrets: []string{"secret-a", "secret-b"}}},
+	}
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	podSpec := createdObject.(*appsv1.Deployment).Spec.Template.Spec
+
+	// The adapters are pulled by a sidecar instead of init containers.
+	assert.False(t, lo.ContainsBy(podSpec.InitContainers, func(c corev1.Container) bool { return c.Name == "puller-adapter-a" }))
+	puller, found := lo.Find(podSpec.Containers, func(c corev1.Container) bool { return c.Name == image.AdapterPullerContainerName })
+	assert.True(t, found)
+	assert.Equal(t, []string{"adapter-volume", "adapter-list", "docker-config-inference-adapters"},
+		lo.Map(puller.VolumeMounts, func(m corev1.VolumeMount, _ int) string { return m.Name }))
+	secretVolume, found := lo.Find(podSpec.Volumes, func(v corev1.Volume) bool { return v.Name == "docker-config-inference-adapters" })
+	assert.True(t, found)
+	assert.Equal(t, []string{"secret-a", "secret-b"}, lo.Map(secretVolume.Projected.Sources, func(p corev1.VolumeProjection, _ int) string { return p.Secret.Name }))
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: EnvAllowRuntimeLoRAUpdating, Value: "True"})
+	// The inference proxy loads the listed adapters.
+	proxy, found := lo.Find(podSpec.Containers, func(c corev1.Container) bool { return c.Name == manifests.InferenceProxyContainerName })
+	assert.True(t, found)
+	assert.Subset(t, proxy.Args, []string{"--adapter-list-dir=/mnt/adapter-list", "--adapter-dir=/mnt/adapter"})
+	assert.Subset(t, lo.Map(proxy.VolumeMounts, func(m corev1.VolumeMount, _ int) string { return m.Name }), []string{"adapter-volume", "adapter-list"})
+
+	// Changing the adapters does not change the pod template.
+	updated := workspace.DeepCopy()
+	updated.Inference.Adapters[1].Source.Name = "adapter-c"
+	updated.Inference.Adapters[1].Source.Image = "fake.kaito.com/adapter-c:0.0.1"
+	updatedObject, err := GeneratePresetInference(context.TODO(), updated, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	assert.Equal(t, podSpec, updatedObject.(*appsv1.Deployment).Spec.Template.Spec)
+
+	configMap := GenerateAdapterConfigMap(context.TODO(), updated, model, mockClient)
+	assert.Equal(t, "testWorkspace-adapters", configMap.Name)
+	assert.Equal(t, map[string]string{
+		AdapterListKey: "adapter-a fake.kaito.com/adapter-a:0.0.1\nadapter-c fake.kaito.com/adapter-c:0.0.1\n",
+	}, configMap.Data)
+
+	// Other runtimes pull the adapters before the inference server starts.
+	updated.Annotations = map[string]string{v1beta1.AnnotationWorkspaceRuntime: "transformers"}
+	assert.Nil(t, GenerateAdapterConfigMap(context.TODO(), updated, model, mockClient))
+}
+
+func TestListedAdapters(t *testing.T) {
+	configMap := &corev1.ConfigMap{Data: map[string]string{
+		"replica-0": "adapter-a fake.kaito.com/adapter-a:latest\nadapter-b fake.kaito.com/adapter-b:latest\n",
+		"replica-1": "",
+	}}
+	pod := func(index string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{appsv1.PodIndexLabel: index}}}
+	}
+	assert.Equal(t, []string{"adapter-a", "adapter-b"}, ListedAdapters(configMap, pod("0")))
+	assert.Empty(t, ListedAdapters(configMap, pod("1")))
+
+	// Every replica pulls the shared list when the adapters are not spread.
+	configMap.Data = map[string]string{AdapterListKey: "adapter-a fake.kaito.com/adapter-a:latest\n"}
+	assert.Equal(t, []string{"adapter-a"}, ListedAdapters(configMap, &corev1.Pod{}))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+)
+
+// PlaceAdapters assigns each adapter to the replicasPerAdapter replicas ranked highest for the adapter name by
+// rendezvous hashing, so that the replicas of an adapter do not depend on the other adapters nor on their order.
+// A replica loads at most MaxAdaptersNumber adapters, the adapters ranking a full replica go to the next ones.
+func PlaceAdapters(adapters []string, replicas, replicasPerAdapter int) [][]string {
+	placement := make([][]string, replicas)
+	replicasPerAdapter = min(replicasPerAdapter, replicas)
+	for _, adapter := range slices.Sorted(slices.Values(adapters)) {
+		ranked := make([]int, replicas)
+		for replica := range ranked {
+			ranked[replica] = replica
+		}
+		slices.SortFunc(ranked, func(a, b int) int {
+			return cmp.Compare(adapterReplicaScore(adapter, b), adapterReplicaScore(adapter, a))
+		})
+		available, full := lo.FilterReject(ranked, func(replica int, _ int) bool {
+			return len(placement[replica]) < v1beta1.MaxAdaptersNumber
+		})
+		for _, replica := range append(available, full...)[:replicasPerAdapter] {
+			placement[replica] = append(placement[replica], adapter)
+		}
+	}
+	return placement
+}
+
+// adapterReplicaScore returns the rendezvous hashing score of the replica for the adapter.
+func adapterReplicaScore(adapter string, replica int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(adapter + "/" + strconv.Itoa(replica)))
+	return h.Sum64()
+}
+
+// GetAdapterPlacement returns the adapters assigned to each replica of the preset inference workload when the
+// adapters are spread across the replicas, nil if every replica loads every adapter. The replicas are the pods
+// of the StatefulSet of the workload, in the order of their index.
+func GetAdapterPlacement(ctx context.Context, workspaceObj *v1beta1.Workspace, model pkgmodel.Model, kubeClient client.Client) [][]string {
+	if !workspaceObj.Inference.AdapterPlacement.IsSpread() {
+		return nil
+	}
+	gctx := &generator.WorkspaceGeneratorContext{
+		Ctx:        ctx,
+		KubeClient: kubeClient,
+		Workspace:  workspaceObj,
+		Model:      model,
+	}
+	_, _, numNodes := getInferenceGPUConfig(ctx, workspaceObj, model, kubeClient)
+	return adapterPlacement(gctx, numNodes)
+}
+
+// adapterPlacement returns the placement of the adapters when the model needs numNodes nodes. With the Spread
+// policy, every node of the workspace runs a replica of the model, unless the model is served by multi-node
+// inference, whose single replica loads every adapter.
+func adapterPlacement(ctx *generator.WorkspaceGeneratorContext, numNodes int) [][]string {
+	workspaceObj := ctx.Workspace
+	replicas := lo.FromPtrOr(workspaceObj.Resource.Count, 1)
+	if !workspaceObj.Inference.AdapterPlacement.IsSpread() || len(workspaceObj.Inference.Adapters) == 0 ||
+		v1beta1.GetWorkspaceRuntimeName(workspaceObj) != pkgmodel.RuntimeNameVLLM ||
+		replicas <= 1 || shouldUseDistributedInference(ctx, numNodes) {
+		return nil
+	}
+	adapters := make([]string, 0, len(workspaceObj.Inference.Adapters))
+	for _, adapter := range workspaceObj.Inference.Adapters {
+		adapters = append(adapters, adapter.Source.Name)
+	}
+	return PlaceAdapters(adapters, replicas, workspaceObj.Inference.AdapterPlacement.GetReplicasPerAdapter())
+}
+
+// SetAdapterPlacement passes the index of the replica to the adapter puller sidecar, which only pulls the adapters
+// listed for its replica in the adapter ConfigMap, so that the inference proxy only loads them.
+func SetAdapterPlacement(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	envVar := corev1.EnvVar{
+		Name: "POD_INDEX",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: fmt.Sprintf("metadata.labels['%s']", appsv1.PodIndexLabel),
+			},
+		},
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == image.AdapterPullerContainerName {
+			spec.Containers[i].Env = append(spec.Containers[i].Env, envVar)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+)
+
+func TestPlaceAdapters(t *testing.T) {
+	testcases := map[string]struct {
+		adapters           []string
+		replicas           int
+		replicasPerAdapter int
+		expected           [][]string
+	}{
+		"One replica per adapter": {
+			adapters:           []string{"a", "b", "c", "d"},
+			replicas:           3,
+			replicasPerAdapter: 1,
+			expected:           [][]string{{"c"}, {"b"}, {"a", "d"}},
+		},
+		"Two replicas per adapter": {
+			adapters:           []string{"a", "b", "c"},
+			replicas:           3,
+			replicasPerAdapter: 2,
+			expected:           [][]string{{"a", "b", "c"}, {"b", "c"}, {"a"}},
+		},
+		"More replicas per adapter than replicas": {
+			adapters:           []string{"a"},
+			replicas:           2,
+			replicasPerAdapter: 3,
+			expected:           [][]string{{"a"}, {"a"}},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PlaceAdapters(tc.adapters, tc.replicas, tc.replicasPerAdapter))
+		})
+	}
+}
+
+func TestPlaceAdaptersIsStable(t *testing.T) {
+	adapters := make([]string, 0, 20)
+	for i := range 20 {
+		adapters = append(adapters, fmt.Sprintf("adapter-%d", i))
+	}
+	replicasOf := func(placement [][]string) map[string][]int {
+		replicas := map[string][]int{}
+		for replica, names := range placement {
+			for _, name := range names {
+				replicas[name] = append(replicas[name], replica)
+			}
+		}
+		return replicas
+	}
+	placement := replicasOf(PlaceAdapters(adapters[:8], 4, 2))
+	for _, name := range adapters[:8] {
+		assert.Len(t, placement[name], 2, "adapter %s", name)
+	}
+
+	// Reordering the adapters, or adding and removing other adapters, does not move an adapter.
+	assert.Equal(t, placement, replicasOf(PlaceAdapters(lo.Reverse(slices.Clone(adapters[:8])), 4, 2)))
+	changed := replicasOf(PlaceAdapters(append(slices.Clone(adapters[3:8]), "adapter-new"), 4, 2))
+	for _, name := range adapters[3:8] {
+		assert.Equal(t, placement[name], changed[name], "adapter %s", name)
+	}
+
+	// A replica loads at most MaxAdaptersNumber adapters, even if more adapters rank it first.
+	for replica, names := range PlaceAdapters(adapters, 2, 1) {
+		assert.LessOrEqual(t, len(names), v1beta1.MaxAdaptersNumber, "replica %d", replica)
+	}
+}
+
+func TestGeneratePresetInferenceWithAdapterPlacement(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	workspace.Resource.Count = lo.ToPtr(2)
+	workspace.Inference.AdapterPlacement = &v1beta1.AdapterPlacementSpec{Policy: v1beta1.AdapterPlacementPolicySpread}
+	for _, name := range []string{"adapter-a", "adapter-b", "adapter-c"} {
+		workspace.Inference.Adapters = append(workspace.Inference.Adapters, v1beta1.AdapterSpec{
+			Source: &v1beta1.DataSource{Name: name, Image: "fake.kaito.com/" + name},
+		})
+	}
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	statefulSet, ok := createdObject.(*appsv1.StatefulSet)
+	if !ok {
+		t.Fatalf("expected the replicas spreading the adapters to be a StatefulSet, got %T", createdObject)
+	}
+	// Every node runs a replica, although the model fits on a single node.
+	assert.Equal(t, int32(2), *statefulSet.Spec.Replicas)
+
+	// Each replica pulls the adapters listed for its index.
+	puller, found := lo.Find(statefulSet.Spec.Template.Spec.Containers, func(c corev1.Container) bool { return c.Name == image.AdapterPullerContainerName })
+	assert.True(t, found)
+	assert.True(t, lo.ContainsBy(puller.Env, func(e corev1.EnvVar) bool { return e.Name == "POD_INDEX" }))
+	proxy, found := lo.Find(statefulSet.Spec.Template.Spec.Containers, func(c corev1.Container) bool { return c.Name == manifests.InferenceProxyContainerName })
+	assert.True(t, found)
+	assert.True(t, lo.ContainsBy(proxy.Env, func(e corev1.EnvVar) bool { return e.Name == "POD_INDEX" }))
+	configMap := GenerateAdapterConfigMap(context.TODO(), workspace, model, mockClient)
+	assert.Equal(t, map[string]string{
+		"replica-0": "adapter-a fake.kaito.com/adapter-a:latest\nadapter-c fake.kaito.com/adapter-c:latest\n",
+		"replica-1": "adapter-b fake.kaito.com/adapter-b:latest\n",
+	}, configMap.Data)
+
+	assert.Equal(t, [][]string{{"adapter-a", "adapter-c"}, {"adapter-b"}}, GetAdapterPlacement(context.TODO(), workspace, model, mockClient))
+
+	// Every replica loads every adapter by default.
+	workspace.Inference.AdapterPlacement = nil
+	createdObject, err = GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	if _, ok := createdObject.(*appsv1.Deployment); !ok {
+		t.Errorf("expected a Deployment without adapter placement, got %T", createdObject)
+	}
+	assert.Nil(t, GetAdapterPlacement(context.TODO(), workspace, model, mockClient))
+}
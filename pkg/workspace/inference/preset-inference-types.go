@@ -28,6 +28,11 @@ const (
 	DefaultVLLMMultiNodeHealthCheckCommand = "python3 /workspace/vllm/multi-node-health-check.py"
 	DefaultVLLMCommand                     = "python3 /workspace/vllm/inference_api.py"
 	DefaultTransformersMainFile            = "/workspace/tfs/inference_api.py"
+	DefaultSGLangCommand                   = "python3 /workspace/sglang/inference_api.py"
+	DefaultTensorRTLLMCommand              = "trtllm-serve --host 0.0.0.0 --port 5000"
+	DefaultTensorRTLLMEngineBuildCommand   = "python3 /workspace/tensorrt-llm/build_engine.py"
+	DefaultLlamaCppCommand                 = "/app/llama-server --host 0.0.0.0 --port 5000"
+	DefaultLlamaCppImage                   = "ghcr.io/ggml-org/llama.cpp:server"
 )
 
 var (
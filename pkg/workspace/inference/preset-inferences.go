@@ -16,6 +16,7 @@ package inference
 import (
 	"context"
 	"fmt"
+	"path"
 	"strconv"
 
 	"github.com/samber/lo"
@@ -35,6 +36,7 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/generator"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	metadata "github.com/kaito-project/kaito/presets/workspace/models"
 )
@@ -79,12 +81,23 @@ var (
 			Operator: corev1.TolerationOpExists,
 			Key:      resources.CapacityNvidiaGPU,
 		},
+		{
+			Effect:   corev1.TaintEffectNoSchedule,
+			Operator: corev1.TolerationOpExists,
+			Key:      resources.CapacityAMDGPU,
+		},
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Value:    consts.GPUString,
 			Key:      consts.SKUString,
 			Operator: corev1.TolerationOpEqual,
 		},
+		{
+			Effect:   corev1.TaintEffectNoSchedule,
+			Value:    consts.AzureSpotPriority,
+			Key:      consts.LabelAzureScaleSetPriority,
+			Operator: corev1.TolerationOpEqual,
+		},
 	}
 )
 
@@ -133,33 +146,25 @@ func GenerateModelWeightsCacheVolume(ctx context.Context, workspaceObj *v1beta1.
 func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspace, revisionNum string,
 	model pkgmodel.Model, kubeClient client.Client) (client.Object, error) {
 
-	// resource requirements
-	var skuNumGPUs int
-	// initially respect the user setting by deploying the model on the same number of nodes as the user requested
-	numNodes := *workspaceObj.Resource.Count
-	gpuConfig, err := utils.GetGPUConfigBySKU(workspaceObj.Resource.InstanceType)
-	if err != nil {
-		gpuConfig, err = utils.TryGetGPUConfigFromNode(ctx, kubeClient, workspaceObj.Status.WorkerNodes)
+	if v1beta1.IsCPUInference(workspaceObj) {
+		gctx := &generator.WorkspaceGeneratorContext{
+			Ctx:        ctx,
+			KubeClient: kubeClient,
+			Workspace:  workspaceObj,
+			Model:      model,
+		}
+		podSpec, err := generator.GenerateManifest(gctx, GenerateLlamaCppInferencePodSpec, SetInferenceProxy)
 		if err != nil {
-			defaultNumGPU := resource.MustParse(model.GetInferenceParameters().GPUCountRequirement)
-			skuNumGPUs = int(defaultNumGPU.Value())
+			return nil, err
 		}
+		// every llama.cpp replica serves the whole model, the node count is the number of replicas
+		return generator.GenerateManifest(gctx,
+			manifests.GenerateDeploymentManifest(revisionNum, *workspaceObj.Resource.Count),
+			manifests.SetDeploymentPodSpec(podSpec),
+		)
 	}
-	if gpuConfig != nil {
-		skuNumGPUs = gpuConfig.GPUCount
-		// Calculate the minimum number of nodes required to satisfy the model's total GPU memory requirement.
-		// The goal is to maximize GPU utilization and not spread the model across too many nodes.
-		totalGPUMemoryRequired := resource.MustParse(model.GetInferenceParameters().TotalGPUMemoryRequirement)
-		totalGPUMemoryPerNode := resource.NewQuantity(int64(gpuConfig.GPUMemGB)*consts.GiBToBytes, resource.BinarySI)
 
-		minimumNodes := 0
-		for ; totalGPUMemoryRequired.Sign() > 0; totalGPUMemoryRequired.Sub(*totalGPUMemoryPerNode) {
-			minimumNodes++
-		}
-		if minimumNodes < numNodes {
-			numNodes = minimumNodes
-		}
-	}
+	gpuConfig, skuNumGPUs, numNodes := getInferenceGPUConfig(ctx, workspaceObj, model, kubeClient)
 
 	gctx := &generator.WorkspaceGeneratorContext{
 		Ctx:        ctx,
@@ -167,10 +172,33 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 		Workspace:  workspaceObj,
 		Model:      model,
 	}
+
+	if v1beta1.GetWorkspaceRuntimeName(workspaceObj) == pkgmodel.RuntimeNameTensorRTLLM {
+		// The compiled engine is pulled instead of the model weights, it always fits on a single node.
+		podSpec, err := generator.GenerateManifest(gctx,
+			GenerateInferencePodSpec(gpuConfig, skuNumGPUs, 1),
+			SetTensorRTLLMEnginePuller(gpuConfig, skuNumGPUs),
+			SetDefaultModelWeightsVolume,
+			SetInferenceProxy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return generator.GenerateManifest(gctx,
+			manifests.GenerateDeploymentManifest(revisionNum, 1),
+			manifests.SetDeploymentPodSpec(podSpec),
+		)
+	}
+
+	// Adapters loaded at runtime are pulled by a sidecar, the others by init containers before the server starts.
+	adapterPuller := SetAdapterPuller
+	if runtimeAdapterLoading(gctx, numNodes) {
+		adapterPuller = SetAdapterPullerSidecar
+	}
 	podOpts := []generator.TypedManifestModifier[generator.WorkspaceGeneratorContext, corev1.PodSpec]{
 		GenerateInferencePodSpec(gpuConfig, skuNumGPUs, numNodes),
 		SetModelDownloadInfo,
-		SetAdapterPuller,
+		adapterPuller,
 	}
 
 	// For multi-node distributed inference with vLLM, we need to use a StatefulSet instead of a Deployment
@@ -185,6 +213,7 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 		} else {
 			podOpts = append(podOpts, SetDefaultModelWeightsVolume)
 		}
+		podOpts = append(podOpts, SetInferenceProxy)
 
 		podSpec, err := generator.GenerateManifest(gctx, podOpts...)
 		if err != nil {
@@ -197,8 +226,21 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 		)
 
 		return generator.GenerateManifest(gctx, ssOpts...)
+	} else if placement := adapterPlacement(gctx, numNodes); placement != nil {
+		// Every node runs a replica spreading the adapters, which needs a stable identity to keep its adapters.
+		podOpts = append(podOpts, SetDefaultModelWeightsVolume, SetAdapterPlacement, SetInferenceProxy)
+
+		podSpec, err := generator.GenerateManifest(gctx, podOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return generator.GenerateManifest(gctx,
+			manifests.GenerateStatefulSetManifest(revisionNum, len(placement)),
+			manifests.SetStatefulSetPodSpec(podSpec),
+		)
 	} else {
-		podOpts = append(podOpts, SetDefaultModelWeightsVolume)
+		podOpts = append(podOpts, SetDefaultModelWeightsVolume, SetInferenceProxy)
 
 		podSpec, err := generator.GenerateManifest(gctx, podOpts...)
 		if err != nil {
@@ -212,6 +254,49 @@ func GeneratePresetInference(ctx context.Context, workspaceObj *v1beta1.Workspac
 	}
 }
 
+// getInferenceGPUConfig returns the GPU config of the workspace nodes, the number of GPUs requested by
+// each inference pod and the number of nodes the model is deployed on.
+func getInferenceGPUConfig(ctx context.Context, workspaceObj *v1beta1.Workspace, model pkgmodel.Model, kubeClient client.Client) (*sku.GPUConfig, int, int) {
+	var skuNumGPUs int
+	// initially respect the user setting by deploying the model on the same number of nodes as the user requested
+	numNodes := *workspaceObj.Resource.Count
+	gpuConfig, err := utils.GetGPUConfigBySKU(workspaceObj.ActiveInstanceType())
+	if err != nil {
+		gpuConfig, err = utils.TryGetGPUConfigFromNode(ctx, kubeClient, workspaceObj.Status.WorkerNodes)
+		if err != nil {
+			defaultNumGPU := resource.MustParse(model.GetInferenceParameters().GPUCountRequirement)
+			skuNumGPUs = int(defaultNumGPU.Value())
+		}
+	}
+	if partition := workspaceObj.Resource.GPUPartition; partition != nil {
+		// A GPU partition is a fraction of a single GPU, the webhook validated that the model fits on it.
+		gpuConfig = gpuConfig.WithPartition(partition.MIGProfile, partition.TimeSlicingReplicas)
+		skuNumGPUs = 1
+		numNodes = 1
+	} else if gpuConfig != nil && workspaceObj.IsSharedPlacement() {
+		// A shared node is divided among workspaces, only request the GPUs required by the preset.
+		defaultNumGPU := resource.MustParse(model.GetInferenceParameters().GPUCountRequirement)
+		skuNumGPUs = int(defaultNumGPU.Value())
+		numNodes = 1
+	} else if gpuConfig != nil {
+		skuNumGPUs = gpuConfig.GPUCount
+		// Calculate the minimum number of nodes required to satisfy the model's total GPU memory requirement.
+		// The goal is to maximize GPU utilization and not spread the model across too many nodes.
+		totalGPUMemoryRequired := resource.MustParse(model.GetInferenceParameters().TotalGPUMemoryRequirement)
+		totalGPUMemoryPerNode := resource.NewQuantity(int64(gpuConfig.GPUMemGB)*consts.GiBToBytes, resource.BinarySI)
+
+		minimumNodes := 0
+		for ; totalGPUMemoryRequired.Sign() > 0; totalGPUMemoryRequired.Sub(*totalGPUMemoryPerNode) {
+			minimumNodes++
+		}
+		if minimumNodes < numNodes {
+			numNodes = minimumNodes
+		}
+	}
+
+	return gpuConfig, skuNumGPUs, numNodes
+}
+
 func shouldUseDistributedInference(ctx *generator.WorkspaceGeneratorContext, numNodes int) bool {
 	runtimeName := v1beta1.GetWorkspaceRuntimeName(ctx.Workspace)
 	return ctx.Model.SupportDistributedInference() && runtimeName == pkgmodel.RuntimeNameVLLM && numNodes > 1
@@ -250,7 +335,8 @@ func getDistributedInferenceProbe(probeType probeType, wObj *v1beta1.Workspace,
 	case probeTypeLiveness:
 		args["ray-port"] = strconv.Itoa(pkgmodel.PortRayCluster)
 	case probeTypeReadiness:
-		args["vllm-port"] = strconv.Itoa(PortInferenceServer)
+		// Behind the inference proxy, the inference server of the leader is only reachable through the proxy.
+		args["vllm-port"] = strconv.Itoa(int(manifests.GetInferenceServiceTargetPort(wObj)))
 	}
 
 	// for distributed inference, we cannot use the default http probe since only the leader pod
@@ -282,8 +368,15 @@ func getDistributedInferenceProbe(probeType probeType, wObj *v1beta1.Workspace,
 	return probe
 }
 
-func GetBaseImageName() string {
-	presetObj := metadata.MustGet("base")
+// GetBaseImageName returns the base runtime image built for the GPU vendor.
+func GetBaseImageName(vendor sku.GPUVendor) string {
+	presetObj := metadata.MustGetBase(vendor)
+	return utils.GetPresetImageName(presetObj.Name, presetObj.Tag)
+}
+
+// GetSGLangImageName returns the base runtime image of the SGLang runtime.
+func GetSGLangImageName() string {
+	presetObj := metadata.MustGetSGLangBase()
 	return utils.GetPresetImageName(presetObj.Name, presetObj.Tag)
 }
 
@@ -319,23 +412,13 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, skuNumGPUs, numNodes int
 		volumes = append(volumes, shmVolume)
 		volumeMounts = append(volumeMounts, shmVolumeMount)
 
-		// node selector
-		nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(ctx.Workspace.Resource.LabelSelector.MatchLabels))
-		for key, value := range ctx.Workspace.Resource.LabelSelector.MatchLabels {
-			nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-				Key:      key,
-				Operator: corev1.NodeSelectorOpIn,
-				Values:   []string{value},
-			})
-		}
-
 		// resource requirements
 		resourceReq := corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+				resources.GPUConfigResourceName(gpuConfig): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 			},
 			Limits: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+				resources.GPUConfigResourceName(gpuConfig): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 			},
 		}
 
@@ -350,27 +433,26 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, skuNumGPUs, numNodes int
 			NumNodes:             numNodes,
 			WorkspaceMetadata:    ctx.Workspace.ObjectMeta,
 			DistributedInference: ctx.Model.SupportDistributedInference(),
+			Host:                 manifests.GetInferenceServerHost(ctx.Workspace),
 			RuntimeContextExtraArguments: pkgmodel.RuntimeContextExtraArguments{
 				AdaptersEnabled: len(ctx.Workspace.Inference.Adapters) > 0,
 			},
 		})
 
-		spec.Affinity = &corev1.Affinity{
-			NodeAffinity: &corev1.NodeAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-					NodeSelectorTerms: []corev1.NodeSelectorTerm{
-						{
-							MatchExpressions: nodeRequirements,
-						},
-					},
-				},
-			},
+		image := GetBaseImageName(gpuConfig.Vendor())
+		switch runtimeName {
+		case pkgmodel.RuntimeNameSGLang:
+			image = GetSGLangImageName()
+		case pkgmodel.RuntimeNameTensorRTLLM:
+			image = GetTensorRTLLMImageName()
 		}
+
+		spec.Affinity = generateNodeAffinity(ctx.Workspace)
 		spec.ImagePullSecrets = GetInferenceImageInfo(ctx.Ctx, ctx.Workspace)
 		spec.Containers = []corev1.Container{
 			{
 				Name:           ctx.Workspace.Name,
-				Image:          GetBaseImageName(),
+				Image:          image,
 				Command:        commands,
 				Resources:      resourceReq,
 				Ports:          containerPorts,
@@ -382,10 +464,107 @@ func GenerateInferencePodSpec(gpuConfig *sku.GPUConfig, skuNumGPUs, numNodes int
 		spec.Tolerations = tolerations
 		spec.Volumes = volumes
 
+		if ctx.Workspace.UsesDynamicResourceAllocation() {
+			claimTemplate := manifests.GenerateGPUResourceClaimTemplateManifest(ctx.Workspace, skuNumGPUs)
+			if err := resources.EnsureResource(ctx.Ctx, claimTemplate, ctx.KubeClient); err != nil {
+				return fmt.Errorf("failed to ensure ResourceClaimTemplate %s: %w", claimTemplate.Name, err)
+			}
+			manifests.SetGPUResourceClaim(ctx.Workspace, spec)
+		}
+
 		return nil
 	}
 }
 
+// GenerateLlamaCppInferencePodSpec generates the pod spec of a preset served by llama.cpp on CPU nodes.
+// The GGUF weights are downloaded by the server into the model weights volume when it starts.
+func GenerateLlamaCppInferencePodSpec(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	inferenceParam := ctx.Model.GetInferenceParameters().DeepCopy()
+	commands := inferenceParam.GetInferenceCommand(pkgmodel.RuntimeContext{
+		RuntimeName:       pkgmodel.RuntimeNameLlamaCpp,
+		WorkspaceMetadata: ctx.Workspace.ObjectMeta,
+		Host:              manifests.GetInferenceServerHost(ctx.Workspace),
+	})
+
+	cpus, memory, err := inferenceParam.GetLlamaCppResourceRequirements()
+	if err != nil {
+		return err
+	}
+	resourceReq := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewQuantity(int64(cpus), resource.DecimalSI),
+			corev1.ResourceMemory: memory,
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: memory,
+		},
+	}
+
+	spec.Affinity = generateNodeAffinity(ctx.Workspace)
+	spec.Containers = []corev1.Container{
+		{
+			Name:      ctx.Workspace.Name,
+			Image:     DefaultLlamaCppImage,
+			Command:   commands,
+			Resources: resourceReq,
+			Ports:     containerPorts,
+			Env: []corev1.EnvVar{
+				{
+					Name:  "LLAMA_CACHE",
+					Value: utils.DefaultWeightsVolumePath,
+				},
+			},
+			LivenessProbe:  defaultLivenessProbe,
+			ReadinessProbe: defaultReadinessProbe,
+			VolumeMounts:   []corev1.VolumeMount{utils.DefaultModelWeightsVolumeMount},
+		},
+	}
+	spec.Tolerations = tolerations
+	spec.Volumes = []corev1.Volume{utils.DefaultModelWeightsVolume}
+
+	return nil
+}
+
+// generateNodeAffinity requires the nodes selected by the label selector of the workspace.
+func generateNodeAffinity(wObj *v1beta1.Workspace) *corev1.Affinity {
+	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(wObj.Resource.LabelSelector.MatchLabels))
+	for key, value := range wObj.Resource.LabelSelector.MatchLabels {
+		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		})
+	}
+	if wObj.IsSharedPlacement() && !v1beta1.IsNodeAutoProvisioningDisabled(wObj) {
+		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+			Key:      v1beta1.LabelSharedNode,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"true"},
+		})
+		// The GPUs are accounted on the shared node the workspace was placed on, the pod must not
+		// land on another shared node with the same labels.
+		if len(wObj.Status.WorkerNodes) > 0 {
+			nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
+				Key:      corev1.LabelHostname,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   wObj.Status.WorkerNodes,
+			})
+		}
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: nodeRequirements,
+					},
+				},
+			},
+		},
+	}
+}
+
 func SetModelDownloadInfo(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
 	if ctx.Model.GetInferenceParameters().DownloadAtRuntime {
 		envvar := corev1.EnvVar{
@@ -459,6 +638,66 @@ func SetDistributedInferenceProbe(ctx *generator.WorkspaceGeneratorContext, spec
 	return nil
 }
 
+// SetInferenceProxy injects the inference proxy in front of the inference server. It must be the last
+// modifier, since the other modifiers add their environment variables and volume mounts to all containers.
+func SetInferenceProxy(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+	if !manifests.InferenceProxyEnabled(ctx.Workspace) {
+		return nil
+	}
+	container, volumes := manifests.GenerateInferenceProxyContainer(ctx.Workspace)
+	runtimeName := v1beta1.GetWorkspaceRuntimeName(ctx.Workspace)
+	if modelName := ctx.Model.GetInferenceParameters().ServedModelName(runtimeName); modelName != "" {
+		container.Args = append(container.Args, "--model="+modelName)
+	}
+	// The max-num-seqs of vLLM caps the in-flight requests admitted by the proxy.
+	if ctx.Workspace.Inference.RateLimit != nil && len(spec.Containers) > 0 && runtimeName == pkgmodel.RuntimeNameVLLM {
+		for _, volumeMount := range spec.Containers[0].VolumeMounts {
+			if volumeMount.MountPath == utils.DefaultConfigMapMountPath {
+				container.VolumeMounts = append(container.VolumeMounts, volumeMount)
+				container.Args = append(container.Args,
+					"--inference-config="+path.Join(volumeMount.MountPath, pkgmodel.ConfigfileNameVLLM))
+				break
+			}
+		}
+	}
+	// The inference server only listens on the loopback interface, the kubelet probes it through the proxy.
+	// The proxy loads the adapters pulled by the adapter puller sidecar, since the runtime LoRA endpoints of the
+	// inference server are not reachable from outside the pod. It reads the images the adapters were pulled from
+	// in the adapter volume.
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == image.AdapterPullerContainerName {
+			for _, volumeMount := range spec.Containers[i].VolumeMounts {
+				if volumeMount.MountPath == AdapterListMountPath || volumeMount.MountPath == utils.DefaultAdapterVolumePath {
+					volumeMount.ReadOnly = true
+					container.VolumeMounts = append(container.VolumeMounts, volumeMount)
+				}
+			}
+			// The replicas spreading the adapters pass their index to the sidecar.
+			container.Env = append(container.Env, spec.Containers[i].Env...)
+			container.Args = append(container.Args,
+				"--adapter-list-dir="+AdapterListMountPath,
+				"--adapter-dir="+utils.DefaultAdapterVolumePath)
+		}
+		if spec.Containers[i].Name == ctx.Workspace.Name {
+			spec.Containers[i].LivenessProbe = proxiedProbe(spec.Containers[i].LivenessProbe)
+			spec.Containers[i].ReadinessProbe = proxiedProbe(spec.Containers[i].ReadinessProbe)
+		}
+	}
+	spec.Containers = append(spec.Containers, container)
+	spec.Volumes = append(spec.Volumes, volumes...)
+	return nil
+}
+
+// proxiedProbe returns a copy of the HTTP probe of the inference server targeting the inference proxy instead.
+func proxiedProbe(probe *corev1.Probe) *corev1.Probe {
+	if probe == nil || probe.HTTPGet == nil || probe.HTTPGet.Port.IntValue() != PortInferenceServer {
+		return probe
+	}
+	probe = probe.DeepCopy()
+	probe.HTTPGet.Port = intstr.FromInt32(manifests.PortInferenceProxy)
+	return probe
+}
+
 func SetDefaultModelWeightsVolume(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
 	spec.Volumes = append(spec.Volumes, utils.DefaultModelWeightsVolume)
 	return nil
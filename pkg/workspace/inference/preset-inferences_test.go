@@ -24,13 +24,20 @@ import (
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	metadata "github.com/kaito-project/kaito/presets/workspace/models"
 )
 
@@ -51,6 +58,8 @@ func TestGeneratePresetInference(t *testing.T) {
 		expectedModelImage string
 		expectedVolume     string
 		expectedEnvVars    []corev1.EnvVar
+		expectedBaseImage  string
+		expectedGPU        corev1.ResourceName
 	}{
 		"test-model/vllm": {
 			workspace: test.MockWorkspaceWithPresetVLLM,
@@ -67,6 +76,77 @@ func TestGeneratePresetInference(t *testing.T) {
 			hasAdapters: false,
 		},
 
+		"test-model/sglang": {
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Annotations = map[string]string{v1beta1.AnnotationWorkspaceRuntime: "sglang"}
+				return w
+			}(),
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workload:           "Deployment",
+			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
+			expectedCmd:        "/bin/sh -c python3 /workspace/sglang/inference_api.py --tp-size=2 --served-model-name=mymodel --mem-fraction-static=0.90 --kaito-config-file=/mnt/config/inference_config.yaml",
+			expectedBaseImage:  fmt.Sprintf("test-registry/kaito-base-sglang:%s", metadata.MustGet("base-sglang").Tag),
+		},
+
+		"test-model-shared/vllm": {
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Resource.PlacementPolicy = v1beta1.PlacementPolicyShared
+				return w
+			}(),
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workload:           "Deployment",
+			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
+			// The pod only requests the GPUs required by the preset on a shared node.
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --tensor-parallel-size=1 --served-model-name=mymodel --gpu-memory-utilization=0.90 --kaito-config-file=/mnt/config/inference_config.yaml",
+			hasAdapters: false,
+		},
+
+		"test-model-amd/vllm": {
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Resource.InstanceType = "Standard_NG32ads_V620_v1"
+				return w
+			}(),
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workload:           "Deployment",
+			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
+			expectedCmd:        "/bin/sh -c python3 /workspace/vllm/inference_api.py --tensor-parallel-size=1 --served-model-name=mymodel --gpu-memory-utilization=0.95 --kaito-config-file=/mnt/config/inference_config.yaml",
+			expectedBaseImage:  fmt.Sprintf("test-registry/kaito-base-rocm:%s", metadata.MustGet("base-rocm").Tag),
+			expectedGPU:        "amd.com/gpu",
+		},
+
+		"test-model-mig/vllm": {
+			workspace: func() *v1beta1.Workspace {
+				w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+				w.Resource.GPUPartition = &v1beta1.GPUPartition{MIGProfile: "1g.10gb"}
+				return w
+			}(),
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			},
+			workload:           "Deployment",
+			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
+			// vLLM sees the MIG instance as a whole device.
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --tensor-parallel-size=1 --served-model-name=mymodel --gpu-memory-utilization=0.85 --kaito-config-file=/mnt/config/inference_config.yaml",
+			expectedGPU: "nvidia.com/mig-1g.10gb",
+		},
+
 		"test-model-no-parallel/vllm": {
 			workspace: test.MockWorkspaceWithPresetVLLM,
 			nodeCount: 1,
@@ -106,12 +186,14 @@ func TestGeneratePresetInference(t *testing.T) {
 			},
 			workload:           "Deployment",
 			expectedModelImage: "test-registry/kaito-test-model:1.0.0",
-			expectedCmd:        "/bin/sh -c python3 /workspace/vllm/inference_api.py --enable-lora --tensor-parallel-size=2 --served-model-name=mymodel --gpu-memory-utilization=0.90 --kaito-config-file=/mnt/config/inference_config.yaml",
-			hasAdapters:        true,
-			expectedVolume:     "adapter-volume",
+			// The runtime LoRA endpoints are kept behind the inference proxy.
+			expectedCmd:    "/bin/sh -c python3 /workspace/vllm/inference_api.py --enable-lora --host=127.0.0.1 --tensor-parallel-size=2 --served-model-name=mymodel --gpu-memory-utilization=0.90 --kaito-config-file=/mnt/config/inference_config.yaml",
+			hasAdapters:    true,
+			expectedVolume: "adapter-volume",
+			// The adapters are pulled by a sidecar and loaded at runtime.
 			expectedEnvVars: []corev1.EnvVar{{
-				Name:  "Adapter-1",
-				Value: "0.5",
+				Name:  "VLLM_ALLOW_RUNTIME_LORA_UPDATING",
+				Value: "True",
 			}},
 		},
 
@@ -263,7 +345,7 @@ func TestGeneratePresetInference(t *testing.T) {
 			mockClient := test.NewClient()
 			tc.callMocks(mockClient)
 
-			workspace := tc.workspace
+			workspace := tc.workspace.DeepCopy()
 			workspace.Resource.Count = &tc.nodeCount
 			expectedSecrets := []string{"fake-secret"}
 			if tc.hasAdapters {
@@ -299,15 +381,18 @@ func TestGeneratePresetInference(t *testing.T) {
 			image := ""
 			envVars := []corev1.EnvVar{}
 			var initContainer []corev1.Container
+			var gpuRequests corev1.ResourceList
 			switch t := createdObject.(type) {
 			case *appsv1.Deployment:
 				createdWorkload = "Deployment"
 				image = t.Spec.Template.Spec.Containers[0].Image
+				gpuRequests = t.Spec.Template.Spec.Containers[0].Resources.Requests
 				envVars = t.Spec.Template.Spec.Containers[0].Env
 				initContainer = t.Spec.Template.Spec.InitContainers
 			case *appsv1.StatefulSet:
 				createdWorkload = "StatefulSet"
 				image = t.Spec.Template.Spec.Containers[0].Image
+				gpuRequests = t.Spec.Template.Spec.Containers[0].Resources.Requests
 				envVars = t.Spec.Template.Spec.Containers[0].Env
 				initContainer = t.Spec.Template.Spec.InitContainers
 			}
@@ -334,8 +419,20 @@ func TestGeneratePresetInference(t *testing.T) {
 					t.Errorf("%s: Puller command is not expected, got %v, expect %v", k, pullerContainer.Command, expectedPullerCmd)
 				}
 			}
-			if image != baseImageName {
-				t.Errorf("%s: image is not expected, got %s, expect %s", k, image, baseImageName)
+			expectedImage := baseImageName
+			if tc.expectedBaseImage != "" {
+				expectedImage = tc.expectedBaseImage
+			}
+			if image != expectedImage {
+				t.Errorf("%s: image is not expected, got %s, expect %s", k, image, expectedImage)
+			}
+
+			expectedGPU := tc.expectedGPU
+			if expectedGPU == "" {
+				expectedGPU = "nvidia.com/gpu"
+			}
+			if _, found := gpuRequests[expectedGPU]; !found {
+				t.Errorf("%s: GPU resource is not expected, got %v, expect %s", k, gpuRequests, expectedGPU)
 			}
 
 			if !reflect.DeepEqual(envVars, tc.expectedEnvVars) {
@@ -393,6 +490,245 @@ func TestGeneratePresetInference(t *testing.T) {
 	}
 }
 
+func TestGeneratePresetInferenceWithDynamicResourceAllocation(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	minMemory := resource.MustParse("16Gi")
+	workspace.Resource.DynamicResourceAllocation = &v1beta1.DynamicResourceAllocation{
+		ProductName: "Tesla-V100-PCIE-16GB",
+		MinMemory:   &minMemory,
+	}
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&resourcev1beta1.ResourceClaimTemplate{}), mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{Group: "resource.k8s.io", Resource: "resourceclaimtemplates"}, "testWorkspace-gpu"))
+	mockClient.On("Create", mock.IsType(context.TODO()), mock.IsType(&resourcev1beta1.ResourceClaimTemplate{}), mock.Anything).Return(nil)
+
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	podSpec := createdObject.(*appsv1.Deployment).Spec.Template.Spec
+
+	// The pod claims its GPUs from the workspace's ResourceClaimTemplate instead of device plugin limits.
+	expectedClaims := []corev1.PodResourceClaim{{Name: "gpu", ResourceClaimTemplateName: lo.ToPtr("testWorkspace-gpu")}}
+	if !reflect.DeepEqual(podSpec.ResourceClaims, expectedClaims) {
+		t.Errorf("ResourceClaims are not expected, got %v, expect %v", podSpec.ResourceClaims, expectedClaims)
+	}
+	containerResources := podSpec.Containers[0].Resources
+	if expectedClaims := []corev1.ResourceClaim{{Name: "gpu"}}; !reflect.DeepEqual(containerResources.Claims, expectedClaims) {
+		t.Errorf("container resource claims are not expected, got %v, expect %v", containerResources.Claims, expectedClaims)
+	}
+	if _, found := containerResources.Limits[resources.CapacityNvidiaGPU]; found {
+		t.Errorf("container must not request device plugin GPUs, got %v", containerResources.Limits)
+	}
+
+	claimTemplate := &resourcev1beta1.ResourceClaimTemplate{}
+	mockClient.GetObjectFromMap(claimTemplate, client.ObjectKey{Name: "testWorkspace-gpu", Namespace: "kaito"})
+	requests := claimTemplate.Spec.Spec.Devices.Requests
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 device request, got %v", requests)
+	}
+	if requests[0].DeviceClassName != v1beta1.DefaultDRADeviceClassName || requests[0].Count != 2 {
+		t.Errorf("device request is not expected, got class %s count %d", requests[0].DeviceClassName, requests[0].Count)
+	}
+	expectedSelectors := []string{
+		`device.attributes[device.driver].productName == "Tesla-V100-PCIE-16GB"`,
+		`device.capacity[device.driver].memory.compareTo(quantity("16Gi")) >= 0`,
+	}
+	var selectors []string
+	for _, selector := range requests[0].Selectors {
+		selectors = append(selectors, selector.CEL.Expression)
+	}
+	if !reflect.DeepEqual(selectors, expectedSelectors) {
+		t.Errorf("device selectors are not expected, got %v, expect %v", selectors, expectedSelectors)
+	}
+}
+
+func TestGeneratePresetInferenceWithLlamaCpp(t *testing.T) {
+	test.RegisterTestModel()
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	workspace.Resource.InstanceType = "Standard_D8s_v5"
+	workspace.Annotations = map[string]string{v1beta1.AnnotationWorkspaceRuntime: "llamacpp"}
+
+	mockClient := test.NewClient()
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	deployment, ok := createdObject.(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("expected a Deployment, got %T", createdObject)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+
+	if container.Image != DefaultLlamaCppImage {
+		t.Errorf("image is not expected, got %s, expect %s", container.Image, DefaultLlamaCppImage)
+	}
+	expectedCmd := "/bin/sh -c /app/llama-server --host 0.0.0.0 --port 5000 --alias=mymodel --model-url=https://huggingface.co/test/test-model-GGUF/resolve/test-revision/test-model-Q4_K_M.gguf --threads=5"
+	cmd := strings.Join(container.Command, " ")
+	if strings.Split(cmd, "--")[0] != strings.Split(expectedCmd, "--")[0] {
+		t.Errorf("main cmdline is not expected, got %s, expect %s", cmd, expectedCmd)
+	}
+	if params, expectedParams := toParameterMap(strings.Split(cmd, "--")[1:]), toParameterMap(strings.Split(expectedCmd, "--")[1:]); !reflect.DeepEqual(params, expectedParams) {
+		t.Errorf("parameters are not expected, got %s, expect %s", params, expectedParams)
+	}
+
+	// 4.5Gi of weights are rounded up to 5 CPUs and 5Gi, plus 2Gi reserved memory.
+	expectedResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("5"),
+			corev1.ResourceMemory: resource.MustParse("7Gi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("7Gi"),
+		},
+	}
+	for name, quantity := range expectedResources.Requests {
+		if actual := container.Resources.Requests[name]; actual.Cmp(quantity) != 0 {
+			t.Errorf("request %s is not expected, got %s, expect %s", name, actual.String(), quantity.String())
+		}
+	}
+	if actual := container.Resources.Limits[corev1.ResourceMemory]; actual.Cmp(expectedResources.Limits[corev1.ResourceMemory]) != 0 {
+		t.Errorf("memory limit is not expected, got %s", actual.String())
+	}
+	if _, found := container.Resources.Limits[resources.CapacityNvidiaGPU]; found {
+		t.Errorf("llama.cpp container must not request GPUs")
+	}
+	if len(deployment.Spec.Template.Spec.InitContainers) != 0 {
+		t.Errorf("llama.cpp downloads the weights itself, expected no init containers, got %v", deployment.Spec.Template.Spec.InitContainers)
+	}
+}
+
+func TestGeneratePresetInferenceWithAuth(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	workspace.Inference.Auth = &v1beta1.AuthSpec{SecretName: "api-keys"}
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	podSpec := createdObject.(*appsv1.Deployment).Spec.Template.Spec
+
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != manifests.InferenceProxyContainerName {
+		t.Fatalf("expected the inference proxy to be injected after the inference server, got %v", podSpec.Containers)
+	}
+	proxy := podSpec.Containers[1]
+	for _, arg := range []string{"--api-keys-dir=/etc/kaito/api-keys", "--workspace=testWorkspace", "--model=mymodel"} {
+		if !lo.Contains(proxy.Args, arg) {
+			t.Errorf("expected proxy arg %s, got %v", arg, proxy.Args)
+		}
+	}
+	if len(proxy.Env) != 0 {
+		t.Errorf("the environment of the inference server must not leak into the proxy, got %v", proxy.Env)
+	}
+	volume, found := lo.Find(podSpec.Volumes, func(v corev1.Volume) bool { return v.Secret != nil })
+	if !found || volume.Secret.SecretName != "api-keys" {
+		t.Errorf("expected the API keys Secret to be mounted, got %v", podSpec.Volumes)
+	}
+
+	// The inference server is only reachable through the proxy.
+	server := podSpec.Containers[0]
+	if !strings.Contains(strings.Join(server.Command, " "), "--host=127.0.0.1") {
+		t.Errorf("expected the inference server to bind to the loopback interface, got %v", server.Command)
+	}
+	for _, probe := range []*corev1.Probe{server.LivenessProbe, server.ReadinessProbe} {
+		if probe.HTTPGet.Port.IntValue() != manifests.PortInferenceProxy {
+			t.Errorf("expected the inference server to be probed through the proxy, got port %s", probe.HTTPGet.Port.String())
+		}
+	}
+	if defaultReadinessProbe.HTTPGet.Port.IntValue() != PortInferenceServer {
+		t.Errorf("the default probes must not be modified")
+	}
+}
+
+func TestGeneratePresetInferenceWithRateLimit(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	workspace.Inference.RateLimit = &v1beta1.RateLimitSpec{
+		RequestsPerMinute:     lo.ToPtr(int32(60)),
+		MaxConcurrentRequests: lo.ToPtr(int32(4)),
+	}
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	podSpec := createdObject.(*appsv1.Deployment).Spec.Template.Spec
+
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != manifests.InferenceProxyContainerName {
+		t.Fatalf("expected the inference proxy to be injected after the inference server, got %v", podSpec.Containers)
+	}
+	proxy := podSpec.Containers[1]
+	for _, arg := range []string{
+		"--requests-per-minute=60",
+		"--max-concurrent-requests=4",
+		"--inference-config=/mnt/config/inference_config.yaml",
+	} {
+		if !lo.Contains(proxy.Args, arg) {
+			t.Errorf("expected proxy arg %s, got %v", arg, proxy.Args)
+		}
+	}
+	if lo.Contains(proxy.Args, "--api-keys-dir=/etc/kaito/api-keys") {
+		t.Errorf("authentication must stay disabled without auth, got %v", proxy.Args)
+	}
+	if !lo.ContainsBy(proxy.VolumeMounts, func(v corev1.VolumeMount) bool { return v.MountPath == "/mnt/config" }) {
+		t.Errorf("expected the inference config to be mounted in the proxy, got %v", proxy.VolumeMounts)
+	}
+}
+
+func TestGenerateNodeAffinity(t *testing.T) {
+	testcases := map[string]struct {
+		workerNodes  []string
+		expectedKeys []string
+	}{
+		"Shared workspace not placed yet": {
+			expectedKeys: []string{"apps", v1beta1.LabelSharedNode},
+		},
+		"Shared workspace pinned to the node its GPUs are accounted on": {
+			workerNodes:  []string{"shared-node"},
+			expectedKeys: []string{"apps", v1beta1.LabelSharedNode, corev1.LabelHostname},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+			workspace.Resource.PlacementPolicy = v1beta1.PlacementPolicyShared
+			workspace.Status.WorkerNodes = tc.workerNodes
+
+			requirements := generateNodeAffinity(workspace).NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions
+			keys := lo.Map(requirements, func(r corev1.NodeSelectorRequirement, _ int) string { return r.Key })
+			if !reflect.DeepEqual(keys, tc.expectedKeys) {
+				t.Errorf("node affinity keys are not expected, got %v, expect %v", keys, tc.expectedKeys)
+			}
+			if hostname, found := lo.Find(requirements, func(r corev1.NodeSelectorRequirement) bool { return r.Key == corev1.LabelHostname }); found &&
+				!reflect.DeepEqual(hostname.Values, tc.workerNodes) {
+				t.Errorf("expected the pod to be pinned to %v, got %v", tc.workerNodes, hostname.Values)
+			}
+		})
+	}
+}
+
 func TestGetDistributedInferenceProbe(t *testing.T) {
 	testcases := map[string]struct {
 		probeType           probeType
@@ -0,0 +1,197 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/generator"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/image"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	metadata "github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+var (
+	// engineCachedPath is created by the engine build job when the engine is found in the engine cache.
+	engineCachedPath = path.Join(utils.DefaultEngineVolumePath, "engine_cached")
+	// engineBuiltPath is created by the engine build job when the engine is ready to be pushed.
+	engineBuiltPath = path.Join(utils.DefaultEngineVolumePath, "engine_build_completed.txt")
+
+	invalidTagChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// GetTensorRTLLMImageName returns the base runtime image of the TensorRT-LLM runtime.
+func GetTensorRTLLMImageName() string {
+	presetObj := metadata.MustGetTensorRTLLMBase()
+	return utils.GetPresetImageName(presetObj.Name, presetObj.Tag)
+}
+
+// GetTensorRTLLMEngineImage returns the reference of the compiled engine of the workspace in its engine
+// cache repository. An engine only runs on the GPU model, tensor parallel degree and TensorRT-LLM version
+// it was compiled for, the tag encodes them with the model revision so any workspace with the same key
+// reuses the cached engine.
+func GetTensorRTLLMEngineImage(wObj *v1beta1.Workspace, params *pkgmodel.PresetParam, gpuConfig *sku.GPUConfig, skuNumGPUs int) (string, error) {
+	repository := wObj.Annotations[v1beta1.AnnotationEngineCacheRepository]
+	if repository == "" {
+		return "", fmt.Errorf("annotation %s is required by the TensorRT-LLM runtime", v1beta1.AnnotationEngineCacheRepository)
+	}
+	if gpuConfig == nil || gpuConfig.GPUModel == "" || gpuConfig.GPUModel == "unknown" {
+		return "", fmt.Errorf("unable to determine the GPU model of instance type %s", wObj.ActiveInstanceType())
+	}
+
+	_, revision, _ := utils.ParseHuggingFaceModelVersion(params.Version)
+	if revision == "" {
+		revision = params.Tag
+	}
+	// a commit prefix is enough to identify the revision and keeps the tag readable
+	revision = revision[:min(len(revision), 12)]
+
+	tpSize := skuNumGPUs
+	if params.DisableTensorParallelism {
+		tpSize = 1
+	}
+
+	gpuModel := strings.Trim(invalidTagChars.ReplaceAllString(strings.ToLower(gpuConfig.GPUModel), "-"), "-")
+	tag := fmt.Sprintf("%s-%s-%s-tp%d-trtllm%s", params.Name, revision, gpuModel, tpSize, metadata.MustGetTensorRTLLMBase().Tag)
+	return fmt.Sprintf("%s:%s", repository, tag), nil
+}
+
+// GenerateTensorRTLLMEngineBuildJob generates the job compiling the TensorRT-LLM engine of the workspace preset
+// on a node of the workspace and pushing it to the engine cache repository. The job checks the engine cache
+// first, nothing is built or pushed if the engine is already cached.
+func GenerateTensorRTLLMEngineBuildJob(ctx context.Context, wObj *v1beta1.Workspace, revisionNum string,
+	model pkgmodel.Model, kubeClient client.Client) (*batchv1.Job, error) {
+	gpuConfig, skuNumGPUs, _ := getInferenceGPUConfig(ctx, wObj, model, kubeClient)
+	inferenceParam := model.GetInferenceParameters().DeepCopy()
+	engineImage, err := GetTensorRTLLMEngineImage(wObj, inferenceParam, gpuConfig, skuNumGPUs)
+	if err != nil {
+		return nil, err
+	}
+
+	engineVolume, engineVolumeMount := utils.ConfigEngineVolume()
+	volumes := []corev1.Volume{engineVolume, utils.DefaultModelWeightsVolume}
+	volumeMounts := []corev1.VolumeMount{engineVolumeMount, utils.DefaultModelWeightsVolumeMount}
+	if secret := wObj.Annotations[v1beta1.AnnotationEngineCacheSecret]; secret != "" {
+		secretVolume, secretVolumeMount := utils.ConfigImagePushSecretVolume(secret)
+		volumes = append(volumes, secretVolume)
+		volumeMounts = append(volumeMounts, secretVolumeMount)
+	}
+
+	// The weights are only pulled if the engine has to be built.
+	cacheCheckCmd := fmt.Sprintf("if oras manifest fetch %s > /dev/null; then touch %s; exit 0; fi", engineImage, engineCachedPath)
+	if !inferenceParam.DownloadAtRuntime {
+		cacheCheckCmd += fmt.Sprintf("; oras pull %s -o %s", manifests.GetModelImageName(inferenceParam), utils.DefaultWeightsVolumePath)
+	}
+	cacheCheckContainer := corev1.Container{
+		Name:         "engine-cache-check",
+		Image:        utils.DefaultORASToolImage,
+		Command:      utils.ShellCmd(cacheCheckCmd),
+		VolumeMounts: volumeMounts,
+	}
+
+	buildCmd := inferenceParam.GetTensorRTLLMEngineBuildCommand(pkgmodel.RuntimeContext{
+		RuntimeName:       pkgmodel.RuntimeNameTensorRTLLM,
+		GPUConfig:         gpuConfig,
+		SKUNumGPUs:        skuNumGPUs,
+		NumNodes:          1,
+		WorkspaceMetadata: wObj.ObjectMeta,
+	})
+	gpuRequest := corev1.ResourceList{
+		resources.GPUConfigResourceName(gpuConfig): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+	}
+	builderContainer := corev1.Container{
+		Name:    "engine-builder",
+		Image:   GetTensorRTLLMImageName(),
+		Command: utils.ShellCmd(fmt.Sprintf("if [ ! -e %s ]; then %s; fi && touch %s", engineCachedPath, buildCmd, engineBuiltPath)),
+		Resources: corev1.ResourceRequirements{
+			Requests: gpuRequest,
+			Limits:   gpuRequest,
+		},
+		VolumeMounts: volumeMounts,
+	}
+	if inferenceParam.DownloadAtRuntime {
+		builderContainer.Env = append(builderContainer.Env, corev1.EnvVar{
+			Name: "HF_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: wObj.Inference.Preset.PresetOptions.ModelAccessSecret,
+					},
+					Key: "HF_TOKEN",
+				},
+			},
+		})
+	}
+
+	// The pusher runs after the init containers, so a failed build fails the job instead of leaving
+	// the pusher waiting for the engine.
+	annotationsData := map[string]map[string]string{
+		"$manifest": {
+			"sh.kaito.model.name": inferenceParam.Name,
+		},
+	}
+	pusherContainer := image.NewDirectoryPusherContainer(utils.DefaultEnginePath, engineImage, annotationsData, engineBuiltPath, engineCachedPath)
+	pusherContainer.VolumeMounts = volumeMounts
+
+	podSpec := corev1.PodSpec{
+		Affinity:       generateNodeAffinity(wObj),
+		InitContainers: []corev1.Container{cacheCheckContainer, builderContainer},
+		Containers:     []corev1.Container{*pusherContainer},
+		Tolerations:    tolerations,
+		Volumes:        volumes,
+	}
+	return manifests.GenerateTensorRTLLMEngineBuildJobManifest(wObj, revisionNum, engineImage, podSpec), nil
+}
+
+// SetTensorRTLLMEnginePuller adds an init container pulling the compiled engine of the workspace from the
+// engine cache repository, which is where the TensorRT-LLM server loads the model from.
+func SetTensorRTLLMEnginePuller(gpuConfig *sku.GPUConfig, skuNumGPUs int) func(*generator.WorkspaceGeneratorContext, *corev1.PodSpec) error {
+	return func(ctx *generator.WorkspaceGeneratorContext, spec *corev1.PodSpec) error {
+		engineImage, err := GetTensorRTLLMEngineImage(ctx.Workspace, ctx.Model.GetInferenceParameters(), gpuConfig, skuNumGPUs)
+		if err != nil {
+			return err
+		}
+
+		engineVolume, engineVolumeMount := utils.ConfigEngineVolume()
+		spec.Volumes = append(spec.Volumes, engineVolume)
+		for i := range spec.Containers {
+			spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, engineVolumeMount)
+		}
+
+		pullerContainer := image.NewPullerContainer(engineImage, utils.DefaultEnginePath)
+		pullerContainer.Name = "engine-puller"
+		pullerContainer.VolumeMounts = []corev1.VolumeMount{engineVolumeMount}
+		if secret := ctx.Workspace.Annotations[v1beta1.AnnotationEngineCacheSecret]; secret != "" {
+			secretVolume, secretVolumeMount := utils.ConfigImagePullSecretVolume("engine-cache", []string{secret})
+			spec.Volumes = append(spec.Volumes, secretVolume)
+			pullerContainer.VolumeMounts = append(pullerContainer.VolumeMounts, secretVolumeMount)
+		}
+		spec.InitContainers = append(spec.InitContainers, *pullerContainer)
+		return nil
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inference
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	metadata "github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+func tensorRTLLMWorkspace() *v1beta1.Workspace {
+	w := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	w.Annotations = map[string]string{
+		v1beta1.AnnotationWorkspaceRuntime:      "tensorrt-llm",
+		v1beta1.AnnotationEngineCacheRepository: "myregistry.azurecr.io/kaito/engines",
+		v1beta1.AnnotationEngineCacheSecret:     "engine-cache-secret",
+	}
+	return w
+}
+
+func TestGetTensorRTLLMEngineImage(t *testing.T) {
+	test.RegisterTestModel()
+	trtllmTag := metadata.MustGet("base-tensorrt-llm").Tag
+	v100 := &sku.GPUConfig{SKU: "Standard_NC12s_v3", GPUCount: 2, GPUMemGB: 32, GPUModel: "NVIDIA V100"}
+
+	testcases := map[string]struct {
+		workspace     *v1beta1.Workspace
+		modelName     string
+		gpuConfig     *sku.GPUConfig
+		skuNumGPUs    int
+		expectedImage string
+		expectedErr   string
+	}{
+		"tag of the weights image": {
+			workspace:     tensorRTLLMWorkspace(),
+			modelName:     "test-model",
+			gpuConfig:     v100,
+			skuNumGPUs:    2,
+			expectedImage: "myregistry.azurecr.io/kaito/engines:test-model-1.0.0-nvidia-v100-tp2-trtllm" + trtllmTag,
+		},
+		"huggingface revision": {
+			workspace:     tensorRTLLMWorkspace(),
+			modelName:     "test-model-download",
+			gpuConfig:     v100,
+			skuNumGPUs:    2,
+			expectedImage: "myregistry.azurecr.io/kaito/engines:test-model-download-test-revisio-nvidia-v100-tp2-trtllm" + trtllmTag,
+		},
+		"tensor parallelism disabled": {
+			workspace:     tensorRTLLMWorkspace(),
+			modelName:     "test-no-tensor-parallel-model",
+			gpuConfig:     v100,
+			skuNumGPUs:    2,
+			expectedImage: "myregistry.azurecr.io/kaito/engines:test-no-tensor-parallel-model-1.0.0-nvidia-v100-tp1-trtllm" + trtllmTag,
+		},
+		"missing engine cache repository": {
+			workspace: func() *v1beta1.Workspace {
+				w := tensorRTLLMWorkspace()
+				delete(w.Annotations, v1beta1.AnnotationEngineCacheRepository)
+				return w
+			}(),
+			modelName:   "test-model",
+			gpuConfig:   v100,
+			skuNumGPUs:  2,
+			expectedErr: "annotation kaito.sh/engine-cache-repository is required",
+		},
+		"unknown GPU model": {
+			workspace:   tensorRTLLMWorkspace(),
+			modelName:   "test-model",
+			gpuConfig:   &sku.GPUConfig{SKU: "unknown", GPUCount: 2, GPUModel: "unknown"},
+			skuNumGPUs:  2,
+			expectedErr: "unable to determine the GPU model",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			params := plugin.KaitoModelRegister.MustGet(tc.modelName).GetInferenceParameters()
+			engineImage, err := GetTensorRTLLMEngineImage(tc.workspace, params, tc.gpuConfig, tc.skuNumGPUs)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTensorRTLLMEngineImage() unexpected error: %v", err)
+			}
+			if engineImage != tc.expectedImage {
+				t.Errorf("engine image is not expected, got %s, expect %s", engineImage, tc.expectedImage)
+			}
+		})
+	}
+}
+
+func TestGenerateTensorRTLLMEngineBuildJob(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	t.Setenv("PRESET_REGISTRY_NAME", "test-registry")
+
+	workspace := tensorRTLLMWorkspace()
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	job, err := GenerateTensorRTLLMEngineBuildJob(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, test.NewClient())
+	if err != nil {
+		t.Fatalf("GenerateTensorRTLLMEngineBuildJob() unexpected error: %v", err)
+	}
+
+	engineImage := fmt.Sprintf("myregistry.azurecr.io/kaito/engines:test-model-1.0.0-nvidia-v100-tp2-trtllm%s", metadata.MustGet("base-tensorrt-llm").Tag)
+	if job.Name != "testWorkspace-engine-build" {
+		t.Errorf("job name is not expected, got %s", job.Name)
+	}
+	if job.Annotations[v1beta1.AnnotationTensorRTLLMEngine] != engineImage {
+		t.Errorf("engine annotation is not expected, got %s, expect %s", job.Annotations[v1beta1.AnnotationTensorRTLLMEngine], engineImage)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if podSpec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("restart policy is not expected, got %s", podSpec.RestartPolicy)
+	}
+	if len(podSpec.InitContainers) != 2 || podSpec.InitContainers[0].Name != "engine-cache-check" || podSpec.InitContainers[1].Name != "engine-builder" {
+		t.Fatalf("init containers are not expected, got %v", podSpec.InitContainers)
+	}
+
+	cacheCheckCmd := strings.Join(podSpec.InitContainers[0].Command, " ")
+	if !strings.Contains(cacheCheckCmd, "oras manifest fetch "+engineImage) {
+		t.Errorf("cache check does not fetch the engine manifest: %s", cacheCheckCmd)
+	}
+	if !strings.Contains(cacheCheckCmd, "oras pull test-registry/kaito-test-model:1.0.0 -o "+utils.DefaultWeightsVolumePath) {
+		t.Errorf("cache check does not pull the model weights: %s", cacheCheckCmd)
+	}
+
+	builder := podSpec.InitContainers[1]
+	if builder.Image != GetTensorRTLLMImageName() {
+		t.Errorf("builder image is not expected, got %s", builder.Image)
+	}
+	builderCmd := strings.Join(builder.Command, " ")
+	for _, arg := range []string{"python3 /workspace/tensorrt-llm/build_engine.py", "--tp-size=2", "--output-dir=" + utils.DefaultEnginePath} {
+		if !strings.Contains(builderCmd, arg) {
+			t.Errorf("builder command %s does not contain %s", builderCmd, arg)
+		}
+	}
+	if gpus := builder.Resources.Limits[resources.CapacityNvidiaGPU]; gpus.Value() != 2 {
+		t.Errorf("builder GPU limit is not expected, got %s", gpus.String())
+	}
+
+	if len(podSpec.Containers) != 1 || podSpec.Containers[0].Name != "pusher" {
+		t.Fatalf("containers are not expected, got %v", podSpec.Containers)
+	}
+	pusherSH := podSpec.Containers[0].Args[0]
+	for _, line := range []string{
+		`[ ! -z "${IMG_REF}" ] || IMG_REF='` + engineImage + `'`,
+		`[ ! -z '' ] || LAYER_PATHS='.'`,
+		`[ ! -z '' ] || SKIP_PATH='` + engineCachedPath + `'`,
+	} {
+		if !strings.Contains(pusherSH, line) {
+			t.Errorf("pusher script does not contain %s", line)
+		}
+	}
+}
+
+func TestGeneratePresetInferenceWithTensorRTLLM(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	t.Setenv("PRESET_REGISTRY_NAME", "test-registry")
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+
+	workspace := tensorRTLLMWorkspace()
+	model := plugin.KaitoModelRegister.MustGet("test-model")
+	createdObject, err := GeneratePresetInference(context.TODO(), workspace, test.MockWorkspaceWithPresetHash, model, mockClient)
+	if err != nil {
+		t.Fatalf("GeneratePresetInference() unexpected error: %v", err)
+	}
+	deployment, ok := createdObject.(*appsv1.Deployment)
+	if !ok {
+		t.Fatalf("expected a Deployment, got %T", createdObject)
+	}
+	podSpec := deployment.Spec.Template.Spec
+	container := podSpec.Containers[0]
+
+	if container.Image != GetTensorRTLLMImageName() {
+		t.Errorf("image is not expected, got %s, expect %s", container.Image, GetTensorRTLLMImageName())
+	}
+	expectedCmd := "/bin/sh -c trtllm-serve --host 0.0.0.0 --port 5000 /mnt/engine/engine --tokenizer=/mnt/engine/engine"
+	if cmd := strings.Join(container.Command, " "); cmd != expectedCmd {
+		t.Errorf("command is not expected, got %s, expect %s", cmd, expectedCmd)
+	}
+
+	// the engine is pulled instead of the model weights
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != "engine-puller" {
+		t.Fatalf("init containers are not expected, got %v", podSpec.InitContainers)
+	}
+	engineImage := fmt.Sprintf("myregistry.azurecr.io/kaito/engines:test-model-1.0.0-nvidia-v100-tp2-trtllm%s", metadata.MustGet("base-tensorrt-llm").Tag)
+	if !strings.Contains(podSpec.InitContainers[0].Args[0], `IMG_REF='`+engineImage+`'`) {
+		t.Errorf("engine puller does not pull %s", engineImage)
+	}
+	foundEngineMount := false
+	for _, volumeMount := range container.VolumeMounts {
+		if volumeMount.MountPath == utils.DefaultEngineVolumePath {
+			foundEngineMount = true
+		}
+	}
+	if !foundEngineMount {
+		t.Errorf("engine volume is not mounted in the inference container")
+	}
+}
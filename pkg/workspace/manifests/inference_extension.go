@@ -0,0 +1,235 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+)
+
+const (
+	// DefaultEndpointPickerImage is the endpoint picker of the Gateway API Inference Extension, it picks
+	// the inference pod serving each request based on the queue length, KV cache usage and loaded LoRA adapters.
+	DefaultEndpointPickerImage = "registry.k8s.io/gateway-api-inference-extension/epp:v0.3.0"
+
+	// EndpointPickerGRPCPort is the port of the ext-proc service the gateway calls to pick an endpoint.
+	EndpointPickerGRPCPort = 9002
+	// EndpointPickerHealthPort is the port of the gRPC health service of the endpoint picker.
+	EndpointPickerHealthPort = 9003
+	// EndpointPickerMetricsPort is the port of the metrics of the endpoint picker.
+	EndpointPickerMetricsPort = 9090
+)
+
+var (
+	// InferencePoolGVK is the kind of the pools of inference pods routed by the Gateway API Inference Extension.
+	InferencePoolGVK = schema.GroupVersionKind{Group: "inference.networking.x-k8s.io", Version: "v1alpha2", Kind: "InferencePool"}
+	// InferenceModelGVK is the kind mapping the model name of a request to an InferencePool.
+	InferenceModelGVK = schema.GroupVersionKind{Group: "inference.networking.x-k8s.io", Version: "v1alpha2", Kind: "InferenceModel"}
+)
+
+// GetEndpointPickerName returns the name of the endpoint picker deployment, service and service account of the workspace.
+func GetEndpointPickerName(wObj *kaitov1beta1.Workspace) string {
+	return fmt.Sprintf("%s-epp", wObj.Name)
+}
+
+// GetInferenceModelName returns the name of the InferenceModel object of a served model of the workspace,
+// the base model uses the workspace name.
+func GetInferenceModelName(wObj *kaitov1beta1.Workspace, adapterName string) string {
+	if adapterName == "" {
+		return wObj.Name
+	}
+	return strings.ToLower(fmt.Sprintf("%s-%s", wObj.Name, adapterName))
+}
+
+// GenerateInferencePoolManifest generates the InferencePool selecting the inference pods of the workspace. The
+// pool has the name of the workspace, so that it can be referenced by HTTPRoutes like the workspace service.
+// leaderOnly selects the leader pod of multi-node inference, the only pod serving the API. The pool targets the
+// same port as the inference service, so that the requests of the gateway go through the inference proxy.
+func GenerateInferencePoolManifest(wObj *kaitov1beta1.Workspace, leaderOnly bool) *unstructured.Unstructured {
+	selector := map[string]interface{}{
+		kaitov1beta1.LabelWorkspaceName: wObj.Name,
+	}
+	if leaderOnly {
+		selector["statefulset.kubernetes.io/pod-name"] = fmt.Sprintf("%s-0", wObj.Name)
+	}
+
+	pool := newInferenceExtensionObject(InferencePoolGVK, wObj, wObj.Name)
+	pool.Object["spec"] = map[string]interface{}{
+		"targetPortNumber": int64(GetInferenceServiceTargetPort(wObj)),
+		"selector":         selector,
+		"extensionRef": map[string]interface{}{
+			"name":        GetEndpointPickerName(wObj),
+			"portNumber":  int64(EndpointPickerGRPCPort),
+			"failureMode": "FailClose",
+		},
+	}
+	return pool
+}
+
+// GenerateInferenceModelManifest generates the InferenceModel routing requests for modelName to the InferencePool
+// of the workspace. modelName is the served model name of the base model or the name of an adapter.
+func GenerateInferenceModelManifest(wObj *kaitov1beta1.Workspace, objectName, modelName string) *unstructured.Unstructured {
+	inferenceModel := newInferenceExtensionObject(InferenceModelGVK, wObj, objectName)
+	inferenceModel.Object["spec"] = map[string]interface{}{
+		"modelName":   modelName,
+		"criticality": "Critical",
+		"poolRef": map[string]interface{}{
+			"name": wObj.Name,
+		},
+	}
+	return inferenceModel
+}
+
+func newInferenceExtensionObject(gvk schema.GroupVersionKind, wObj *kaitov1beta1.Workspace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(wObj.Namespace)
+	obj.SetLabels(map[string]string{
+		kaitov1beta1.LabelWorkspaceName: wObj.Name,
+	})
+	obj.SetOwnerReferences([]v1.OwnerReference{
+		*v1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+	})
+	return obj
+}
+
+// GenerateEndpointPickerManifests generates the endpoint picker of the InferencePool of the workspace, along with
+// the service the gateway calls and the RBAC objects granting it read access to the pool, its models and pods.
+func GenerateEndpointPickerManifests(wObj *kaitov1beta1.Workspace, image string) (*corev1.ServiceAccount, *rbacv1.Role, *rbacv1.RoleBinding, *appsv1.Deployment, *corev1.Service) {
+	name := GetEndpointPickerName(wObj)
+	objectMeta := func() v1.ObjectMeta {
+		return v1.ObjectMeta{
+			Name:      name,
+			Namespace: wObj.Namespace,
+			Labels: map[string]string{
+				kaitov1beta1.LabelWorkspaceName: wObj.Name,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		}
+	}
+	selector := map[string]string{
+		"app": name,
+	}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: objectMeta()}
+	role := &rbacv1.Role{
+		ObjectMeta: objectMeta(),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{InferencePoolGVK.Group},
+				Resources: []string{"inferencepools", "inferencemodels"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: objectMeta(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: wObj.Namespace,
+			},
+		},
+	}
+
+	healthProbe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			GRPC: &corev1.GRPCAction{
+				Port:    EndpointPickerHealthPort,
+				Service: ptr.To("inference-extension"),
+			},
+		},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: objectMeta(),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &v1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: selector,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: name,
+					Containers: []corev1.Container{
+						{
+							Name:  "epp",
+							Image: image,
+							Args: []string{
+								"-poolName", wObj.Name,
+								"-poolNamespace", wObj.Namespace,
+								"-v", "3",
+								"-grpcPort", fmt.Sprint(EndpointPickerGRPCPort),
+								"-grpcHealthPort", fmt.Sprint(EndpointPickerHealthPort),
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "grpc", ContainerPort: EndpointPickerGRPCPort},
+								{Name: "grpc-health", ContainerPort: EndpointPickerHealthPort},
+								{Name: "metrics", ContainerPort: EndpointPickerMetricsPort},
+							},
+							LivenessProbe:  healthProbe,
+							ReadinessProbe: healthProbe,
+						},
+					},
+				},
+			},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: objectMeta(),
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:        "grpc-ext-proc",
+					Protocol:    corev1.ProtocolTCP,
+					Port:        EndpointPickerGRPCPort,
+					TargetPort:  intstr.FromInt32(EndpointPickerGRPCPort),
+					AppProtocol: ptr.To("http2"),
+				},
+			},
+		},
+	}
+	return serviceAccount, role, roleBinding, deployment, service
+}
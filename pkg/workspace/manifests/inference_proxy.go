@@ -0,0 +1,141 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+)
+
+const (
+	// InferenceProxyContainerName is the name of the proxy container injected in front of the inference server.
+	InferenceProxyContainerName = "inference-proxy"
+	// PortInferenceProxy is the port of the inference proxy, targeted by the service instead of the inference server.
+	PortInferenceProxy = 5001
+	// PortInferenceProxyMetrics is the system port of the inference proxy, serving its metrics and the adapter
+	// management API of the inference server to the controller.
+	PortInferenceProxyMetrics = 5002
+
+	// DefaultInferenceProxyImage is the image of the inference proxy unless INFERENCE_PROXY_IMAGE is set,
+	// the proxy ships in the image of the workspace controller.
+	DefaultInferenceProxyImage = "mcr.microsoft.com/aks/kaito/workspace:0.5.1"
+
+	apiKeysVolumeName = "api-keys"
+	apiKeysMountPath  = "/etc/kaito/api-keys"
+)
+
+// InferenceProxyEnabled returns true if the inference pods of the workspace run the inference proxy. Besides
+// authentication and rate limiting, the proxy keeps the runtime LoRA endpoints of vLLM, enabled to load the
+// adapters of the workspace, away from the clients of the inference service.
+func InferenceProxyEnabled(wObj *kaitov1beta1.Workspace) bool {
+	if wObj.Inference == nil {
+		return false
+	}
+	return wObj.Inference.Auth != nil || wObj.Inference.RateLimit != nil ||
+		(wObj.Inference.Preset != nil && len(wObj.Inference.Adapters) > 0 &&
+			kaitov1beta1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameVLLM)
+}
+
+// GetInferenceServiceTargetPort returns the pod port the inference service of the workspace targets.
+func GetInferenceServiceTargetPort(wObj *kaitov1beta1.Workspace) int32 {
+	if InferenceProxyEnabled(wObj) {
+		return PortInferenceProxy
+	}
+	return 5000
+}
+
+// GetInferenceServerHost returns the address the inference server binds to, all interfaces if empty. Behind the
+// inference proxy, the inference server only listens on the loopback interface so that the proxy cannot be
+// bypassed through the pod IP.
+func GetInferenceServerHost(wObj *kaitov1beta1.Workspace) string {
+	if InferenceProxyEnabled(wObj) {
+		return "127.0.0.1"
+	}
+	return ""
+}
+
+// GetInferenceProxyImageName returns the image of the inference proxy.
+func GetInferenceProxyImageName() string {
+	if image := os.Getenv("INFERENCE_PROXY_IMAGE"); image != "" {
+		return image
+	}
+	return DefaultInferenceProxyImage
+}
+
+// GenerateInferenceProxyContainer generates the proxy container forwarding the requests of the inference
+// service to the inference server on port 5000, and the volumes it mounts.
+func GenerateInferenceProxyContainer(wObj *kaitov1beta1.Workspace) (corev1.Container, []corev1.Volume) {
+	args := []string{
+		fmt.Sprintf("--bind-address=:%d", PortInferenceProxy),
+		fmt.Sprintf("--metrics-bind-address=:%d", PortInferenceProxyMetrics),
+		"--upstream=http://127.0.0.1:5000",
+		"--workspace=" + wObj.Name,
+	}
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if auth := wObj.Inference.Auth; auth != nil {
+		args = append(args, "--api-keys-dir="+apiKeysMountPath)
+		volumes = append(volumes, corev1.Volume{
+			Name: apiKeysVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: auth.SecretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      apiKeysVolumeName,
+			MountPath: apiKeysMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if rateLimit := wObj.Inference.RateLimit; rateLimit != nil {
+		if rateLimit.RequestsPerMinute != nil {
+			args = append(args, fmt.Sprintf("--requests-per-minute=%d", *rateLimit.RequestsPerMinute))
+		}
+		if rateLimit.MaxConcurrentRequests != nil {
+			args = append(args, fmt.Sprintf("--max-concurrent-requests=%d", *rateLimit.MaxConcurrentRequests))
+		}
+	}
+
+	probe := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt32(PortInferenceProxy),
+				Path: "/healthz",
+			},
+		},
+		PeriodSeconds: 10,
+	}
+	return corev1.Container{
+		Name:    InferenceProxyContainerName,
+		Image:   GetInferenceProxyImageName(),
+		Command: []string{"/inference-proxy"},
+		Args:    args,
+		Ports: []corev1.ContainerPort{
+			{Name: "proxy", ContainerPort: PortInferenceProxy},
+			{Name: "proxy-metrics", ContainerPort: PortInferenceProxyMetrics},
+		},
+		LivenessProbe:  probe,
+		ReadinessProbe: probe,
+		VolumeMounts:   volumeMounts,
+	}, volumes
+}
@@ -17,11 +17,13 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -31,9 +33,90 @@ import (
 	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/generator"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/image"
 )
 
+// GPUResourceClaimName is the name under which pods reference the GPUs claimed through DRA.
+const GPUResourceClaimName = "gpu"
+
+// GetGPUResourceClaimTemplateName returns the name of the workspace's ResourceClaimTemplate.
+func GetGPUResourceClaimTemplateName(wObj *kaitov1beta1.Workspace) string {
+	return fmt.Sprintf("%s-gpu", wObj.Name)
+}
+
+// GenerateGPUResourceClaimTemplateManifest generates a ResourceClaimTemplate that requests deviceCount GPUs
+// per pod, selected by the device attributes in the workspace's dynamicResourceAllocation settings.
+func GenerateGPUResourceClaimTemplateManifest(wObj *kaitov1beta1.Workspace, deviceCount int) *resourcev1beta1.ResourceClaimTemplate {
+	dra := wObj.Resource.DynamicResourceAllocation
+
+	var selectors []resourcev1beta1.DeviceSelector
+	if dra.ProductName != "" {
+		selectors = append(selectors, resourcev1beta1.DeviceSelector{
+			CEL: &resourcev1beta1.CELDeviceSelector{
+				Expression: fmt.Sprintf("device.attributes[device.driver].productName == %q", dra.ProductName),
+			},
+		})
+	}
+	if dra.MinMemory != nil {
+		selectors = append(selectors, resourcev1beta1.DeviceSelector{
+			CEL: &resourcev1beta1.CELDeviceSelector{
+				Expression: fmt.Sprintf("device.capacity[device.driver].memory.compareTo(quantity(%q)) >= 0", dra.MinMemory.String()),
+			},
+		})
+	}
+
+	return &resourcev1beta1.ResourceClaimTemplate{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      GetGPUResourceClaimTemplateName(wObj),
+			Namespace: wObj.Namespace,
+			Labels: map[string]string{
+				kaitov1beta1.LabelWorkspaceName: wObj.Name,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Spec: resourcev1beta1.ResourceClaimTemplateSpec{
+			Spec: resourcev1beta1.ResourceClaimSpec{
+				Devices: resourcev1beta1.DeviceClaim{
+					Requests: []resourcev1beta1.DeviceRequest{
+						{
+							Name:            GPUResourceClaimName,
+							DeviceClassName: dra.GetDeviceClassName(),
+							Selectors:       selectors,
+							AllocationMode:  resourcev1beta1.DeviceAllocationModeExactCount,
+							Count:           int64(deviceCount),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// SetGPUResourceClaim makes the pod claim its GPUs from the workspace's ResourceClaimTemplate
+// instead of requesting device plugin resources. The first container is the one running the model,
+// it keeps its other resource requirements.
+func SetGPUResourceClaim(wObj *kaitov1beta1.Workspace, spec *corev1.PodSpec) {
+	spec.ResourceClaims = append(spec.ResourceClaims, corev1.PodResourceClaim{
+		Name:                      GPUResourceClaimName,
+		ResourceClaimTemplateName: ptr.To(GetGPUResourceClaimTemplateName(wObj)),
+	})
+	if len(spec.Containers) == 0 {
+		return
+	}
+	requirements := &spec.Containers[0].Resources
+	for _, list := range []corev1.ResourceList{requirements.Requests, requirements.Limits} {
+		for name := range list {
+			if name == resources.CapacityNvidiaGPU || name == resources.CapacityAMDGPU || strings.HasPrefix(string(name), resources.CapacityMIGPrefix) {
+				delete(list, name)
+			}
+		}
+	}
+	requirements.Claims = append(requirements.Claims, corev1.ResourceClaim{Name: GPUResourceClaimName})
+}
+
 func GenerateHeadlessServiceManifest(workspaceObj *kaitov1beta1.Workspace) *corev1.Service {
 	serviceName := fmt.Sprintf("%s-headless", workspaceObj.Name)
 	selector := map[string]string{
@@ -83,7 +166,7 @@ func GenerateServiceManifest(workspaceObj *kaitov1beta1.Workspace, serviceType c
 					Name:       "http",
 					Protocol:   corev1.ProtocolTCP,
 					Port:       80,
-					TargetPort: intstr.FromInt32(5000),
+					TargetPort: intstr.FromInt32(GetInferenceServiceTargetPort(workspaceObj)),
 				},
 				{
 					Name:       "ray",
@@ -198,6 +281,24 @@ func GenerateTuningJobManifest(wObj *kaitov1beta1.Workspace, revisionNum string,
 		shouldShareProcessNamespace = ptr.To(false)
 	}
 
+	var podFailurePolicy *batchv1.PodFailurePolicy
+	if wObj.IsSpot() {
+		// Pods evicted together with a spot node are retried without counting towards the backoff limit.
+		podFailurePolicy = &batchv1.PodFailurePolicy{
+			Rules: []batchv1.PodFailurePolicyRule{
+				{
+					Action: batchv1.PodFailurePolicyActionIgnore,
+					OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{
+						{
+							Type:   corev1.DisruptionTarget,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+		}
+	}
+
 	return &batchv1.Job{
 		TypeMeta: v1.TypeMeta{
 			APIVersion: "batch/v1",
@@ -215,6 +316,7 @@ func GenerateTuningJobManifest(wObj *kaitov1beta1.Workspace, revisionNum string,
 			},
 		},
 		Spec: batchv1.JobSpec{
+			PodFailurePolicy: podFailurePolicy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: v1.ObjectMeta{
 					Labels: labels,
@@ -233,6 +335,47 @@ func GenerateTuningJobManifest(wObj *kaitov1beta1.Workspace, revisionNum string,
 	}
 }
 
+// GetTensorRTLLMEngineBuildJobName returns the name of the job compiling the TensorRT-LLM engine of the workspace.
+func GetTensorRTLLMEngineBuildJobName(wObj *kaitov1beta1.Workspace) string {
+	return wObj.Name + "-engine-build"
+}
+
+// GenerateTensorRTLLMEngineBuildJobManifest generates the job compiling the TensorRT-LLM engine of the workspace,
+// the job is annotated with the reference of the engine it pushes to the engine cache.
+func GenerateTensorRTLLMEngineBuildJobManifest(wObj *kaitov1beta1.Workspace, revisionNum string, engineImage string, podSpec corev1.PodSpec) *batchv1.Job {
+	labels := map[string]string{
+		kaitov1beta1.LabelWorkspaceName: wObj.Name,
+	}
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+
+	return &batchv1.Job{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:      GetTensorRTLLMEngineBuildJobName(wObj),
+			Namespace: wObj.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				kaitov1beta1.WorkspaceRevisionAnnotation: revisionNum,
+				kaitov1beta1.AnnotationTensorRTLLMEngine: engineImage,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
 func GenerateDeploymentManifest(revisionNum string, replicas int) func(*generator.WorkspaceGeneratorContext, *appsv1.Deployment) error {
 	return func(ctx *generator.WorkspaceGeneratorContext, d *appsv1.Deployment) error {
 		selector := map[string]string{
@@ -0,0 +1,102 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
+	"github.com/kaito-project/kaito/pkg/utils/networkpolicy"
+)
+
+const (
+	// LabelKaitoComponent labels the pods of the KAITO namespace that reach the inference pods of the workspaces,
+	// the NetworkPolicies of the workspaces only admit these pods from the KAITO namespace.
+	LabelKaitoComponent = "kaito.sh/component"
+	// KaitoComponentController is the component of the workspace controller, which collects the metrics of the
+	// inference proxy and the loaded adapters through its system port.
+	KaitoComponentController = "workspace-controller"
+	// KaitoComponentRouter is the component of the model-name router, which forwards the requests to the
+	// inference pods and scrapes the metrics of the inference server.
+	KaitoComponentRouter = "router"
+)
+
+// GenerateNetworkPolicyManifest generates the NetworkPolicy restricting the traffic to the inference pods of
+// the workspace. The inference service is reachable from the allowed peers, or from all sources if it is a
+// LoadBalancer service, and all other ports, e.g. the Ray ports of multi-node inference, only from the pods of
+// the workspace. The endpoint picker of the Gateway API Inference Extension, and the router of the KAITO
+// namespace if the workspace allows it, can reach the port targeted by the inference service, and the workspace
+// controller the system port of the inference proxy. The inference server itself only listens on the loopback interface behind the proxy.
+func GenerateNetworkPolicyManifest(wObj *kaitov1beta1.Workspace, serviceType corev1.ServiceType, systemNamespace string, inferenceExtension bool) *networkingv1.NetworkPolicy {
+	spec := wObj.Inference.NetworkPolicy
+	workspaceSelector := v1.LabelSelector{
+		MatchLabels: map[string]string{kaitov1beta1.LabelWorkspaceName: wObj.Name},
+	}
+
+	targetPort := GetInferenceServiceTargetPort(wObj)
+	rules := []networkingv1.NetworkPolicyIngressRule{
+		networkpolicy.ServiceIngressRule(spec, wObj.Namespace, serviceType, targetPort),
+		{
+			From: []networkingv1.NetworkPolicyPeer{{PodSelector: &workspaceSelector}},
+		},
+	}
+	if systemNamespace != "" {
+		// The router does not authenticate its clients, so it would let any pod reach the workspace.
+		if spec.AllowRouter {
+			rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{networkpolicy.NamespacePeer(systemNamespace, &v1.LabelSelector{
+					MatchLabels: map[string]string{LabelKaitoComponent: KaitoComponentRouter},
+				})},
+				Ports: networkpolicy.TCPPorts(targetPort),
+			})
+		}
+		if InferenceProxyEnabled(wObj) {
+			rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{networkpolicy.NamespacePeer(systemNamespace, &v1.LabelSelector{
+					MatchLabels: map[string]string{LabelKaitoComponent: KaitoComponentController},
+				})},
+				Ports: networkpolicy.TCPPorts(PortInferenceProxyMetrics),
+			})
+		}
+	}
+	if inferenceExtension {
+		// The gateway routes the requests of the InferencePool through the inference proxy.
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &v1.LabelSelector{MatchLabels: map[string]string{"app": GetEndpointPickerName(wObj)}},
+			}},
+			Ports: networkpolicy.TCPPorts(targetPort),
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      wObj.Name,
+			Namespace: wObj.Namespace,
+			Labels: map[string]string{
+				kaitov1beta1.LabelWorkspaceName: wObj.Name,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				*v1.NewControllerRef(wObj, kaitov1beta1.GroupVersion.WithKind("Workspace")),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: workspaceSelector,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+}
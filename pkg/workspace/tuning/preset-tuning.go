@@ -28,6 +28,7 @@ import (
 
 	kaitov1beta1 "github.com/kaito-project/kaito/api/v1beta1"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
@@ -63,6 +64,12 @@ var (
 			Value:  consts.GPUString,
 			Key:    consts.SKUString,
 		},
+		{
+			Effect:   corev1.TaintEffectNoSchedule,
+			Value:    consts.AzureSpotPriority,
+			Key:      consts.LabelAzureScaleSetPriority,
+			Operator: corev1.TolerationOpEqual,
+		},
 	}
 )
 
@@ -74,8 +81,8 @@ func getInstanceGPUCount(sku string) int {
 	return 1
 }
 
-func GetTuningImageInfo() string {
-	presetObj := metadata.MustGet("base")
+func GetTuningImageInfo(vendor sku.GPUVendor) string {
+	presetObj := metadata.MustGetBase(vendor)
 	return utils.GetPresetImageName(presetObj.Name, presetObj.Tag)
 }
 
@@ -229,7 +236,7 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 	}
 
 	var skuNumGPUs int
-	gpuConfig, err := utils.GetGPUConfigBySKU(workspaceObj.Resource.InstanceType)
+	gpuConfig, err := utils.GetGPUConfigBySKU(workspaceObj.ActiveInstanceType())
 	if err != nil {
 		gpuConfig, err = utils.TryGetGPUConfigFromNode(ctx, kubeClient, workspaceObj.Status.WorkerNodes)
 		if err != nil {
@@ -241,8 +248,8 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 		skuNumGPUs = gpuConfig.GPUCount
 	}
 
-	commands, resourceReq := prepareTuningParameters(ctx, workspaceObj, modelCommand, tuningObj, skuNumGPUs)
-	tuningImage := GetTuningImageInfo()
+	commands, resourceReq := prepareTuningParameters(ctx, workspaceObj, modelCommand, tuningObj, skuNumGPUs, gpuConfig.Vendor())
+	tuningImage := GetTuningImageInfo(gpuConfig.Vendor())
 
 	var envVars []corev1.EnvVar
 	presetName := strings.ToLower(string(workspaceObj.Tuning.Preset.Name))
@@ -254,10 +261,21 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 		})
 	}
 	// Add Expandable Memory Feature to reduce Peak GPU Mem Usage
+	allocConfEnv := "PYTORCH_CUDA_ALLOC_CONF"
+	if gpuConfig.Vendor() == sku.GPUVendorAMD {
+		allocConfEnv = "PYTORCH_HIP_ALLOC_CONF"
+	}
 	envVars = append(envVars, corev1.EnvVar{
-		Name:  "PYTORCH_CUDA_ALLOC_CONF",
+		Name:  allocConfEnv,
 		Value: "expandable_segments:True",
 	})
+	// Spot nodes can be evicted at any time, resume from the last checkpoint in the output directory on restart.
+	if workspaceObj.IsSpot() {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "RESUME_FROM_CHECKPOINT",
+			Value: "true",
+		})
+	}
 
 	// remove duplicate volumes
 	seen := make(map[string]bool)
@@ -273,6 +291,14 @@ func CreatePresetTuning(ctx context.Context, workspaceObj *kaitov1beta1.Workspac
 	jobObj := manifests.GenerateTuningJobManifest(workspaceObj, revisionNum, tuningImage, imagePullSecrets, *workspaceObj.Resource.Count, commands,
 		containerPorts, nil, nil, resourceReq, tolerations, initContainers, sidecarContainers, volumes, volumeMounts, envVars)
 
+	if workspaceObj.UsesDynamicResourceAllocation() {
+		claimTemplate := manifests.GenerateGPUResourceClaimTemplateManifest(workspaceObj, skuNumGPUs)
+		if err := resources.EnsureResource(ctx, claimTemplate, kubeClient); err != nil {
+			return nil, fmt.Errorf("failed to ensure ResourceClaimTemplate %s: %w", claimTemplate.Name, err)
+		}
+		manifests.SetGPUResourceClaim(workspaceObj, &jobObj.Spec.Template.Spec)
+	}
+
 	err = resources.CreateResource(ctx, jobObj, kubeClient)
 	if client.IgnoreAlreadyExists(err) != nil {
 		return nil, err
@@ -396,23 +422,23 @@ func prepareModelRunParameters(ctx context.Context, tuningObj *model.PresetParam
 // and sets the GPU resources required for tuning.
 // Returns the command and resource configuration.
 func prepareTuningParameters(ctx context.Context, wObj *kaitov1beta1.Workspace, modelCommand string,
-	tuningObj *model.PresetParam, skuNumGPUs int) ([]string, corev1.ResourceRequirements) {
+	tuningObj *model.PresetParam, skuNumGPUs int, vendor sku.GPUVendor) ([]string, corev1.ResourceRequirements) {
 	hfParam := tuningObj.Transformers // Only support Huggingface for now
 	if hfParam.AccelerateParams == nil {
 		hfParam.AccelerateParams = make(map[string]string)
 	}
 	// Set # of processes to GPU Count
-	numProcesses := getInstanceGPUCount(wObj.Resource.InstanceType)
+	numProcesses := getInstanceGPUCount(wObj.ActiveInstanceType())
 	hfParam.AccelerateParams["num_processes"] = fmt.Sprintf("%d", numProcesses)
 	torchCommand := utils.BuildCmdStr(hfParam.BaseCommand, hfParam.AccelerateParams)
 	commands := utils.ShellCmd(torchCommand + " " + modelCommand)
 
 	resourceRequirements := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+			resources.GPUResourceName(vendor): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 		},
 		Limits: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
+			resources.GPUResourceName(vendor): *resource.NewQuantity(int64(skuNumGPUs), resource.DecimalSI),
 		},
 	}
 
@@ -25,7 +25,7 @@ import os
 """
 
 # Embedding configuration
-EMBEDDING_SOURCE_TYPE = os.getenv("EMBEDDING_SOURCE_TYPE", "local")  # Determines local or remote embedding source
+EMBEDDING_SOURCE_TYPE = os.getenv("EMBEDDING_SOURCE_TYPE", os.getenv("EMBEDDING_TYPE", "local"))  # Determines local, remote or workspace embedding source
 
 # Local embedding model
 LOCAL_EMBEDDING_MODEL_ID = os.getenv("LOCAL_EMBEDDING_MODEL_ID", "BAAI/bge-small-en-v1.5")
@@ -34,6 +34,10 @@ LOCAL_EMBEDDING_MODEL_ID = os.getenv("LOCAL_EMBEDDING_MODEL_ID", "BAAI/bge-small
 REMOTE_EMBEDDING_URL = os.getenv("REMOTE_EMBEDDING_URL", "http://localhost:5000/embedding")
 REMOTE_EMBEDDING_ACCESS_SECRET = os.getenv("REMOTE_EMBEDDING_ACCESS_SECRET", "default-access-secret")
 
+# Kaito Workspace serving a text-embedding preset
+WORKSPACE_EMBEDDING_URL = os.getenv("WORKSPACE_EMBEDDING_URL", "http://localhost:5000")
+WORKSPACE_EMBEDDING_ACCESS_SECRET = os.getenv("WORKSPACE_EMBEDDING_ACCESS_SECRET", "")  # API key of workspaces with inference.auth
+
 """
 =========================================================================
 """
@@ -0,0 +1,70 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+from typing import Any, Optional
+import requests
+from .base import BaseEmbeddingModel
+from ragengine.metrics.helpers import record_embedding_metrics
+
+class WorkspaceEmbeddingModel(BaseEmbeddingModel):
+    model_url: str
+    api_key: str = ""
+    served_model_name: Optional[str] = None
+
+    def __init__(self, model_url: str, api_key: str = "", /, **data: Any):
+        """
+        Initialize the WorkspaceEmbeddingModel.
+
+        Args:
+            model_url (str): The URL of the inference service of a Kaito Workspace serving a
+                text-embedding preset, which exposes the OpenAI-compatible embeddings API.
+            api_key (str): The API key sent to workspaces requiring one with inference.auth, if any.
+        """
+        super().__init__(model_url=model_url.rstrip("/"), api_key=api_key, **data)
+
+    def _headers(self) -> dict:
+        """Returns the headers authenticating the requests to the workspace."""
+        return {"Authorization": f"Bearer {self.api_key}"} if self.api_key else {}
+
+    def _get_served_model_name(self) -> str:
+        """Returns the name of the model served by the workspace, which is required by the embeddings API."""
+        if self.served_model_name is None:
+            response = requests.get(f"{self.model_url}/v1/models", headers=self._headers())
+            response.raise_for_status()
+            self.served_model_name = response.json()["data"][0]["id"]
+        return self.served_model_name
+
+    @record_embedding_metrics
+    def _get_text_embedding(self, text: str):
+        """Returns the text embedding for a given input string."""
+        payload = {
+            "model": self._get_served_model_name(),
+            "input": text
+        }
+
+        try:
+            response = requests.post(f"{self.model_url}/v1/embeddings", headers=self._headers(), json=payload)
+            response.raise_for_status()  # Raise an HTTPError for bad responses
+            return response.json()["data"][0]["embedding"]
+        except requests.exceptions.RequestException as e:
+            raise RuntimeError(f"Failed to get embedding from workspace: {e}")
+
+    def _get_query_embedding(self, query: str):
+        return self._get_text_embedding(query)
+
+    def get_embedding_dimension(self) -> int:
+        """Infers the embedding dimension by making a call to get the embedding of a dummy text."""
+        dummy_input = "This is a dummy sentence."
+        embedding = self._get_text_embedding(dummy_input)
+
+        return len(embedding)
@@ -17,13 +17,15 @@ import json
 from vector_store_manager.manager import VectorStoreManager
 from embedding.huggingface_local_embedding import LocalHuggingFaceEmbedding
 from embedding.remote_embedding import RemoteEmbeddingModel
+from embedding.workspace_embedding import WorkspaceEmbeddingModel
 from fastapi import FastAPI, HTTPException, Query, Request
 from models import (IndexRequest, ListDocumentsResponse, UpdateDocumentRequest,
                     QueryRequest, QueryResponse, Document, HealthStatus, DeleteDocumentRequest,
                     DeleteDocumentResponse, UpdateDocumentResponse)
 from vector_store.faiss_store import FaissVectorStoreHandler
 
-from ragengine.config import (REMOTE_EMBEDDING_URL, REMOTE_EMBEDDING_ACCESS_SECRET,
+from ragengine.config import (REMOTE_EMBEDDING_URL, REMOTE_EMBEDDING_ACCESS_SECRET, WORKSPACE_EMBEDDING_URL,
+                              WORKSPACE_EMBEDDING_ACCESS_SECRET,
                               EMBEDDING_SOURCE_TYPE, LOCAL_EMBEDDING_MODEL_ID, DEFAULT_VECTOR_DB_PERSIST_DIR)
 from urllib.parse import unquote
 import os
@@ -58,7 +60,8 @@ from ragengine.metrics.prometheus_metrics import (
     STATUS_SUCCESS,
     STATUS_FAILURE,
     MODE_LOCAL,
-    MODE_REMOTE
+    MODE_REMOTE,
+    MODE_WORKSPACE
 )
 
 app = FastAPI()
@@ -91,8 +94,10 @@ if EMBEDDING_SOURCE_TYPE.lower() == MODE_LOCAL:
     embedding_manager = LocalHuggingFaceEmbedding(LOCAL_EMBEDDING_MODEL_ID)
 elif EMBEDDING_SOURCE_TYPE.lower() == MODE_REMOTE:
     embedding_manager = RemoteEmbeddingModel(REMOTE_EMBEDDING_URL, REMOTE_EMBEDDING_ACCESS_SECRET)
+elif EMBEDDING_SOURCE_TYPE.lower() == MODE_WORKSPACE:
+    embedding_manager = WorkspaceEmbeddingModel(WORKSPACE_EMBEDDING_URL, WORKSPACE_EMBEDDING_ACCESS_SECRET)
 else:
-    raise ValueError("Invalid Embedding Type Specified (Must be Local or Remote)")
+    raise ValueError("Invalid Embedding Type Specified (Must be Local, Remote or Workspace)")
 
 # Initialize vector store
 # TODO: Dynamically set VectorStore from EnvVars (which ultimately comes from CRD StorageSpec)
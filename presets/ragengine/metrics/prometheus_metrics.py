@@ -22,6 +22,7 @@ STATUS_SUCCESS = "success"
 STATUS_FAILURE = "failure"
 MODE_LOCAL = "local"
 MODE_REMOTE = "remote"
+MODE_WORKSPACE = "workspace"
 
 # Embedding metrics
 rag_embedding_latency = Histogram(
@@ -0,0 +1,94 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import argparse
+import logging
+import os
+import sys
+from typing import Any, List
+
+import yaml
+from sglang.srt.entrypoints.http_server import launch_server
+from sglang.srt.server_args import prepare_server_args
+
+# Initialize logger
+logger = logging.getLogger(__name__)
+debug_mode = os.environ.get('DEBUG_MODE', 'false').lower() == 'true'
+logging.basicConfig(
+    level=logging.DEBUG if debug_mode else logging.INFO,
+    format='%(levelname)s %(asctime)s %(filename)s:%(lineno)d] %(message)s',
+    datefmt='%m-%d %H:%M:%S')
+
+# Defaults of the SGLang server in a KAITO preset, they can be overridden
+# by the command line or the sglang section of the KAITO config file.
+DEFAULT_SGLANG_ARGS = {
+    "model-path": "/workspace/sglang/weights",
+    "host": "0.0.0.0",
+    "port": 5000,
+}
+
+def parse_kaito_args(argv: List[str]):
+    # KAITO only args, they start with "kaito-" prefix to avoid conflict with sglang args.
+    parser = argparse.ArgumentParser(description="KAITO SGLang serving server", add_help=False)
+    parser.add_argument("--kaito-adapters-dir", type=str, default="/mnt/adapter", help="Directory where adapters are stored in KAITO preset.")
+    parser.add_argument("--kaito-config-file", type=str, default="", help="Additional args for KAITO preset.")
+    return parser.parse_known_args(argv)
+
+def load_config_args(config_file: str) -> dict[str, Any]:
+    if not config_file:
+        return {}
+    with open(config_file, 'r') as file:
+        config_data = yaml.safe_load(file) or {}
+    return config_data.get('sglang', {}) or {}
+
+def load_lora_paths(adapters_dir: str) -> List[str]:
+    lora_paths: List[str] = []
+    if not os.path.exists(adapters_dir):
+        return lora_paths
+
+    logger.info(f"Loading LoRA adapters from {adapters_dir}")
+    for adapter in sorted(os.listdir(adapters_dir)):
+        adapter_path = os.path.join(adapters_dir, adapter)
+        if os.path.isdir(adapter_path):
+            lora_paths.append(f"{adapter}={adapter_path}")
+    return lora_paths
+
+def build_sglang_argv(argv: List[str]) -> List[str]:
+    kaito_args, runtime_argv = parse_kaito_args(argv)
+
+    # command line args take precedence over the config file, which takes precedence over the defaults
+    args = dict(DEFAULT_SGLANG_ARGS)
+    args.update(load_config_args(kaito_args.kaito_config_file))
+    given = {arg.split("=", 1)[0][2:] for arg in runtime_argv if arg.startswith("--")}
+
+    sglang_argv: List[str] = []
+    for key, value in args.items():
+        if key in given:
+            continue
+        if isinstance(value, bool):
+            if value:
+                sglang_argv.append(f"--{key}")
+            continue
+        sglang_argv.extend([f"--{key}", str(value)])
+    sglang_argv.extend(runtime_argv)
+
+    lora_paths = load_lora_paths(kaito_args.kaito_adapters_dir)
+    if lora_paths:
+        sglang_argv.append("--lora-paths")
+        sglang_argv.extend(lora_paths)
+    return sglang_argv
+
+if __name__ == "__main__":
+    sglang_argv = build_sglang_argv(sys.argv[1:])
+    logger.info(f"Starting SGLang server with args: {sglang_argv}")
+    launch_server(prepare_server_args(sglang_argv))
@@ -0,0 +1,72 @@
+# Copyright (c) KAITO authors.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import argparse
+import logging
+import os
+from typing import List, Optional
+
+from tensorrt_llm import LLM, BuildConfig
+from transformers import AutoTokenizer
+
+# Initialize logger
+logger = logging.getLogger(__name__)
+debug_mode = os.environ.get('DEBUG_MODE', 'false').lower() == 'true'
+logging.basicConfig(
+    level=logging.DEBUG if debug_mode else logging.INFO,
+    format='%(levelname)s %(asctime)s %(filename)s:%(lineno)d] %(message)s',
+    datefmt='%m-%d %H:%M:%S')
+
+def parse_args(argv: Optional[List[str]] = None):
+    parser = argparse.ArgumentParser(description="KAITO TensorRT-LLM engine builder")
+    parser.add_argument("--model", type=str, default="/workspace/weights", help="Local weights directory or huggingface repository of the model.")
+    parser.add_argument("--revision", type=str, default=None, help="Revision of the huggingface repository.")
+    parser.add_argument("--download-dir", type=str, default=None, help="Directory where the huggingface weights are downloaded.")
+    parser.add_argument("--tp-size", type=int, default=1, help="Tensor parallel degree of the engine.")
+    parser.add_argument("--output-dir", type=str, required=True, help="Directory where the engine and the tokenizer are saved.")
+    parser.add_argument("--max-batch-size", type=int, default=None, help="Maximum number of requests in a batch.")
+    parser.add_argument("--max-seq-len", type=int, default=None, help="Maximum total length of a request.")
+    parser.add_argument("--max-num-tokens", type=int, default=None, help="Maximum number of batched tokens.")
+    return parser.parse_args(argv)
+
+def build_config(args) -> BuildConfig:
+    config = BuildConfig()
+    if args.max_batch_size is not None:
+        config.max_batch_size = args.max_batch_size
+    if args.max_seq_len is not None:
+        config.max_seq_len = args.max_seq_len
+    if args.max_num_tokens is not None:
+        config.max_num_tokens = args.max_num_tokens
+    return config
+
+def build_engine(args):
+    if args.download_dir:
+        os.environ["HF_HOME"] = args.download_dir
+
+    logger.info(f"Building TensorRT-LLM engine of {args.model} with tp size {args.tp_size}")
+    llm = LLM(
+        model=args.model,
+        revision=args.revision,
+        tensor_parallel_size=args.tp_size,
+        build_config=build_config(args),
+    )
+    llm.save(args.output_dir)
+
+    # trtllm-serve loads the tokenizer from the engine directory, the raw weights
+    # are not available to the inference pods.
+    tokenizer = AutoTokenizer.from_pretrained(args.model, revision=args.revision)
+    tokenizer.save_pretrained(args.output_dir)
+    logger.info(f"Saved TensorRT-LLM engine to {args.output_dir}")
+
+if __name__ == "__main__":
+    build_engine(parse_args())
@@ -65,6 +65,7 @@ class ModelConfig:
     torch_dtype: Optional[str] = field(default=None, metadata={"help": "The torch dtype for the pre-trained model"})
     device_map: str = field(default="auto", metadata={"help": "The device map for the pre-trained model"})
     chat_template: Optional[str] = field(default=None, metadata={"help": "The file path to the chat template, or the template in single-line form for the specified model"})
+    host: str = field(default="0.0.0.0", metadata={"help": "The address the server binds to"})
 
     # Method to process additional arguments
     def process_additional_args(self, addt_args: List[str]):
@@ -129,6 +130,7 @@ model_args = asdict(args)
 model_args["local_files_only"] = not model_args.pop('allow_remote_files')
 model_pipeline = model_args.pop('pipeline')
 combination_type = model_args.pop('combination_type')
+server_host = model_args.pop('host')
 
 app = FastAPI()
 resovled_chat_template = load_chat_template(model_args.pop('chat_template'))
@@ -536,4 +538,4 @@ if __name__ == "__main__":
     local_rank = int(os.environ.get("LOCAL_RANK", 0)) # Default to 0 if not set
     port = 5000 + local_rank # Adjust port based on local rank
     logger.info(f"Starting server on port {port}")
-    uvicorn.run(app=app, host='0.0.0.0', port=port)
+    uvicorn.run(app=app, host=server_host, port=port)
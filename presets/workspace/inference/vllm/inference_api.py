@@ -127,6 +127,9 @@ def load_lora_adapters(adapters_dir: str) -> Optional[LoRAModulePath]:
 
     logger.info(f"Loading LoRA adapters from {adapters_dir}")
     for adapter in os.listdir(adapters_dir):
+        # Adapters are pulled into hidden directories until they are complete.
+        if adapter.startswith("."):
+            continue
         adapter_path = os.path.join(adapters_dir, adapter)
         if os.path.isdir(adapter_path):
             lora_list.append(LoRAModulePath(adapter, adapter_path))
@@ -26,7 +26,7 @@ parent_dir = str(Path(__file__).resolve().parent.parent)
 # Add the parent directory to sys.path
 sys.path.append(parent_dir)
 
-from inference_api import binary_search_with_limited_steps, KaitoConfig
+from inference_api import binary_search_with_limited_steps, load_lora_adapters, KaitoConfig
 from huggingface_hub import snapshot_download
 import shutil
 
@@ -201,3 +201,12 @@ def test_binary_search_with_limited_steps():
 
     result = binary_search_with_limited_steps(20, 100, lambda x: False)
     assert result == 0, f"Expected 0, but got {result}"
+
+def test_load_lora_adapters_skips_pulling(tmp_path):
+    for adapter in ["adapter-a", "adapter-b", ".pulling"]:
+        (tmp_path / adapter).mkdir()
+    (tmp_path / "adapter-list").touch()
+
+    # Adapters still being pulled by the sidecar are loaded at runtime once complete.
+    names = sorted(lora.name for lora in load_lora_adapters(str(tmp_path)))
+    assert names == ["adapter-a", "adapter-b"], f"Expected the complete adapters, but got {names}"
@@ -0,0 +1,145 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bge
+
+import (
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	metadata "github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+func init() {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     PresetBGESmallEnV1_5Model,
+		Instance: &bgeSmallEnV1_5A,
+	})
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     PresetBGELargeEnV1_5Model,
+		Instance: &bgeLargeEnV1_5A,
+	})
+}
+
+const (
+	PresetBGESmallEnV1_5Model = "bge-small-en-v1.5"
+	PresetBGELargeEnV1_5Model = "bge-large-en-v1.5"
+)
+
+var (
+	// bge models are served by the vLLM embedding task, inputs are truncated to the
+	// 512 tokens the models were trained with.
+	bgeRunParamsVLLM = map[string]string{
+		"task":          "embed",
+		"dtype":         "float16",
+		"max-model-len": "512",
+	}
+	bgeRunParamsLlamaCpp = map[string]string{
+		"embeddings": "",
+		"pooling":    "cls",
+	}
+)
+
+var bgeSmallEnV1_5A bgeSmallEnV1_5
+
+type bgeSmallEnV1_5 struct{}
+
+func (*bgeSmallEnV1_5) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Metadata:                  metadata.MustGet(PresetBGESmallEnV1_5Model),
+		DiskStorageRequirement:    "60Gi",
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "2Gi",
+		PerGPUMemoryRequirement:   "0Gi", // The model fits on a single GPU, no per GPU memory requirement.
+		EmbeddingDimension:        384,
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelName:      PresetBGESmallEnV1_5Model,
+				ModelRunParams: bgeRunParamsVLLM,
+				DisallowLoRA:   true,
+			},
+			LlamaCpp: model.LlamaCppParam{
+				BaseCommand:    inference.DefaultLlamaCppCommand,
+				ModelName:      PresetBGESmallEnV1_5Model,
+				GGUFRepo:       "CompendiumLabs/bge-small-en-v1.5-gguf",
+				GGUFFile:       "bge-small-en-v1.5-f16.gguf",
+				GGUFFileSize:   "67Mi",
+				ModelRunParams: bgeRunParamsLlamaCpp,
+			},
+			// The model is too small to be split across GPUs.
+			DisableTensorParallelism: true,
+		},
+		ReadinessTimeout: time.Duration(30) * time.Minute,
+	}
+}
+
+func (*bgeSmallEnV1_5) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (*bgeSmallEnV1_5) SupportDistributedInference() bool {
+	return false
+}
+
+func (*bgeSmallEnV1_5) SupportTuning() bool {
+	return false
+}
+
+var bgeLargeEnV1_5A bgeLargeEnV1_5
+
+type bgeLargeEnV1_5 struct{}
+
+func (*bgeLargeEnV1_5) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Metadata:                  metadata.MustGet(PresetBGELargeEnV1_5Model),
+		DiskStorageRequirement:    "60Gi",
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "4Gi",
+		PerGPUMemoryRequirement:   "0Gi", // The model fits on a single GPU, no per GPU memory requirement.
+		EmbeddingDimension:        1024,
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelName:      PresetBGELargeEnV1_5Model,
+				ModelRunParams: bgeRunParamsVLLM,
+				DisallowLoRA:   true,
+			},
+			LlamaCpp: model.LlamaCppParam{
+				BaseCommand:    inference.DefaultLlamaCppCommand,
+				ModelName:      PresetBGELargeEnV1_5Model,
+				GGUFRepo:       "CompendiumLabs/bge-large-en-v1.5-gguf",
+				GGUFFile:       "bge-large-en-v1.5-f16.gguf",
+				GGUFFileSize:   "670Mi",
+				ModelRunParams: bgeRunParamsLlamaCpp,
+			},
+			// The model is too small to be split across GPUs.
+			DisableTensorParallelism: true,
+		},
+		ReadinessTimeout: time.Duration(30) * time.Minute,
+	}
+}
+
+func (*bgeLargeEnV1_5) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (*bgeLargeEnV1_5) SupportDistributedInference() bool {
+	return false
+}
+
+func (*bgeLargeEnV1_5) SupportTuning() bool {
+	return false
+}
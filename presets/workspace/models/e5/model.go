@@ -0,0 +1,83 @@
+// Copyright (c) KAITO authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e5
+
+import (
+	"time"
+
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	metadata "github.com/kaito-project/kaito/presets/workspace/models"
+)
+
+func init() {
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     PresetE5LargeV2Model,
+		Instance: &e5LargeV2A,
+	})
+}
+
+const (
+	PresetE5LargeV2Model = "e5-large-v2"
+)
+
+var (
+	// e5 models are served by the vLLM embedding task, inputs are truncated to the
+	// 512 tokens the models were trained with. Clients are expected to add the
+	// "query: " and "passage: " prefixes the models were trained with.
+	e5RunParamsVLLM = map[string]string{
+		"task":          "embed",
+		"dtype":         "float16",
+		"max-model-len": "512",
+	}
+)
+
+var e5LargeV2A e5LargeV2
+
+type e5LargeV2 struct{}
+
+func (*e5LargeV2) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Metadata:                  metadata.MustGet(PresetE5LargeV2Model),
+		DiskStorageRequirement:    "60Gi",
+		GPUCountRequirement:       "1",
+		TotalGPUMemoryRequirement: "4Gi",
+		PerGPUMemoryRequirement:   "0Gi", // The model fits on a single GPU, no per GPU memory requirement.
+		EmbeddingDimension:        1024,
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelName:      PresetE5LargeV2Model,
+				ModelRunParams: e5RunParamsVLLM,
+				DisallowLoRA:   true,
+			},
+			// The model is too small to be split across GPUs.
+			DisableTensorParallelism: true,
+		},
+		ReadinessTimeout: time.Duration(30) * time.Minute,
+	}
+}
+
+func (*e5LargeV2) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (*e5LargeV2) SupportDistributedInference() bool {
+	return false
+}
+
+func (*e5LargeV2) SupportTuning() bool {
+	return false
+}
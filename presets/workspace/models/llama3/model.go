@@ -80,6 +80,14 @@ func (*llama3_1_8BInstruct) GetInferenceParameters() *model.PresetParam {
 				RayLeaderBaseCommand: inference.DefaultVLLMRayLeaderBaseCommand,
 				RayWorkerBaseCommand: inference.DefaultVLLMRayWorkerBaseCommand,
 			},
+			SGLang: model.SGLangParam{
+				BaseCommand: inference.DefaultSGLangCommand,
+				ModelName:   PresetLlama3_1_8BInstructModel,
+			},
+			TensorRTLLM: model.TensorRTLLMParam{
+				BaseCommand:        inference.DefaultTensorRTLLMCommand,
+				EngineBuildCommand: inference.DefaultTensorRTLLMEngineBuildCommand,
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -21,6 +21,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 )
 
 var (
@@ -61,3 +62,22 @@ func MustGet(name string) model.Metadata {
 
 	return *(m.(*model.Metadata))
 }
+
+// MustGetBase retrieves the metadata of the base runtime image for the
+// given GPU vendor. AMD GPUs use the ROCm build of the base image.
+func MustGetBase(vendor sku.GPUVendor) model.Metadata {
+	if vendor == sku.GPUVendorAMD {
+		return MustGet("base-rocm")
+	}
+	return MustGet("base")
+}
+
+// MustGetSGLangBase retrieves the metadata of the base runtime image of the SGLang runtime.
+func MustGetSGLangBase() model.Metadata {
+	return MustGet("base-sglang")
+}
+
+// MustGetTensorRTLLMBase retrieves the metadata of the base runtime image of the TensorRT-LLM runtime.
+func MustGetTensorRTLLMBase() model.Metadata {
+	return MustGet("base-tensorrt-llm")
+}
@@ -12,3 +12,26 @@
 // limitations under the License.
 
 package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/kaito-project/kaito/pkg/utils"
+)
+
+func TestSupportedModelsArePinned(t *testing.T) {
+	catalog := Catalog{}
+	assert.NoError(t, yaml.Unmarshal(supportedModelsYAML, &catalog))
+
+	for _, m := range catalog.Models {
+		if m.Version == "" {
+			continue
+		}
+		_, revision, err := utils.ParseHuggingFaceModelVersion(m.Version)
+		assert.NoError(t, err, m.Name)
+		assert.NotEmpty(t, revision, "the version of %s must pin a commit", m.Name)
+	}
+}
@@ -130,6 +130,14 @@ func (*mistral7bInst) GetInferenceParameters() *model.PresetParam {
 				ModelName:      PresetMistral7BInstructModel,
 				ModelRunParams: mistralRunParamsVLLM,
 			},
+			SGLang: model.SGLangParam{
+				BaseCommand: inference.DefaultSGLangCommand,
+				ModelName:   PresetMistral7BInstructModel,
+			},
+			TensorRTLLM: model.TensorRTLLMParam{
+				BaseCommand:        inference.DefaultTensorRTLLMCommand,
+				EngineBuildCommand: inference.DefaultTensorRTLLMEngineBuildCommand,
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -43,14 +43,19 @@ func init() {
 		Name:     PresetPhi3_5MiniInstruct,
 		Instance: &phi3_5MiniC,
 	})
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     PresetPhi3_5VisionInstruct,
+		Instance: &phi3_5VisionA,
+	})
 }
 
 const (
-	PresetPhi3Mini4kModel     = "phi-3-mini-4k-instruct"
-	PresetPhi3Mini128kModel   = "phi-3-mini-128k-instruct"
-	PresetPhi3Medium4kModel   = "phi-3-medium-4k-instruct"
-	PresetPhi3Medium128kModel = "phi-3-medium-128k-instruct"
-	PresetPhi3_5MiniInstruct  = "phi-3.5-mini-instruct"
+	PresetPhi3Mini4kModel      = "phi-3-mini-4k-instruct"
+	PresetPhi3Mini128kModel    = "phi-3-mini-128k-instruct"
+	PresetPhi3Medium4kModel    = "phi-3-medium-4k-instruct"
+	PresetPhi3Medium128kModel  = "phi-3-medium-128k-instruct"
+	PresetPhi3_5MiniInstruct   = "phi-3.5-mini-instruct"
+	PresetPhi3_5VisionInstruct = "phi-3.5-vision-instruct"
 )
 
 var (
@@ -64,6 +69,13 @@ var (
 	phiRunParamsVLLM = map[string]string{
 		"dtype": "float16",
 	}
+	// The vision encoder of phi-3.5-vision runs once per image, the number of images
+	// per prompt bounds the activation memory vLLM reserves for it.
+	phiVisionRunParamsVLLM = map[string]string{
+		"dtype":               "float16",
+		"trust-remote-code":   "",
+		"limit-mm-per-prompt": "image=4",
+	}
 )
 
 var phi3MiniA phi3Mini4KInst
@@ -89,6 +101,13 @@ func (*phi3Mini4KInst) GetInferenceParameters() *model.PresetParam {
 				ModelName:      PresetPhi3Mini4kModel,
 				ModelRunParams: phiRunParamsVLLM,
 			},
+			LlamaCpp: model.LlamaCppParam{
+				BaseCommand:  inference.DefaultLlamaCppCommand,
+				ModelName:    PresetPhi3Mini4kModel,
+				GGUFRepo:     "microsoft/Phi-3-mini-4k-instruct-gguf",
+				GGUFFile:     "Phi-3-mini-4k-instruct-q4.gguf",
+				GGUFFileSize: "2.2Gi",
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -185,6 +204,13 @@ func (*phi3_5MiniInst) GetInferenceParameters() *model.PresetParam {
 				ModelName:      PresetPhi3_5MiniInstruct,
 				ModelRunParams: phiRunParamsVLLM,
 			},
+			LlamaCpp: model.LlamaCppParam{
+				BaseCommand:  inference.DefaultLlamaCppCommand,
+				ModelName:    PresetPhi3_5MiniInstruct,
+				GGUFRepo:     "bartowski/Phi-3.5-mini-instruct-GGUF",
+				GGUFFile:     "Phi-3.5-mini-instruct-Q4_K_M.gguf",
+				GGUFFileSize: "2.3Gi",
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -211,6 +237,38 @@ func (*phi3_5MiniInst) SupportTuning() bool {
 	return true
 }
 
+var phi3_5VisionA phi3_5VisionInst
+
+type phi3_5VisionInst struct{}
+
+func (*phi3_5VisionInst) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Metadata:               metadata.MustGet(PresetPhi3_5VisionInstruct),
+		DiskStorageRequirement: "80Gi",
+		GPUCountRequirement:    "1",
+		// The language model and the CLIP vision encoder take 8.3GB, the rest is
+		// reserved for the activations of the image tokens.
+		TotalGPUMemoryRequirement: "12Gi",
+		PerGPUMemoryRequirement:   "0Gi", // We run Phi using native vertical model parallel, no per GPU memory requirement.
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelName:      PresetPhi3_5VisionInstruct,
+				ModelRunParams: phiVisionRunParamsVLLM,
+				DisallowLoRA:   true,
+			},
+		},
+		ReadinessTimeout: time.Duration(30) * time.Minute,
+	}
+}
+func (*phi3_5VisionInst) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+func (*phi3_5VisionInst) SupportDistributedInference() bool { return false }
+func (*phi3_5VisionInst) SupportTuning() bool {
+	return false
+}
+
 var phi3MediumA Phi3Medium4kInstruct
 
 type Phi3Medium4kInstruct struct{}
@@ -31,11 +31,16 @@ func init() {
 		Name:     PresetQwen2_5Coder32BInstructModel,
 		Instance: &qwen2_5coder32bInst,
 	})
+	plugin.KaitoModelRegister.Register(&plugin.Registration{
+		Name:     PresetQwen2VL7BInstructModel,
+		Instance: &qwen2vl7bInst,
+	})
 }
 
 const (
 	PresetQwen2_5Coder7BInstructModel  = "qwen2.5-coder-7b-instruct"
 	PresetQwen2_5Coder32BInstructModel = "qwen2.5-coder-32b-instruct"
+	PresetQwen2VL7BInstructModel       = "qwen2-vl-7b-instruct"
 )
 
 var (
@@ -51,6 +56,11 @@ var (
 		"tool-call-parser":        "hermes",
 		"enable-auto-tool-choice": "",
 	}
+	// Qwen2-VL encodes images at their native resolution, the number of images per
+	// prompt bounds the activation memory vLLM reserves for the vision encoder.
+	qwenVLRunParamsVLLM = map[string]string{
+		"limit-mm-per-prompt": "image=4",
+	}
 )
 
 var qwen2_5coder7bInst qwen2_5Coder7BInstruct
@@ -76,6 +86,10 @@ func (*qwen2_5Coder7BInstruct) GetInferenceParameters() *model.PresetParam {
 				ModelName:      PresetQwen2_5Coder7BInstructModel,
 				ModelRunParams: qwenRunParamsVLLM,
 			},
+			SGLang: model.SGLangParam{
+				BaseCommand: inference.DefaultSGLangCommand,
+				ModelName:   PresetQwen2_5Coder7BInstructModel,
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -129,6 +143,10 @@ func (*qwen2_5Coder32BInstruct) GetInferenceParameters() *model.PresetParam {
 				ModelName:      PresetQwen2_5Coder32BInstructModel,
 				ModelRunParams: qwenRunParamsVLLM,
 			},
+			SGLang: model.SGLangParam{
+				BaseCommand: inference.DefaultSGLangCommand,
+				ModelName:   PresetQwen2_5Coder32BInstructModel,
+			},
 		},
 		ReadinessTimeout: time.Duration(30) * time.Minute,
 	}
@@ -156,3 +174,40 @@ func (*qwen2_5Coder32BInstruct) SupportDistributedInference() bool {
 func (*qwen2_5Coder32BInstruct) SupportTuning() bool {
 	return true
 }
+
+var qwen2vl7bInst qwen2VL7BInstruct
+
+type qwen2VL7BInstruct struct{}
+
+func (*qwen2VL7BInstruct) GetInferenceParameters() *model.PresetParam {
+	return &model.PresetParam{
+		Metadata:               metadata.MustGet(PresetQwen2VL7BInstructModel),
+		DiskStorageRequirement: "110Gi",
+		GPUCountRequirement:    "1",
+		// The language model and the ViT vision encoder take 16.6GB, the rest is
+		// reserved for the activations of the image tokens.
+		TotalGPUMemoryRequirement: "24Gi",
+		PerGPUMemoryRequirement:   "0Gi", // We run qwen using native vertical model parallel, no per GPU memory requirement.
+		RuntimeParam: model.RuntimeParam{
+			VLLM: model.VLLMParam{
+				BaseCommand:    inference.DefaultVLLMCommand,
+				ModelName:      PresetQwen2VL7BInstructModel,
+				ModelRunParams: qwenVLRunParamsVLLM,
+				DisallowLoRA:   true,
+			},
+		},
+		ReadinessTimeout: time.Duration(30) * time.Minute,
+	}
+}
+
+func (*qwen2VL7BInstruct) GetTuningParameters() *model.PresetParam {
+	return nil
+}
+
+func (*qwen2VL7BInstruct) SupportDistributedInference() bool {
+	return false
+}
+
+func (*qwen2VL7BInstruct) SupportTuning() bool {
+	return false
+}
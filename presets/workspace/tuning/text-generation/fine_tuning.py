@@ -25,6 +25,7 @@ from peft import LoraConfig, get_peft_model, prepare_model_for_kbit_training
 from transformers import (AutoModelForCausalLM, AutoTokenizer,
                           BitsAndBytesConfig, TrainingArguments,
                           TrainerCallback, TrainerControl, TrainerState)
+from transformers.trainer_utils import get_last_checkpoint
 from trl import SFTTrainer
 
 # Initialize logger
@@ -134,7 +135,13 @@ trainer: SFTTrainer = accelerator.prepare(SFTTrainer(
     callbacks=[empty_cache_callback]
     # metrics = "tensorboard" or "wandb" # TODO
 ))
-trainer.train()
+# Resume from the last checkpoint if the previous run was interrupted, e.g. by a spot node eviction
+last_checkpoint = None
+if os.environ.get('RESUME_FROM_CHECKPOINT', 'false').lower() == 'true' and os.path.isdir(ta_args.output_dir):
+    last_checkpoint = get_last_checkpoint(ta_args.output_dir)
+    if last_checkpoint:
+        logger.info(f"Resuming training from checkpoint {last_checkpoint}")
+trainer.train(resume_from_checkpoint=last_checkpoint)
 os.makedirs(ta_args.output_dir, exist_ok=True)
 # only save the adapter weights
 trainer.model.save_pretrained(ta_args.output_dir)